@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+// backfillBalanceAfter computes the running balance of transactions created
+// before it was stored. It's safe to run while the API is serving traffic.
+func backfillBalanceAfter(database *bun.DB) int {
+	ledgerRepository := repository.NewLedgerRepository(database)
+
+	transactionsService := transactions.NewService(
+		repository.NewTransactionRepository(database),
+		repository.NewIdempotencyRecordRepository(database),
+		repository.NewCustomerAccountRepository(database),
+		repository.NewBalanceRepository(database),
+		ledgerRepository,
+		repository.NewOutboxRepository(database),
+		repository.NewAuditLogRepository(database),
+		operationtypes.NewService(repository.NewOperationTypeRepository(database), ledgerRepository),
+		transactions.Options{},
+	)
+
+	result, err := transactionsService.BackfillBalanceAfter(context.Background())
+	if err != nil {
+		log.Err(err).Msg("failed to backfill balance after")
+
+		return 1
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(transactions.DomainToBackfillBalanceAfterResponse(result)); err != nil {
+		log.Err(err).Msg("failed to write backfill result")
+
+		return 1
+	}
+
+	return 0
+}
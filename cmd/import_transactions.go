@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/imports"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/audit"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+// importTransactions imports a CSV or OFX file and prints the report as JSON.
+// The exit code is 2 when rows were invalid or failed, like reconcile.
+func importTransactions(database *bun.DB, args []string) int {
+	defaultMapping := imports.DefaultCSVColumnMapping()
+
+	flags := flag.NewFlagSet(importCommand, flag.ContinueOnError)
+	filePath := flags.String("file", "", "path of the CSV or OFX file to import")
+	format := flags.String("format", "", "format of the file (csv or ofx), guessed from the extension by default")
+	dryRun := flags.Bool("dry-run", false, "only validate the rows, without posting them")
+	accountIDColumn := flags.String("account-id-column", defaultMapping.AccountID, "CSV column holding the account id")
+	operationTypeColumn := flags.String("operation-type-column", defaultMapping.OperationType, "CSV column holding the operation type")
+	amountColumn := flags.String("amount-column", defaultMapping.Amount, "CSV column holding the amount")
+	delimiter := flags.String("delimiter", "", "CSV field delimiter, a comma by default")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	content, err := os.ReadFile(*filePath)
+	if err != nil {
+		log.Err(err).Str("file", *filePath).Msg("failed to read import file")
+
+		return 1
+	}
+
+	request := imports.ImportRequest{
+		FileName: filepath.Base(*filePath),
+		Format:   models.ImportFormat(*format),
+		Content:  content,
+		DryRun:   *dryRun,
+		CSVMapping: imports.CSVColumnMapping{
+			AccountID:     *accountIDColumn,
+			OperationType: *operationTypeColumn,
+			Amount:        *amountColumn,
+		},
+		CSVDelimiter: *delimiter,
+	}
+
+	if request.Format == "" {
+		request.Format = imports.FormatFromFileName(request.FileName)
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		log.Error().Interface("field_errors", err.FieldErrors).Msg("invalid import options")
+
+		return 1
+	}
+
+	// Transactions imported from the command line are audited as made by
+	// the user running it.
+	ctx := audit.WithRequest(context.Background(), &audit.Request{
+		Actor: os.Getenv("USER"),
+		Route: importCommand,
+	})
+	ledgerRepository := repository.NewLedgerRepository(database)
+	customerAccountRepository := repository.NewCustomerAccountRepository(database)
+
+	operationTypesService := operationtypes.NewService(repository.NewOperationTypeRepository(database), ledgerRepository)
+	if err := operationTypesService.Load(ctx); err != nil {
+		log.Err(err).Msg("failed to load operation types")
+
+		return 1
+	}
+
+	importsService := imports.NewService(
+		repository.NewImportFileRepository(database),
+		customerAccountRepository,
+		transactions.NewService(
+			repository.NewTransactionRepository(database),
+			repository.NewIdempotencyRecordRepository(database),
+			customerAccountRepository,
+			repository.NewBalanceRepository(database),
+			ledgerRepository,
+			repository.NewOutboxRepository(database),
+			repository.NewAuditLogRepository(database),
+			operationTypesService,
+			transactions.Options{},
+		),
+		operationTypesService,
+	)
+
+	result, err := importsService.ImportTransactions(ctx, request)
+	if err != nil {
+		log.Err(err).Str("file", *filePath).Msg("failed to import transactions")
+
+		return 1
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(imports.DomainToImportResponse(result)); err != nil {
+		log.Err(err).Msg("failed to write import report")
+
+		return 1
+	}
+
+	if result.FailedRows > 0 {
+		return 2
+	}
+
+	return 0
+}
@@ -1,34 +1,52 @@
 package main
 
 import (
+	"os"
+
+	"github.com/rs/zerolog/log"
 	"github.com/tiagovaldrich/accounts-api/db"
-	"github.com/tiagovaldrich/accounts-api/internal/api/accounts"
-	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
 	"github.com/tiagovaldrich/accounts-api/internal/config"
-	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	serveCommand                string = "serve"
+	reconcileCommand            string = "reconcile"
+	backfillBalanceAfterCommand string = "backfill-balance-after"
+	importCommand               string = "import"
+	rebuildBalancesCommand      string = "rebuild-balances"
 )
 
 func main() {
 	cfg := config.MustLoad()
-	appRouter := config.NewRouter()
+
+	command := serveCommand
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
 
 	database := db.NewDatabase(&cfg.EnvVars.Database)
-	//nolint: errcheck
-	defer database.Close()
 
 	db.RunMigrations(database, nil)
 
-	customerRepository := repository.NewCustomerRepository(database)
-	customerAccountRepository := repository.NewCustomerAccountRepository(database)
-	balanceRepository := repository.NewBalanceRepository(database)
-	transactionRepository := repository.NewTransactionRepository(database)
-
-	accountsService := accounts.NewService(customerRepository, customerAccountRepository, balanceRepository)
-	transactionsService := transactions.NewService(transactionRepository, customerAccountRepository, balanceRepository)
-
-	accounts.NewHTTPHandler(appRouter.GetApp(), accountsService)
-	transactions.NewHTTPHandler(appRouter.GetApp(), transactionsService)
+	var exitCode int
+	switch command {
+	case serveCommand:
+		serve(cfg, database)
+	case reconcileCommand:
+		exitCode = reconcile(database, os.Args[2:])
+	case backfillBalanceAfterCommand:
+		exitCode = backfillBalanceAfter(database)
+	case importCommand:
+		exitCode = importTransactions(database, os.Args[2:])
+	case rebuildBalancesCommand:
+		exitCode = rebuildBalances(database, os.Args[2:])
+	default:
+		log.Error().Str("command", command).Msg("unknown command")
+		exitCode = 1
+	}
 
 	//nolint: errcheck
-	appRouter.Start()
+	database.Close()
+
+	os.Exit(exitCode)
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+// rebuildBalances recomputes the balances from the transactions and prints
+// the differences as JSON. Without --apply nothing is changed and the exit
+// code is 2 when differences were found, like reconcile.
+func rebuildBalances(database *bun.DB, args []string) int {
+	flags := flag.NewFlagSet(rebuildBalancesCommand, flag.ContinueOnError)
+	apply := flags.Bool("apply", false, "correct the differences with audited adjustments")
+	reason := flags.String("reason", "", "why the balances are being rebuilt, required with --apply")
+	operator := flags.String("operator", os.Getenv("USER"), "who is rebuilding the balances")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	request := reconciliation.RebuildBalancesRequest{
+		Apply:    *apply,
+		Operator: *operator,
+		Reason:   *reason,
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		log.Error().Interface("field_errors", err.FieldErrors).Msg("invalid rebuild options")
+
+		return 1
+	}
+
+	reconciliationService := reconciliation.NewService(
+		repository.NewReconciliationRepository(database),
+		repository.NewCustomerAccountRepository(database),
+		repository.NewBalanceRepository(database),
+		repository.NewOutboxRepository(database),
+	)
+
+	result, err := reconciliationService.RebuildBalances(context.Background(), request)
+	if err != nil {
+		log.Err(err).Msg("failed to rebuild balances")
+
+		return 1
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(reconciliation.DomainToRebuildResponse(result)); err != nil {
+		log.Err(err).Msg("failed to write rebuild report")
+
+		return 1
+	}
+
+	if !result.Applied && len(result.Diffs) > 0 {
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+// reconcile runs a single reconciliation and prints the report as JSON.
+// The exit code is 2 when drifts were found so it can gate cron jobs and pipelines.
+func reconcile(database *bun.DB, args []string) int {
+	flags := flag.NewFlagSet(reconcileCommand, flag.ContinueOnError)
+	blockAccounts := flags.Bool("block", false, "block accounts with drifts until they are resolved")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	reconciliationService := reconciliation.NewService(
+		repository.NewReconciliationRepository(database),
+		repository.NewCustomerAccountRepository(database),
+		repository.NewBalanceRepository(database),
+		repository.NewOutboxRepository(database),
+	)
+
+	report, err := reconciliationService.Reconcile(context.Background(), reconciliation.ReconcileRequest{
+		BlockAccounts: *blockAccounts,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to reconcile balances")
+
+		return 1
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(reconciliation.DomainToReportResponse(report)); err != nil {
+		log.Err(err).Msg("failed to write reconciliation report")
+
+		return 1
+	}
+
+	if len(report.Drifts) > 0 {
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+
+	"github.com/tiagovaldrich/accounts-api/internal/api/accounts"
+	"github.com/tiagovaldrich/accounts-api/internal/api/activity"
+	"github.com/tiagovaldrich/accounts-api/internal/api/auditlogs"
+	"github.com/tiagovaldrich/accounts-api/internal/api/imports"
+	"github.com/tiagovaldrich/accounts-api/internal/api/ledger"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+	"github.com/tiagovaldrich/accounts-api/internal/api/schedules"
+	"github.com/tiagovaldrich/accounts-api/internal/api/statements"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/api/webhooks"
+	"github.com/tiagovaldrich/accounts-api/internal/config"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/jobs"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+func serve(cfg *config.AppConfig, database *bun.DB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	customerRepository := repository.NewCustomerRepository(database)
+	customerAccountRepository := repository.NewCustomerAccountRepository(database)
+	balanceRepository := repository.NewBalanceRepository(database)
+	balanceSnapshotRepository := repository.NewBalanceSnapshotRepository(database)
+	transactionRepository := repository.NewTransactionRepository(database)
+	idempotencyRecordRepository := repository.NewIdempotencyRecordRepository(database)
+	idempotentRequestRepository := repository.NewIdempotentRequestRepository(database)
+	ledgerRepository := repository.NewLedgerRepository(database)
+	reconciliationRepository := repository.NewReconciliationRepository(database)
+	operationTypeRepository := repository.NewOperationTypeRepository(database)
+	importFileRepository := repository.NewImportFileRepository(database)
+	outboxRepository := repository.NewOutboxRepository(database)
+	webhookRepository := repository.NewWebhookRepository(database)
+	auditLogRepository := repository.NewAuditLogRepository(database)
+	transactionScheduleRepository := repository.NewTransactionScheduleRepository(database)
+
+	appRouter := config.NewRouter(idempotentRequestRepository, auditLogRepository, cfg.EnvVars.HTTP)
+
+	if cfg.EnvVars.HTTP.MetricsEnabled {
+		//nolint: errcheck
+		go config.NewMetricsRouter(cfg.EnvVars.HTTP).Start()
+	}
+
+	operationTypesService := operationtypes.NewService(operationTypeRepository, ledgerRepository)
+	if err := operationTypesService.Load(ctx); err != nil {
+		panic(err)
+	}
+
+	eventPublisher, err := events.NewPublisherFromConfig(cfg.EnvVars.Events)
+	if err != nil {
+		panic(err)
+	}
+	//nolint: errcheck
+	defer events.ClosePublisher(eventPublisher)
+
+	notifier := events.NewNotifier(database)
+	if err := notifier.Start(ctx); err != nil {
+		panic(err)
+	}
+
+	accountsService := accounts.NewService(
+		customerRepository,
+		customerAccountRepository,
+		balanceRepository,
+		balanceSnapshotRepository,
+		outboxRepository,
+		auditLogRepository,
+	)
+	transactionsService := transactions.NewService(
+		transactionRepository,
+		idempotencyRecordRepository,
+		customerAccountRepository,
+		balanceRepository,
+		ledgerRepository,
+		outboxRepository,
+		auditLogRepository,
+		operationTypesService,
+		newTransactionsOptions(cfg),
+	)
+	ledgerService := ledger.NewService(ledgerRepository)
+	auditLogsService := auditlogs.NewService(auditLogRepository)
+	reconciliationService := reconciliation.NewService(
+		reconciliationRepository,
+		customerAccountRepository,
+		balanceRepository,
+		outboxRepository,
+	)
+	importsService := imports.NewService(
+		importFileRepository,
+		customerAccountRepository,
+		transactionsService,
+		operationTypesService,
+	)
+	activityService := activity.NewService(customerAccountRepository, outboxRepository, notifier)
+	webhooksService := webhooks.NewService(webhookRepository, webhooks.Options{
+		MaxAttempts: cfg.EnvVars.Webhooks.MaxAttempts,
+		Timeout:     cfg.EnvVars.Webhooks.Timeout,
+		BatchSize:   cfg.EnvVars.Webhooks.DeliveryBatchSize,
+	})
+	schedulesService := schedules.NewService(
+		transactionScheduleRepository,
+		customerAccountRepository,
+		transactionsService,
+		operationTypesService,
+		newSchedulesOptions(cfg),
+	)
+	statementsService := statements.NewService(
+		transactionRepository,
+		balanceSnapshotRepository,
+		customerAccountRepository,
+		operationTypesService,
+		newStatementsOptions(cfg),
+	)
+
+	accounts.NewHTTPHandler(appRouter.GetApp(), accountsService)
+	transactions.NewHTTPHandler(appRouter.GetApp(), transactionsService)
+	ledger.NewHTTPHandler(appRouter.GetApp(), ledgerService)
+	reconciliation.NewHTTPHandler(appRouter.GetApp(), reconciliationService)
+	operationtypes.NewHTTPHandler(appRouter.GetApp(), operationTypesService)
+	imports.NewHTTPHandler(appRouter.GetApp(), importsService)
+	webhooks.NewHTTPHandler(appRouter.GetApp(), webhooksService)
+	activity.NewHTTPHandler(appRouter.GetApp(), activityService)
+	auditlogs.NewHTTPHandler(appRouter.GetApp(), auditLogsService)
+	schedules.NewHTTPHandler(appRouter.GetApp(), schedulesService)
+	statements.NewHTTPHandler(appRouter.GetApp(), statementsService)
+
+	jobs.NewScheduler(newJobs(
+		cfg,
+		balanceSnapshotRepository,
+		idempotencyRecordRepository,
+		idempotentRequestRepository,
+		outboxRepository,
+		reconciliationService,
+		operationTypesService,
+		webhooksService,
+		schedulesService,
+		eventPublisher,
+	)...).Start(ctx)
+
+	//nolint: errcheck
+	appRouter.Start()
+}
+
+func newTransactionsOptions(cfg *config.AppConfig) transactions.Options {
+	balanceLockStrategy, err := transactions.ParseBalanceLockStrategy(cfg.EnvVars.Transactions.BalanceLockStrategy)
+	if err != nil {
+		panic(err)
+	}
+
+	options := transactions.Options{
+		BalanceLockStrategy:  balanceLockStrategy,
+		OptimisticMaxRetries: cfg.EnvVars.Transactions.BalanceOptimisticMaxRetries,
+	}
+
+	if cfg.EnvVars.Transactions.AccountQueueEnabled {
+		options.AccountQueueSize = cfg.EnvVars.Transactions.AccountQueueSize
+		options.AccountQueueMaxBatch = cfg.EnvVars.Transactions.AccountQueueMaxBatch
+	}
+
+	return options
+}
+
+func newSchedulesOptions(cfg *config.AppConfig) schedules.Options {
+	location, err := cfg.EnvVars.Schedules.Location()
+	if err != nil {
+		panic(err)
+	}
+
+	return schedules.Options{
+		BatchSize:     cfg.EnvVars.Schedules.BatchSize,
+		MaxAttempts:   cfg.EnvVars.Schedules.MaxAttempts,
+		RetryInterval: cfg.EnvVars.Schedules.RetryInterval,
+		Location:      location,
+	}
+}
+
+func newStatementsOptions(cfg *config.AppConfig) statements.Options {
+	location, err := cfg.EnvVars.Statements.Location()
+	if err != nil {
+		panic(err)
+	}
+
+	return statements.Options{
+		Location: location,
+		BankID:   cfg.EnvVars.Statements.BankID,
+		Currency: cfg.EnvVars.Statements.Currency,
+	}
+}
+
+func newJobs(
+	cfg *config.AppConfig,
+	balanceSnapshotRepository repository.BalanceSnapshotRepository,
+	idempotencyRecordRepository repository.IdempotencyRecordRepository,
+	idempotentRequestRepository repository.IdempotentRequestRepository,
+	outboxRepository repository.OutboxRepository,
+	reconciliationService reconciliation.Servicer,
+	operationTypesService operationtypes.Servicer,
+	webhooksService webhooks.Servicer,
+	schedulesService schedules.Servicer,
+	eventPublisher events.Publisher,
+) []jobs.Job {
+	enabledJobs := []jobs.Job{
+		jobs.NewOperationTypesRefreshJob(operationTypesService, cfg.EnvVars.Jobs.OperationTypesRefreshInterval),
+	}
+
+	if cfg.EnvVars.Jobs.BalanceSnapshotEnabled {
+		location, err := cfg.EnvVars.Jobs.BalanceSnapshotLocation()
+		if err != nil {
+			panic(err)
+		}
+
+		enabledJobs = append(enabledJobs, jobs.NewBalanceSnapshotJob(
+			balanceSnapshotRepository,
+			cfg.EnvVars.Jobs.BalanceSnapshotInterval,
+			location,
+		))
+	}
+
+	if cfg.EnvVars.Jobs.ReconciliationEnabled {
+		enabledJobs = append(enabledJobs, jobs.NewReconciliationJob(
+			reconciliationService,
+			cfg.EnvVars.Jobs.ReconciliationInterval,
+			cfg.EnvVars.Jobs.ReconciliationBlockAccounts,
+		))
+	}
+
+	if cfg.EnvVars.Jobs.IdempotencyKeysCleanupEnabled {
+		enabledJobs = append(enabledJobs, jobs.NewIdempotencyKeysCleanupJob(
+			idempotencyRecordRepository,
+			idempotentRequestRepository,
+			cfg.EnvVars.Jobs.IdempotencyKeysCleanupInterval,
+			cfg.EnvVars.Jobs.IdempotencyKeyRetention,
+		))
+	}
+
+	if cfg.EnvVars.Jobs.OutboxDispatcherEnabled {
+		enabledJobs = append(enabledJobs, jobs.NewOutboxDispatcherJob(
+			events.NewDispatcher(
+				outboxRepository,
+				events.NewMultiPublisher(eventPublisher, webhooksService),
+				cfg.EnvVars.Jobs.OutboxBatchSize,
+				cfg.EnvVars.Jobs.OutboxMaxAttempts,
+			),
+			cfg.EnvVars.Jobs.OutboxDispatcherInterval,
+		))
+	}
+
+	if cfg.EnvVars.Webhooks.DeliveryEnabled {
+		enabledJobs = append(enabledJobs, jobs.NewWebhookDeliveryJob(
+			webhooksService,
+			cfg.EnvVars.Webhooks.DeliveryInterval,
+		))
+	}
+
+	if cfg.EnvVars.Schedules.Enabled {
+		enabledJobs = append(enabledJobs, jobs.NewScheduledTransactionsJob(
+			schedulesService,
+			cfg.EnvVars.Schedules.Interval,
+		))
+	}
+
+	return enabledJobs
+}
@@ -0,0 +1,108 @@
+-- +migrate Up
+CREATE TABLE ledger_account (
+    id UUID PRIMARY KEY,
+    code VARCHAR(255) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT ledger_account_code_unique UNIQUE (code)
+);
+
+INSERT INTO ledger_account (id, code, description) VALUES
+    (gen_random_uuid(), 'settlement', 'Settlement account for purchases and withdrawals'),
+    (gen_random_uuid(), 'fees_revenue', 'Revenue from fees charged to customers'),
+    (gen_random_uuid(), 'voucher_funding', 'Funding source for credit vouchers');
+
+CREATE TABLE journal_entry (
+    id UUID PRIMARY KEY,
+    transaction_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT journal_entry_transaction_id_fk FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    CONSTRAINT journal_entry_transaction_id_unique UNIQUE (transaction_id)
+);
+
+-- A posting moves money either into a customer account or into an internal
+-- ledger account, never both.
+CREATE TABLE journal_posting (
+    id UUID PRIMARY KEY,
+    journal_entry_id UUID NOT NULL,
+    customer_account_id UUID,
+    ledger_account_id UUID,
+    amount BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT journal_posting_journal_entry_id_fk FOREIGN KEY (journal_entry_id) REFERENCES journal_entry(id),
+    CONSTRAINT journal_posting_customer_account_id_fk FOREIGN KEY (customer_account_id) REFERENCES customer_account(id),
+    CONSTRAINT journal_posting_ledger_account_id_fk FOREIGN KEY (ledger_account_id) REFERENCES ledger_account(id),
+    CONSTRAINT journal_posting_single_account_check CHECK (num_nonnulls(customer_account_id, ledger_account_id) = 1),
+    CONSTRAINT journal_posting_amount_not_zero_check CHECK (amount <> 0)
+);
+
+CREATE INDEX idx_journal_posting_journal_entry_id ON journal_posting(journal_entry_id);
+CREATE INDEX idx_journal_posting_customer_account_id ON journal_posting(customer_account_id);
+CREATE INDEX idx_journal_posting_ledger_account_id ON journal_posting(ledger_account_id);
+
+-- +migrate StatementBegin
+CREATE FUNCTION check_journal_entry_balanced() RETURNS TRIGGER AS $$
+DECLARE
+    entry_id UUID;
+    entry_total BIGINT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        entry_id := OLD.journal_entry_id;
+    ELSE
+        entry_id := NEW.journal_entry_id;
+    END IF;
+
+    SELECT COALESCE(SUM(amount), 0) INTO entry_total
+    FROM journal_posting
+    WHERE journal_entry_id = entry_id;
+
+    IF entry_total <> 0 THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced, postings sum to %', entry_id, entry_total
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- Deferred so every posting of an entry can be inserted before the sum is checked at commit.
+CREATE CONSTRAINT TRIGGER journal_posting_balanced_check
+    AFTER INSERT OR UPDATE OR DELETE ON journal_posting
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
+
+-- Backfill the journal for transactions created before the ledger existed.
+-- Amounts below one cent used to be accepted and stored as 0; those
+-- transactions moved no money, so they get no entry, as a posting can't be 0.
+INSERT INTO journal_entry (id, transaction_id, created_at)
+SELECT gen_random_uuid(), t.id, t.created_at
+FROM transactions t
+WHERE t.amount <> 0;
+
+INSERT INTO journal_posting (id, journal_entry_id, customer_account_id, amount, created_at)
+SELECT gen_random_uuid(), je.id, t.customer_account_id, t.amount, t.created_at
+FROM journal_entry je
+JOIN transactions t ON t.id = je.transaction_id
+WHERE t.amount <> 0;
+
+INSERT INTO journal_posting (id, journal_entry_id, ledger_account_id, amount, created_at)
+SELECT gen_random_uuid(), je.id, la.id, -t.amount, t.created_at
+FROM journal_entry je
+JOIN transactions t ON t.id = je.transaction_id
+JOIN ledger_account la ON la.code = CASE
+    WHEN t.operation_type = 'credit_voucher' THEN 'voucher_funding'
+    ELSE 'settlement'
+END
+WHERE t.amount <> 0;
+
+-- +migrate Down
+DROP TRIGGER journal_posting_balanced_check ON journal_posting;
+DROP FUNCTION check_journal_entry_balanced();
+DROP TABLE journal_posting;
+DROP TABLE journal_entry;
+DROP TABLE ledger_account;
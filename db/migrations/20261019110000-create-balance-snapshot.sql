@@ -0,0 +1,18 @@
+
+-- +migrate Up
+CREATE TABLE balance_snapshot (
+    id UUID PRIMARY KEY,
+    customer_account_id UUID NOT NULL,
+    balance BIGINT NOT NULL,
+    snapshot_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT balance_snapshot_customer_account_id_fk FOREIGN KEY (customer_account_id) REFERENCES customer_account(id),
+    CONSTRAINT balance_snapshot_customer_account_id_snapshot_at_unique UNIQUE (customer_account_id, snapshot_at)
+);
+
+CREATE INDEX idx_transactions_customer_account_id_created_at ON transactions(customer_account_id, created_at);
+
+-- +migrate Down
+DROP INDEX idx_transactions_customer_account_id_created_at;
+DROP TABLE balance_snapshot;
@@ -0,0 +1,45 @@
+
+-- +migrate Up
+CREATE TYPE reconciliation_drift_status AS ENUM (
+    'open',
+    'resolved'
+);
+
+ALTER TABLE customer_account ADD COLUMN blocked_at TIMESTAMPTZ;
+
+CREATE TABLE reconciliation_run (
+    id UUID PRIMARY KEY,
+    accounts_checked INTEGER NOT NULL DEFAULT 0,
+    drifts_found INTEGER NOT NULL DEFAULT 0,
+    block_accounts BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE reconciliation_drift (
+    id UUID PRIMARY KEY,
+    reconciliation_run_id UUID NOT NULL,
+    customer_account_id UUID NOT NULL,
+    balance BIGINT NOT NULL,
+    transactions_total BIGINT NOT NULL,
+    drift BIGINT NOT NULL,
+    status reconciliation_drift_status NOT NULL DEFAULT 'open',
+    account_blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT reconciliation_drift_reconciliation_run_id_fk FOREIGN KEY (reconciliation_run_id) REFERENCES reconciliation_run(id),
+    CONSTRAINT reconciliation_drift_customer_account_id_fk FOREIGN KEY (customer_account_id) REFERENCES customer_account(id)
+);
+
+CREATE INDEX idx_reconciliation_drift_customer_account_id ON reconciliation_drift(customer_account_id);
+CREATE INDEX idx_reconciliation_drift_status ON reconciliation_drift(status);
+
+-- +migrate Down
+DROP TABLE reconciliation_drift;
+DROP TABLE reconciliation_run;
+ALTER TABLE customer_account DROP COLUMN blocked_at;
+DROP TYPE reconciliation_drift_status;
@@ -0,0 +1,7 @@
+
+-- +migrate Up
+-- Nullable until the backfill-balance-after command has filled the existing history.
+ALTER TABLE transactions ADD COLUMN balance_after BIGINT;
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN balance_after;
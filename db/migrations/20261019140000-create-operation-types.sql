@@ -0,0 +1,49 @@
+
+-- +migrate Up
+CREATE TYPE operation_direction AS ENUM (
+    'credit',
+    'debit'
+);
+
+CREATE TABLE operation_types (
+    id UUID PRIMARY KEY,
+    code VARCHAR(64) NOT NULL,
+    direction operation_direction NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    min_amount BIGINT,
+    max_amount BIGINT,
+    counterparty_ledger_account VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT operation_types_code_unique UNIQUE (code),
+    CONSTRAINT operation_types_counterparty_ledger_account_fk FOREIGN KEY (counterparty_ledger_account) REFERENCES ledger_account(code),
+    CONSTRAINT operation_types_min_amount_check CHECK (min_amount IS NULL OR min_amount > 0),
+    CONSTRAINT operation_types_amount_limits_check CHECK (min_amount IS NULL OR max_amount IS NULL OR max_amount >= min_amount)
+);
+
+INSERT INTO operation_types (id, code, direction, description, counterparty_ledger_account) VALUES
+    (gen_random_uuid(), 'normal_purchase', 'debit', 'Regular purchase', 'settlement'),
+    (gen_random_uuid(), 'installment_purchase', 'debit', 'Purchase with installments', 'settlement'),
+    (gen_random_uuid(), 'withdrawal', 'debit', 'Cash withdrawal', 'settlement'),
+    (gen_random_uuid(), 'credit_voucher', 'credit', 'Credit voucher', 'voucher_funding');
+
+ALTER TABLE transactions ALTER COLUMN operation_type TYPE VARCHAR(64) USING operation_type::text;
+ALTER TABLE transactions ADD CONSTRAINT transactions_operation_type_fk FOREIGN KEY (operation_type) REFERENCES operation_types(code);
+
+DROP TYPE transaction_operation_type;
+
+-- +migrate Down
+CREATE TYPE transaction_operation_type AS ENUM (
+    'normal_purchase',
+    'installment_purchase',
+    'withdrawal',
+    'credit_voucher'
+);
+
+ALTER TABLE transactions DROP CONSTRAINT transactions_operation_type_fk;
+ALTER TABLE transactions ALTER COLUMN operation_type TYPE transaction_operation_type USING operation_type::transaction_operation_type;
+
+DROP TABLE operation_types;
+DROP TYPE operation_direction;
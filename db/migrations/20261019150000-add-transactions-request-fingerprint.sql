@@ -0,0 +1,14 @@
+-- +migrate Up
+-- SHA-256 of the payload that created the transaction, used to tell an idempotent
+-- retry apart from a different request reusing the same idempotency key.
+ALTER TABLE transactions ADD COLUMN request_fingerprint VARCHAR(64);
+
+UPDATE transactions
+SET request_fingerprint = encode(
+    sha256(convert_to(customer_account_id::text || ':' || operation_type || ':' || abs(amount)::text, 'UTF8')),
+    'hex'
+)
+WHERE idempotency_key IS NOT NULL;
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN request_fingerprint;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- The scoped index is built before the global constraint is dropped, so there is
+-- no moment in which a key can be inserted twice for the same account.
+CREATE UNIQUE INDEX transactions_customer_account_idempotency_key_unique
+    ON transactions (customer_account_id, idempotency_key);
+
+ALTER TABLE transactions DROP CONSTRAINT transactions_idempotency_key_unique;
+
+-- Supports the cleanup job that purges keys older than the retention window.
+CREATE INDEX idx_transactions_idempotency_key_created_at
+    ON transactions (created_at)
+    WHERE idempotency_key IS NOT NULL;
+
+-- +migrate Down
+-- Fails if two accounts already share a key, which the scoped index allows.
+ALTER TABLE transactions ADD CONSTRAINT transactions_idempotency_key_unique UNIQUE (idempotency_key);
+
+DROP INDEX idx_transactions_idempotency_key_created_at;
+DROP INDEX transactions_customer_account_idempotency_key_unique;
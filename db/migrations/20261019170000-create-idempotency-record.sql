@@ -0,0 +1,33 @@
+-- +migrate Up
+-- Idempotency keys are claimed here at the start of the database transaction that
+-- creates the financial transaction. A concurrent request with the same key blocks
+-- on the primary key until the first one commits or rolls back.
+CREATE TABLE idempotency_record (
+    customer_account_id UUID NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_fingerprint VARCHAR(64) NOT NULL,
+    transaction_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT idempotency_record_pk PRIMARY KEY (customer_account_id, idempotency_key),
+    CONSTRAINT idempotency_record_customer_account_id_fk FOREIGN KEY (customer_account_id) REFERENCES customer_account(id),
+    CONSTRAINT idempotency_record_transaction_id_fk FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+);
+
+CREATE INDEX idx_idempotency_record_created_at ON idempotency_record (created_at);
+
+INSERT INTO idempotency_record (customer_account_id, idempotency_key, request_fingerprint, transaction_id, created_at, updated_at)
+SELECT customer_account_id, idempotency_key, request_fingerprint, id, created_at, updated_at
+FROM transactions
+WHERE idempotency_key IS NOT NULL;
+
+-- The cleanup job now scans idempotency_record instead.
+DROP INDEX idx_transactions_idempotency_key_created_at;
+
+-- +migrate Down
+CREATE INDEX idx_transactions_idempotency_key_created_at
+    ON transactions (created_at)
+    WHERE idempotency_key IS NOT NULL;
+
+DROP TABLE idempotency_record;
@@ -0,0 +1,21 @@
+-- +migrate Up
+-- Responses of mutating requests sent with the Idempotency-Key header. A row with
+-- no status_code is a claim of a request that is still being processed.
+CREATE TABLE idempotent_request (
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(2048) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_fingerprint VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    response_content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT idempotent_request_pk PRIMARY KEY (method, path, idempotency_key)
+);
+
+CREATE INDEX idx_idempotent_request_created_at ON idempotent_request (created_at);
+
+-- +migrate Down
+DROP TABLE idempotent_request;
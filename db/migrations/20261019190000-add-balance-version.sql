@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Incremented on every balance update, so the optimistic lock strategy can detect
+-- concurrent writers with UPDATE ... WHERE version = ?.
+ALTER TABLE balance ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE balance DROP COLUMN version;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Files of transactions imported through the import subsystem. The SHA-256 of
+-- the content identifies the file, so uploading it again replays this report
+-- instead of posting its rows a second time.
+CREATE TABLE import_file (
+    id UUID NOT NULL,
+    file_hash VARCHAR(64) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    total_rows INTEGER NOT NULL,
+    imported_rows INTEGER NOT NULL,
+    failed_rows INTEGER NOT NULL,
+    rows JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT import_file_pk PRIMARY KEY (id),
+    CONSTRAINT import_file_file_hash_unique UNIQUE (file_hash)
+);
+
+-- +migrate Down
+DROP TABLE import_file;
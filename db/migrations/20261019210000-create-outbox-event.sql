@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Domain events written in the same database transaction as the change they
+-- describe, and published afterwards by the outbox dispatcher. The serial id
+-- gives the order in which the events of an account are published.
+CREATE TABLE outbox_event (
+    id BIGSERIAL NOT NULL,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    schema_version INTEGER NOT NULL,
+    customer_account_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT outbox_event_pk PRIMARY KEY (id),
+    CONSTRAINT outbox_event_event_id_unique UNIQUE (event_id),
+    CONSTRAINT outbox_event_status_check CHECK (status IN ('pending', 'dispatched', 'dead_letter'))
+);
+
+CREATE INDEX idx_outbox_event_pending ON outbox_event (customer_account_id, id) WHERE status = 'pending';
+
+-- +migrate Down
+DROP TABLE outbox_event;
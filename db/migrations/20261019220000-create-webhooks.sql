@@ -0,0 +1,61 @@
+-- +migrate Up
+CREATE TABLE webhook_subscription (
+    id UUID NOT NULL,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webhook_subscription_pk PRIMARY KEY (id)
+);
+
+-- One delivery per event and subscription; the outbox publishes at least once,
+-- so enqueuing the same event again is a no-op.
+CREATE TABLE webhook_delivery (
+    id UUID NOT NULL,
+    webhook_subscription_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webhook_delivery_pk PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_subscription_fk FOREIGN KEY (webhook_subscription_id)
+        REFERENCES webhook_subscription (id) ON DELETE CASCADE,
+    CONSTRAINT webhook_delivery_subscription_event_unique UNIQUE (webhook_subscription_id, event_id),
+    CONSTRAINT webhook_delivery_status_check CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+CREATE INDEX idx_webhook_delivery_pending ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+
+-- Every request sent for a delivery, with what the receiver answered.
+CREATE TABLE webhook_delivery_attempt (
+    id UUID NOT NULL,
+    webhook_delivery_id UUID NOT NULL,
+    attempt INTEGER NOT NULL,
+    response_status INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webhook_delivery_attempt_pk PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_attempt_delivery_fk FOREIGN KEY (webhook_delivery_id)
+        REFERENCES webhook_delivery (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_delivery_attempt_delivery ON webhook_delivery_attempt (webhook_delivery_id, attempt);
+
+-- +migrate Down
+DROP TABLE webhook_delivery_attempt;
+DROP TABLE webhook_delivery;
+DROP TABLE webhook_subscription;
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- Notifies the account of every event written to the outbox, so the API
+-- instances streaming its activity fetch the new events. Notifications are
+-- only delivered when the transaction commits, and those with the same payload
+-- sent by one transaction are folded into one.
+-- +migrate StatementBegin
+CREATE FUNCTION notify_outbox_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('outbox_event', NEW.customer_account_id::TEXT);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER outbox_event_notify
+    AFTER INSERT ON outbox_event
+    FOR EACH ROW EXECUTE FUNCTION notify_outbox_event();
+
+-- Streams read the events of an account after the last one they sent.
+CREATE INDEX idx_outbox_event_account ON outbox_event (customer_account_id, id);
+
+-- +migrate Down
+DROP INDEX idx_outbox_event_account;
+DROP TRIGGER outbox_event_notify ON outbox_event;
+DROP FUNCTION notify_outbox_event();
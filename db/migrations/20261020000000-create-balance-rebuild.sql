@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE balance_rebuild (
+    id UUID PRIMARY KEY,
+    operator TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    accounts_checked INTEGER NOT NULL DEFAULT 0,
+    adjustments_count INTEGER NOT NULL DEFAULT 0,
+    snapshot_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE balance_adjustment (
+    id UUID PRIMARY KEY,
+    balance_rebuild_id UUID NOT NULL,
+    customer_account_id UUID NOT NULL,
+    previous_balance BIGINT,
+    rebuilt_balance BIGINT NOT NULL,
+    adjustment BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT balance_adjustment_balance_rebuild_id_fk FOREIGN KEY (balance_rebuild_id) REFERENCES balance_rebuild(id),
+    CONSTRAINT balance_adjustment_customer_account_id_fk FOREIGN KEY (customer_account_id) REFERENCES customer_account(id)
+);
+
+CREATE INDEX idx_balance_adjustment_balance_rebuild_id ON balance_adjustment(balance_rebuild_id);
+CREATE INDEX idx_balance_adjustment_customer_account_id ON balance_adjustment(customer_account_id);
+
+-- +migrate Down
+DROP TABLE balance_adjustment;
+DROP TABLE balance_rebuild;
@@ -0,0 +1,42 @@
+-- +migrate Up
+-- The audit log outlives the entities it refers to, so it has no foreign keys.
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    action TEXT NOT NULL,
+    entity_type TEXT,
+    entity_id UUID,
+    customer_account_id UUID,
+    actor TEXT NOT NULL,
+    client_ip TEXT,
+    user_agent TEXT,
+    request_id TEXT,
+    method TEXT,
+    route TEXT,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_log_customer_account_id ON audit_log (customer_account_id, created_at);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+-- Rows can only be inserted. TRUNCATE doesn't fire row triggers, so dropping
+-- the whole table (e.g. between tests) still works for its owner.
+-- +migrate StatementBegin
+CREATE FUNCTION prevent_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only, % is not allowed', TG_OP
+        USING ERRCODE = 'insufficient_privilege';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_change();
+
+-- +migrate Down
+DROP TRIGGER audit_log_append_only ON audit_log;
+DROP FUNCTION prevent_audit_log_change();
+DROP TABLE audit_log;
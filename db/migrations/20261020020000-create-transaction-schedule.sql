@@ -0,0 +1,58 @@
+-- +migrate Up
+-- A transaction to post at a future date, once or recurring with an RRULE.
+-- next_occurrence is the index of the next occurrence to post and
+-- next_run_at when the worker should try it next (its date, a retry or the
+-- end of the lease of a worker posting it).
+CREATE TABLE transaction_schedule (
+    id UUID NOT NULL,
+    customer_account_id UUID NOT NULL,
+    operation_type VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL,
+    start_at TIMESTAMPTZ NOT NULL,
+    rrule TEXT,
+    insufficient_funds_policy VARCHAR(20) NOT NULL DEFAULT 'retry',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    next_occurrence INTEGER NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT transaction_schedule_pk PRIMARY KEY (id),
+    CONSTRAINT transaction_schedule_customer_account_fk FOREIGN KEY (customer_account_id)
+        REFERENCES customer_account (id),
+    CONSTRAINT transaction_schedule_operation_type_fk FOREIGN KEY (operation_type)
+        REFERENCES operation_types (code),
+    CONSTRAINT transaction_schedule_amount_check CHECK (amount > 0),
+    CONSTRAINT transaction_schedule_policy_check CHECK (insufficient_funds_policy IN ('retry', 'skip')),
+    CONSTRAINT transaction_schedule_status_check CHECK (status IN ('active', 'paused', 'cancelled', 'completed'))
+);
+
+CREATE INDEX idx_transaction_schedule_due ON transaction_schedule (next_run_at) WHERE status = 'active';
+CREATE INDEX idx_transaction_schedule_customer_account_id ON transaction_schedule (customer_account_id);
+
+-- The outcome of each occurrence of a schedule.
+CREATE TABLE transaction_schedule_run (
+    id UUID NOT NULL,
+    transaction_schedule_id UUID NOT NULL,
+    occurrence INTEGER NOT NULL,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    transaction_id UUID,
+    attempts INTEGER NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT transaction_schedule_run_pk PRIMARY KEY (id),
+    CONSTRAINT transaction_schedule_run_schedule_fk FOREIGN KEY (transaction_schedule_id)
+        REFERENCES transaction_schedule (id),
+    CONSTRAINT transaction_schedule_run_transaction_fk FOREIGN KEY (transaction_id)
+        REFERENCES transactions (id),
+    CONSTRAINT transaction_schedule_run_occurrence_unique UNIQUE (transaction_schedule_id, occurrence),
+    CONSTRAINT transaction_schedule_run_status_check CHECK (status IN ('created', 'skipped', 'failed'))
+);
+
+-- +migrate Down
+DROP TABLE transaction_schedule_run;
+DROP TABLE transaction_schedule;
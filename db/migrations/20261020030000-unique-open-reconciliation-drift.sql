@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Keep only the latest open drift of each account before enforcing a single
+-- open drift per account.
+UPDATE reconciliation_drift d
+SET status = 'resolved', resolved_at = NOW(), updated_at = NOW()
+WHERE d.status = 'open'
+  AND EXISTS (
+      SELECT 1 FROM reconciliation_drift newer
+      WHERE newer.customer_account_id = d.customer_account_id
+        AND newer.status = 'open'
+        AND (newer.created_at, newer.id) > (d.created_at, d.id)
+  );
+
+CREATE UNIQUE INDEX uniq_reconciliation_drift_open_customer_account_id
+    ON reconciliation_drift(customer_account_id)
+    WHERE status = 'open';
+
+-- +migrate Down
+DROP INDEX uniq_reconciliation_drift_open_customer_account_id;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- The fingerprint of the request lives in idempotency_record, next to the key
+-- it was claimed with, and is dropped from transactions. The idempotency key is
+-- kept on the transaction: it records which key created it, and its unique
+-- index per account still rejects a second transaction for the same key should
+-- a code path ever skip the claim.
+ALTER TABLE transactions DROP COLUMN request_fingerprint;
+
+-- +migrate Down
+ALTER TABLE transactions ADD COLUMN request_fingerprint VARCHAR(64);
+
+UPDATE transactions t
+SET request_fingerprint = r.request_fingerprint
+FROM idempotency_record r
+WHERE r.transaction_id = t.id;
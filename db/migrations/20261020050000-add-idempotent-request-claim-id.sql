@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Identifies the request holding a claim, so one that lost its lease can no
+-- longer renew, complete or release the claim taken over by a retry.
+ALTER TABLE idempotent_request ADD COLUMN claim_id UUID;
+
+-- +migrate Down
+ALTER TABLE idempotent_request DROP COLUMN claim_id;
@@ -19,6 +19,12 @@ type SearchCustomerAccountResult struct {
 	CreatedAt  time.Time
 }
 
+type BalanceResult struct {
+	CustomerAccountID *uuid.UUID
+	Balance           int64
+	At                time.Time
+}
+
 func DatabaseToSearchCustomerAccountResult(dbResult repository.CustomerAccountByIDResult) SearchCustomerAccountResult {
 	return SearchCustomerAccountResult{
 		CustomerID: dbResult.ID,
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofrs/uuid/v5"
@@ -22,6 +23,7 @@ func NewHTTPHandler(app *fiber.App, service Servicer) {
 	routeGroup := app.Group("/accounts")
 	routeGroup.Post("/", httpHandler.createAccount)
 	routeGroup.Get("/:customerAccountId", httpHandler.searchCustomerBankAccountByID)
+	routeGroup.Get("/:customerAccountId/balance", httpHandler.getBalance)
 }
 
 func (h *httpHandler) createAccount(c *fiber.Ctx) error {
@@ -71,3 +73,34 @@ func (h *httpHandler) searchCustomerBankAccountByID(c *fiber.Ctx) error {
 
 	return c.Status(http.StatusOK).JSON(DomainToSearchAccountByIDResponse(customerAccountResult))
 }
+
+func (h *httpHandler) getBalance(c *fiber.Ctx) error {
+	customerAccountIdParsed, err := uuid.FromString(c.Params("customerAccountId"))
+	if err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid account id",
+		})
+	}
+
+	at := time.Now()
+	if atQuery := c.Query("at"); atQuery != "" {
+		at, err = time.Parse(time.RFC3339, atQuery)
+		if err != nil {
+			return cerror.New(cerror.Params{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid at timestamp, expected RFC 3339",
+			})
+		}
+	}
+
+	balanceResult, err := h.service.GetBalance(c.Context(), getBalanceRequest{
+		CustomerAccountID: &customerAccountIdParsed,
+		At:                at,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToBalanceResponse(balanceResult))
+}
@@ -1,6 +1,10 @@
 package accounts
 
-import "github.com/gofrs/uuid/v5"
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
 
 type createAccountRequest struct {
 	Document string `json:"document_number" validate:"required"`
@@ -9,3 +13,8 @@ type createAccountRequest struct {
 type searchAccountRequest struct {
 	CustomerAccountID *uuid.UUID
 }
+
+type getBalanceRequest struct {
+	CustomerAccountID *uuid.UUID
+	At                time.Time
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
 )
 
 type AccountCreatedResponse struct {
@@ -17,6 +18,12 @@ type SearchCustomerAccountByIDResponse struct {
 	Document string     `json:"document_number"`
 }
 
+type BalanceResponse struct {
+	ID      *uuid.UUID `json:"account_id"`
+	Balance float64    `json:"balance"`
+	At      time.Time  `json:"at"`
+}
+
 func DomainToAccountCreatedResponse(customerAccountResult CustomerAccountResult) AccountCreatedResponse {
 	return AccountCreatedResponse{
 		ID:        customerAccountResult.CustomerAccount.ID,
@@ -31,3 +38,11 @@ func DomainToSearchAccountByIDResponse(searchCustomerAccountResult SearchCustome
 		Document: searchCustomerAccountResult.Document,
 	}
 }
+
+func DomainToBalanceResponse(balanceResult BalanceResult) BalanceResponse {
+	return BalanceResponse{
+		ID:      balanceResult.CustomerAccountID,
+		Balance: utils.FromCents(balanceResult.Balance),
+		At:      balanceResult.At,
+	}
+}
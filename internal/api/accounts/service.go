@@ -5,6 +5,8 @@ import (
 
 	"github.com/paemuri/brdoc"
 	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/audit"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
 	"github.com/tiagovaldrich/accounts-api/internal/repository"
@@ -15,23 +17,33 @@ type Servicer interface {
 	SearchCustomerAccountByID(
 		ctx context.Context, req searchAccountRequest,
 	) (SearchCustomerAccountResult, error)
+	GetBalance(ctx context.Context, req getBalanceRequest) (BalanceResult, error)
 }
 
 type service struct {
 	customerRepository        repository.CustomerRepository
 	customerAccountRepository repository.CustomerAccountRepository
 	balanceRepository         repository.BalanceRepository
+	balanceSnapshotRepository repository.BalanceSnapshotRepository
+	outboxRepository          repository.OutboxRepository
+	auditLogRepository        repository.AuditLogRepository
 }
 
 func NewService(
 	customerRepository repository.CustomerRepository,
 	customerAccountRepository repository.CustomerAccountRepository,
 	balanceRepository repository.BalanceRepository,
+	balanceSnapshotRepository repository.BalanceSnapshotRepository,
+	outboxRepository repository.OutboxRepository,
+	auditLogRepository repository.AuditLogRepository,
 ) Servicer {
 	return &service{
 		customerRepository:        customerRepository,
 		customerAccountRepository: customerAccountRepository,
 		balanceRepository:         balanceRepository,
+		balanceSnapshotRepository: balanceSnapshotRepository,
+		outboxRepository:          outboxRepository,
+		auditLogRepository:        auditLogRepository,
 	}
 }
 
@@ -78,10 +90,14 @@ func (s *service) CreateAccount(ctx context.Context, accountReq createAccountReq
 			return err
 		}
 
+		if err := s.recordAccountCreated(txCtx, customer, customerAccount); err != nil {
+			return err
+		}
+
 		customerAccountResult.Customer = customer
 		customerAccountResult.CustomerAccount = customerAccount
 
-		return nil
+		return s.recordAccountAudit(txCtx, customerAccountResult)
 	})
 
 	if err != nil {
@@ -91,6 +107,55 @@ func (s *service) CreateAccount(ctx context.Context, accountReq createAccountReq
 	return customerAccountResult, nil
 }
 
+func (s *service) recordAccountCreated(
+	ctx context.Context, customer *models.Customer, customerAccount *models.CustomerAccount,
+) error {
+	outboxEvent, err := events.NewOutboxEvent(events.AccountCreated, customerAccount.ID, events.AccountCreatedPayload{
+		AccountID:      customerAccount.ID,
+		CustomerID:     customer.ID,
+		DocumentNumber: customer.Document,
+		CreatedAt:      customerAccount.CreatedAt,
+	})
+	if err == nil {
+		err = s.outboxRepository.CreateOutboxEvents(ctx, outboxEvent)
+	}
+
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccount.ID.String()).
+			Msg("failed to record account created event")
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) recordAccountAudit(ctx context.Context, customerAccountResult CustomerAccountResult) error {
+	customerAccountID := customerAccountResult.CustomerAccount.ID
+
+	entry, err := audit.NewEntry(ctx, audit.Change{
+		Action:            audit.ActionCreate,
+		EntityType:        audit.EntityAccount,
+		EntityID:          customerAccountID,
+		CustomerAccountID: customerAccountID,
+		After:             DomainToAccountCreatedResponse(customerAccountResult),
+	})
+	if err == nil {
+		_, err = s.auditLogRepository.CreateAuditLog(ctx, entry)
+	}
+
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Msg("failed to record account audit log")
+
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) isValidDocumentNumber(documentNumber string) bool {
 	return brdoc.IsCPF(documentNumber) || brdoc.IsCNPJ(documentNumber)
 }
@@ -116,3 +181,29 @@ func (s *service) SearchCustomerAccountByID(
 
 	return DatabaseToSearchCustomerAccountResult(*customerAccount), nil
 }
+
+func (s *service) GetBalance(ctx context.Context, getBalanceReq getBalanceRequest) (BalanceResult, error) {
+	if _, err := s.SearchCustomerAccountByID(ctx, searchAccountRequest{
+		CustomerAccountID: getBalanceReq.CustomerAccountID,
+	}); err != nil {
+		return BalanceResult{}, err
+	}
+
+	balance, err := s.balanceSnapshotRepository.GetCustomerAccountBalanceAt(
+		ctx, getBalanceReq.CustomerAccountID, getBalanceReq.At,
+	)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", getBalanceReq.CustomerAccountID.String()).
+			Time("at", getBalanceReq.At).
+			Msg("failed to get customer account balance at point in time")
+
+		return BalanceResult{}, err
+	}
+
+	return BalanceResult{
+		CustomerAccountID: getBalanceReq.CustomerAccountID,
+		Balance:           balance,
+		At:                getBalanceReq.At,
+	}, nil
+}
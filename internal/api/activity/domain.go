@@ -0,0 +1,29 @@
+package activity
+
+import (
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+// StreamEvent is an event of the stream. Its ID is the position of the event
+// in the outbox, which only grows, so clients can resume after it.
+type StreamEvent struct {
+	ID    int64
+	Event events.Event
+}
+
+// Subscription is a client following the events of an account.
+type Subscription struct {
+	// LastEventID is the event the stream starts after.
+	LastEventID int64
+	// WakeUp receives a value when new events of the account are committed.
+	WakeUp      <-chan struct{}
+	Unsubscribe func()
+}
+
+func DatabaseToStreamEvent(outboxEvent models.OutboxEvent) StreamEvent {
+	return StreamEvent{
+		ID:    outboxEvent.ID,
+		Event: events.DatabaseToEvent(outboxEvent),
+	}
+}
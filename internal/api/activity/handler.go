@@ -0,0 +1,137 @@
+package activity
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+)
+
+const (
+	// heartbeatInterval is how often a comment is sent on idle streams, so
+	// proxies keep them open and closed connections are noticed. Events are
+	// also looked up then, in case a notification was lost.
+	heartbeatInterval = 15 * time.Second
+
+	// reconnectDelay is how long clients wait before reconnecting.
+	reconnectDelay = 3 * time.Second
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	app.Get("/accounts/:customerAccountId/events", httpHandler.streamEvents)
+}
+
+// streamEvents streams the transaction and balance events of the account as
+// Server-Sent Events, as they're committed. A client that reconnects with the
+// Last-Event-ID header (or the last_event_id query parameter, for clients that
+// can't set headers) gets the events it missed first.
+func (h *httpHandler) streamEvents(c *fiber.Ctx) error {
+	customerAccountID, err := uuid.FromString(c.Params("customerAccountId"))
+	if err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid account id",
+		})
+	}
+
+	request := subscribeRequest{
+		CustomerAccountID: &customerAccountID,
+	}
+
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	if lastEventID != "" {
+		parsed, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || parsed < 0 {
+			return cerror.New(cerror.Params{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid payload",
+			}, cerror.FieldError{
+				Field:   "Last-Event-ID",
+				Message: "Last-Event-ID must be the id of an event",
+			})
+		}
+
+		request.LastEventID = &parsed
+	}
+
+	subscription, err := h.service.Subscribe(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Unsubscribe()
+
+		// The stream outlives the handler, so it can't use the request
+		// context; it ends when writing to the client fails.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		h.stream(ctx, w, &customerAccountID, subscription)
+	})
+
+	return nil
+}
+
+func (h *httpHandler) stream(ctx context.Context, w *bufio.Writer, customerAccountID *uuid.UUID, subscription Subscription) {
+	lastEventID := subscription.LastEventID
+
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		streamEvents, err := h.service.ListEvents(ctx, listEventsRequest{
+			CustomerAccountID: customerAccountID,
+			AfterEventID:      lastEventID,
+		})
+		if err != nil {
+			log.Err(err).Str("customer_account_id", customerAccountID.String()).Msg("failed to list account events")
+
+			return
+		}
+
+		for _, streamEvent := range streamEvents {
+			if err := writeStreamEvent(w, streamEvent); err != nil {
+				return
+			}
+
+			lastEventID = streamEvent.ID
+		}
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		if len(streamEvents) == listEventsLimit {
+			continue
+		}
+
+		select {
+		case <-subscription.WakeUp:
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+	}
+}
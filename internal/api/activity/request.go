@@ -0,0 +1,15 @@
+package activity
+
+import "github.com/gofrs/uuid/v5"
+
+type subscribeRequest struct {
+	CustomerAccountID *uuid.UUID
+	// LastEventID is the id of the last event the client received, to resume
+	// after it. Without it only events committed from now on are streamed.
+	LastEventID *int64
+}
+
+type listEventsRequest struct {
+	CustomerAccountID *uuid.UUID
+	AfterEventID      int64
+}
@@ -0,0 +1,20 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// writeStreamEvent writes the event in the text/event-stream format, with the
+// event envelope as its data.
+func writeStreamEvent(w io.Writer, event StreamEvent) error {
+	data, err := json.Marshal(event.Event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event.Type, data)
+
+	return err
+}
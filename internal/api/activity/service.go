@@ -0,0 +1,98 @@
+package activity
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const listEventsLimit = 100
+
+// StreamedEventTypes are the events sent to the clients following an account.
+var StreamedEventTypes = []string{
+	events.TransactionCreated,
+	events.BalanceChanged,
+}
+
+type Servicer interface {
+	Subscribe(context.Context, subscribeRequest) (Subscription, error)
+	ListEvents(context.Context, listEventsRequest) ([]StreamEvent, error)
+}
+
+type service struct {
+	customerAccountRepository repository.CustomerAccountRepository
+	outboxRepository          repository.OutboxRepository
+	notifier                  *events.Notifier
+}
+
+func NewService(
+	customerAccountRepository repository.CustomerAccountRepository,
+	outboxRepository repository.OutboxRepository,
+	notifier *events.Notifier,
+) Servicer {
+	return &service{
+		customerAccountRepository: customerAccountRepository,
+		outboxRepository:          outboxRepository,
+		notifier:                  notifier,
+	}
+}
+
+// Subscribe starts following the events of the account. The subscription is
+// taken before the starting point is read, so no event committed in between
+// is missed.
+func (s *service) Subscribe(ctx context.Context, request subscribeRequest) (Subscription, error) {
+	customerAccount, err := s.customerAccountRepository.SearchCustomerAccountByID(ctx, request.CustomerAccountID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if customerAccount == nil {
+		return Subscription{}, cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Customer account not found",
+		})
+	}
+
+	wakeUp, unsubscribe := s.notifier.Subscribe(*request.CustomerAccountID)
+
+	subscription := Subscription{
+		WakeUp:      wakeUp,
+		Unsubscribe: unsubscribe,
+	}
+
+	if request.LastEventID != nil {
+		subscription.LastEventID = *request.LastEventID
+
+		return subscription, nil
+	}
+
+	subscription.LastEventID, err = s.outboxRepository.GetLatestAccountOutboxEventID(ctx, request.CustomerAccountID)
+	if err != nil {
+		unsubscribe()
+
+		return Subscription{}, err
+	}
+
+	return subscription, nil
+}
+
+// ListEvents returns the next events of the account to stream, oldest first,
+// up to listEventsLimit at a time.
+func (s *service) ListEvents(ctx context.Context, request listEventsRequest) ([]StreamEvent, error) {
+	outboxEvents, err := s.outboxRepository.ListAccountOutboxEvents(
+		ctx, request.CustomerAccountID, request.AfterEventID, StreamedEventTypes, listEventsLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]StreamEvent, 0, len(outboxEvents))
+	for _, outboxEvent := range outboxEvents {
+		result = append(result, DatabaseToStreamEvent(outboxEvent))
+	}
+
+	return result, nil
+}
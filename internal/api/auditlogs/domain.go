@@ -0,0 +1,45 @@
+package auditlogs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type AuditLogResult struct {
+	ID                *uuid.UUID
+	Action            string
+	EntityType        *string
+	EntityID          *uuid.UUID
+	CustomerAccountID *uuid.UUID
+	Actor             string
+	ClientIP          *string
+	UserAgent         *string
+	RequestID         *string
+	Method            *string
+	Route             *string
+	Before            json.RawMessage
+	After             json.RawMessage
+	CreatedAt         time.Time
+}
+
+func DatabaseToAuditLogResult(entry models.AuditLog) AuditLogResult {
+	return AuditLogResult{
+		ID:                entry.ID,
+		Action:            entry.Action,
+		EntityType:        entry.EntityType,
+		EntityID:          entry.EntityID,
+		CustomerAccountID: entry.CustomerAccountID,
+		Actor:             entry.Actor,
+		ClientIP:          entry.ClientIP,
+		UserAgent:         entry.UserAgent,
+		RequestID:         entry.RequestID,
+		Method:            entry.Method,
+		Route:             entry.Route,
+		Before:            entry.Before,
+		After:             entry.After,
+		CreatedAt:         entry.CreatedAt,
+	}
+}
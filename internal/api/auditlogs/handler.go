@@ -0,0 +1,107 @@
+package auditlogs
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/audit-logs")
+	routeGroup.Get("/", httpHandler.listAuditLogs)
+}
+
+func (h *httpHandler) listAuditLogs(c *fiber.Ctx) error {
+	request := listAuditLogsRequest{
+		Limit: defaultListLimit,
+	}
+
+	var fieldErrors []cerror.FieldError
+
+	if entityType := c.Query("entity_type"); entityType != "" {
+		request.EntityType = &entityType
+	}
+
+	for field, target := range map[string]**uuid.UUID{
+		"entity_id":  &request.EntityID,
+		"account_id": &request.CustomerAccountID,
+	} {
+		if value := c.Query(field); value != "" {
+			id, err := uuid.FromString(value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, cerror.FieldError{
+					Field:   field,
+					Message: field + " must be a valid UUID",
+				})
+
+				continue
+			}
+
+			*target = &id
+		}
+	}
+
+	for field, target := range map[string]**time.Time{
+		"from": &request.From,
+		"to":   &request.To,
+	} {
+		if value := c.Query(field); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, cerror.FieldError{
+					Field:   field,
+					Message: field + " must be an RFC 3339 timestamp",
+				})
+
+				continue
+			}
+
+			*target = &at
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+
+		request.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			fieldErrors = append(fieldErrors, cerror.FieldError{
+				Field:   "limit",
+				Message: "limit must be an integer",
+			})
+		}
+	}
+
+	if len(fieldErrors) == 0 {
+		if err := validator.ValidateStruct(request); err != nil {
+			fieldErrors = err.FieldErrors
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid query parameters",
+		}, fieldErrors...)
+	}
+
+	auditLogs, err := h.service.ListAuditLogs(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToAuditLogsResponse(auditLogs))
+}
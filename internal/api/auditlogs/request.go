@@ -0,0 +1,18 @@
+package auditlogs
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const defaultListLimit = 100
+
+type listAuditLogsRequest struct {
+	EntityType        *string `validate:"omitempty,max=64"`
+	EntityID          *uuid.UUID
+	CustomerAccountID *uuid.UUID
+	From              *time.Time
+	To                *time.Time
+	Limit             int `validate:"min=1,max=500"`
+}
@@ -0,0 +1,53 @@
+package auditlogs
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type AuditLogResponse struct {
+	ID                *uuid.UUID      `json:"id"`
+	Action            string          `json:"action"`
+	EntityType        *string         `json:"entity_type"`
+	EntityID          *uuid.UUID      `json:"entity_id"`
+	CustomerAccountID *uuid.UUID      `json:"account_id"`
+	Actor             string          `json:"actor"`
+	ClientIP          *string         `json:"client_ip"`
+	UserAgent         *string         `json:"user_agent"`
+	RequestID         *string         `json:"request_id"`
+	Method            *string         `json:"method"`
+	Route             *string         `json:"route"`
+	Before            json.RawMessage `json:"before,omitempty"`
+	After             json.RawMessage `json:"after,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+func DomainToAuditLogResponse(result AuditLogResult) AuditLogResponse {
+	return AuditLogResponse{
+		ID:                result.ID,
+		Action:            result.Action,
+		EntityType:        result.EntityType,
+		EntityID:          result.EntityID,
+		CustomerAccountID: result.CustomerAccountID,
+		Actor:             result.Actor,
+		ClientIP:          result.ClientIP,
+		UserAgent:         result.UserAgent,
+		RequestID:         result.RequestID,
+		Method:            result.Method,
+		Route:             result.Route,
+		Before:            result.Before,
+		After:             result.After,
+		CreatedAt:         result.CreatedAt,
+	}
+}
+
+func DomainToAuditLogsResponse(results []AuditLogResult) []AuditLogResponse {
+	response := make([]AuditLogResponse, 0, len(results))
+	for _, result := range results {
+		response = append(response, DomainToAuditLogResponse(result))
+	}
+
+	return response
+}
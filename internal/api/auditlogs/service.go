@@ -0,0 +1,45 @@
+package auditlogs
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+type Servicer interface {
+	ListAuditLogs(context.Context, listAuditLogsRequest) ([]AuditLogResult, error)
+}
+
+type service struct {
+	auditLogRepository repository.AuditLogRepository
+}
+
+func NewService(auditLogRepository repository.AuditLogRepository) Servicer {
+	return &service{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (s *service) ListAuditLogs(ctx context.Context, request listAuditLogsRequest) ([]AuditLogResult, error) {
+	entries, err := s.auditLogRepository.ListAuditLogs(ctx, repository.AuditLogFilter{
+		EntityType:        request.EntityType,
+		EntityID:          request.EntityID,
+		CustomerAccountID: request.CustomerAccountID,
+		From:              request.From,
+		To:                request.To,
+		Limit:             request.Limit,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to list audit logs")
+
+		return nil, err
+	}
+
+	results := make([]AuditLogResult, 0, len(entries))
+	for _, entry := range entries {
+		results = append(results, DatabaseToAuditLogResult(entry))
+	}
+
+	return results, nil
+}
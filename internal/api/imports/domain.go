@@ -0,0 +1,55 @@
+package imports
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type ImportStatus string
+
+const (
+	// ImportValidated is a dry run in which every row is valid.
+	ImportValidated ImportStatus = "validated"
+	// ImportRejected is a file with invalid rows; none of its rows were posted.
+	ImportRejected ImportStatus = "rejected"
+	// ImportCompleted is a file whose rows were posted.
+	ImportCompleted ImportStatus = "imported"
+)
+
+type ImportResult struct {
+	ID              *uuid.UUID
+	FileName        string
+	FileHash        string
+	Format          models.ImportFormat
+	Status          ImportStatus
+	DryRun          bool
+	AlreadyImported bool
+	TotalRows       int
+	ImportedRows    int
+	FailedRows      int
+	Rows            []models.ImportFileRow
+	CreatedAt       *time.Time
+}
+
+// Rejected tells whether the file was posted for real but refused because of
+// invalid rows.
+func (r ImportResult) Rejected() bool {
+	return !r.DryRun && r.Status == ImportRejected
+}
+
+func DatabaseToImportResult(importFile models.ImportFile) ImportResult {
+	return ImportResult{
+		ID:           importFile.ID,
+		FileName:     importFile.FileName,
+		FileHash:     importFile.FileHash,
+		Format:       importFile.Format,
+		Status:       ImportCompleted,
+		TotalRows:    importFile.TotalRows,
+		ImportedRows: importFile.ImportedRows,
+		FailedRows:   importFile.FailedRows,
+		Rows:         importFile.Rows,
+		CreatedAt:    &importFile.CreatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package imports
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/imports")
+	routeGroup.Post("/", httpHandler.importTransactions)
+	routeGroup.Get("/:importId", httpHandler.getImport)
+}
+
+func (h *httpHandler) importTransactions(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Missing file",
+		})
+	}
+
+	content, err := readFile(fileHeader)
+	if err != nil {
+		log.Err(err).Msg("failed to read import file")
+
+		return err
+	}
+
+	request := ImportRequest{
+		FileName:     fileHeader.Filename,
+		Format:       models.ImportFormat(c.FormValue("format")),
+		Content:      content,
+		CSVMapping:   DefaultCSVColumnMapping(),
+		CSVDelimiter: c.FormValue("delimiter"),
+	}
+
+	if request.Format == "" {
+		request.Format = FormatFromFileName(fileHeader.Filename)
+	}
+
+	if dryRun := c.FormValue("dry_run"); dryRun != "" {
+		request.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			return cerror.New(cerror.Params{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid payload",
+			}, cerror.FieldError{
+				Field:   "dry_run",
+				Message: "dry_run must be a boolean",
+			})
+		}
+	}
+
+	for column, name := range map[*string]string{
+		&request.CSVMapping.AccountID:     c.FormValue("account_id_column"),
+		&request.CSVMapping.OperationType: c.FormValue("operation_type_column"),
+		&request.CSVMapping.Amount:        c.FormValue("amount_column"),
+	} {
+		if name != "" {
+			*column = name
+		}
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	importResult, err := h.service.ImportTransactions(c.Context(), request)
+	if err != nil {
+		log.Err(err).Str("file_name", request.FileName).Msg("failed to import transactions")
+
+		return err
+	}
+
+	status := http.StatusOK
+	if importResult.Rejected() {
+		status = http.StatusUnprocessableEntity
+	}
+
+	return c.Status(status).JSON(DomainToImportResponse(importResult))
+}
+
+func (h *httpHandler) getImport(c *fiber.Ctx) error {
+	importID, err := uuid.FromString(c.Params("importId"))
+	if err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid import id",
+		})
+	}
+
+	importResult, err := h.service.GetImport(c.Context(), getImportRequest{
+		ImportID: &importID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToImportResponse(importResult))
+}
+
+func readFile(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
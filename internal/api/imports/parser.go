@@ -0,0 +1,157 @@
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/ofx"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// fileRow is a transaction as read from the file, before any validation. Row
+// is its 1-based position among the transactions of the file.
+type fileRow struct {
+	Row           int
+	AccountID     string
+	OperationType string
+	Amount        string
+}
+
+// FormatFromFileName guesses the format of a file from its extension, so
+// callers only need to send it when the extension is unusual.
+func FormatFromFileName(fileName string) models.ImportFormat {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return models.CSVImportFormat
+	case ".ofx", ".qfx":
+		return models.OFXImportFormat
+	default:
+		return ""
+	}
+}
+
+func parseRows(request ImportRequest) ([]fileRow, error) {
+	if request.Format == models.OFXImportFormat {
+		return parseOFXRows(request.Content)
+	}
+
+	return parseCSVRows(request.Content, request.CSVMapping, request.CSVDelimiter)
+}
+
+func parseCSVRows(content []byte, mapping CSVColumnMapping, delimiter string) ([]fileRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, utf8BOM)))
+	reader.TrimLeadingSpace = true
+
+	if delimiter != "" {
+		reader.Comma = []rune(delimiter)[0]
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for index, name := range header {
+		columns[strings.TrimSpace(name)] = index
+	}
+
+	indexOf := func(column string) (int, error) {
+		index, ok := columns[column]
+		if !ok {
+			return 0, fmt.Errorf("column %q not found in the header", column)
+		}
+
+		return index, nil
+	}
+
+	accountIDColumn, err := indexOf(mapping.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	operationTypeColumn, err := indexOf(mapping.OperationType)
+	if err != nil {
+		return nil, err
+	}
+
+	amountColumn, err := indexOf(mapping.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []fileRow
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, fileRow{
+			Row:           len(rows) + 1,
+			AccountID:     strings.TrimSpace(record[accountIDColumn]),
+			OperationType: strings.TrimSpace(record[operationTypeColumn]),
+			Amount:        strings.TrimSpace(record[amountColumn]),
+		})
+	}
+}
+
+// parseOFXRows reads the transactions of every statement in the file. The
+// ACCTID of the statement holds the account id, and as OFX has no notion of
+// our operation types they come from the sign of the amount and the TRNTYPE.
+func parseOFXRows(content []byte) ([]fileRow, error) {
+	statements, err := ofx.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []fileRow
+
+	for _, statement := range statements {
+		// Statements exported by this API hold the account id encoded to fit
+		// in ACCTID; other files are expected to hold it as is.
+		accountID := statement.AccountID
+		if id, ok := ofx.DecodeAccountID(accountID); ok {
+			accountID = id.String()
+		}
+
+		for _, transaction := range statement.Transactions {
+			amount := strings.TrimPrefix(transaction.Amount, "+")
+			debit := strings.HasPrefix(amount, "-")
+
+			rows = append(rows, fileRow{
+				Row:           len(rows) + 1,
+				AccountID:     accountID,
+				OperationType: string(ofxOperationType(transaction.Type, debit)),
+				Amount:        strings.TrimPrefix(amount, "-"),
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+func ofxOperationType(transactionType string, debit bool) models.OperationType {
+	if !debit {
+		return models.CreditVoucher
+	}
+
+	switch strings.ToUpper(transactionType) {
+	case "ATM", "CASH":
+		return models.Withdrawal
+	default:
+		return models.NormalPurchase
+	}
+}
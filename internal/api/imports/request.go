@@ -0,0 +1,35 @@
+package imports
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+// CSVColumnMapping names the header columns of a CSV file holding each field
+// of the transactions.
+type CSVColumnMapping struct {
+	AccountID     string `json:"account_id" validate:"required"`
+	OperationType string `json:"operation_type" validate:"required"`
+	Amount        string `json:"amount" validate:"required"`
+}
+
+func DefaultCSVColumnMapping() CSVColumnMapping {
+	return CSVColumnMapping{
+		AccountID:     "account_id",
+		OperationType: "operation_type",
+		Amount:        "amount",
+	}
+}
+
+type ImportRequest struct {
+	FileName     string              `validate:"required,max=255"`
+	Format       models.ImportFormat `validate:"required,oneof=csv ofx"`
+	Content      []byte              `validate:"required"`
+	DryRun       bool
+	CSVMapping   CSVColumnMapping
+	CSVDelimiter string `validate:"omitempty,len=1"`
+}
+
+type getImportRequest struct {
+	ImportID *uuid.UUID
+}
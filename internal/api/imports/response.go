@@ -0,0 +1,65 @@
+package imports
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+type ImportResponse struct {
+	ID              *uuid.UUID          `json:"id"`
+	FileName        string              `json:"file_name"`
+	FileHash        string              `json:"file_hash"`
+	Format          models.ImportFormat `json:"format"`
+	Status          ImportStatus        `json:"status"`
+	DryRun          bool                `json:"dry_run"`
+	AlreadyImported bool                `json:"already_imported"`
+	TotalRows       int                 `json:"total_rows"`
+	ImportedRows    int                 `json:"imported_rows"`
+	FailedRows      int                 `json:"failed_rows"`
+	Rows            []ImportRowResponse `json:"rows"`
+	CreatedAt       *time.Time          `json:"created_at"`
+}
+
+type ImportRowResponse struct {
+	Row               int                    `json:"row"`
+	CustomerAccountID *uuid.UUID             `json:"account_id"`
+	OperationType     models.OperationType   `json:"operation_type"`
+	Amount            float64                `json:"amount"`
+	Status            models.ImportRowStatus `json:"status"`
+	TransactionID     *uuid.UUID             `json:"transaction_id,omitempty"`
+	Errors            []string               `json:"errors,omitempty"`
+}
+
+func DomainToImportResponse(result ImportResult) ImportResponse {
+	rows := make([]ImportRowResponse, 0, len(result.Rows))
+
+	for _, row := range result.Rows {
+		rows = append(rows, ImportRowResponse{
+			Row:               row.Row,
+			CustomerAccountID: row.CustomerAccountID,
+			OperationType:     row.OperationType,
+			Amount:            utils.FromCents(row.Amount),
+			Status:            row.Status,
+			TransactionID:     row.TransactionID,
+			Errors:            row.Errors,
+		})
+	}
+
+	return ImportResponse{
+		ID:              result.ID,
+		FileName:        result.FileName,
+		FileHash:        result.FileHash,
+		Format:          result.Format,
+		Status:          result.Status,
+		DryRun:          result.DryRun,
+		AlreadyImported: result.AlreadyImported,
+		TotalRows:       result.TotalRows,
+		ImportedRows:    result.ImportedRows,
+		FailedRows:      result.FailedRows,
+		Rows:            rows,
+		CreatedAt:       result.CreatedAt,
+	}
+}
@@ -0,0 +1,288 @@
+package imports
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/shopspring/decimal"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+type Servicer interface {
+	ImportTransactions(context.Context, ImportRequest) (ImportResult, error)
+	GetImport(context.Context, getImportRequest) (ImportResult, error)
+}
+
+type service struct {
+	importFileRepository      repository.ImportFileRepository
+	customerAccountRepository repository.CustomerAccountRepository
+	transactionsService       transactions.Servicer
+	operationTypes            operationtypes.Registry
+}
+
+func NewService(
+	importFileRepository repository.ImportFileRepository,
+	customerAccountRepository repository.CustomerAccountRepository,
+	transactionsService transactions.Servicer,
+	operationTypes operationtypes.Registry,
+) Servicer {
+	return &service{
+		importFileRepository:      importFileRepository,
+		customerAccountRepository: customerAccountRepository,
+		transactionsService:       transactionsService,
+		operationTypes:            operationTypes,
+	}
+}
+
+// ImportTransactions validates every row of the file and, unless it's a dry
+// run or a row is invalid, posts them through the transactions service.
+//
+// A file is identified by the SHA-256 of its content: uploading it again
+// returns the stored report without posting anything. Each row is posted with
+// an idempotency key derived from that hash and its position, so a file whose
+// import was interrupted by an unexpected error can be uploaded again and only
+// the missing rows are created.
+func (s *service) ImportTransactions(ctx context.Context, request ImportRequest) (ImportResult, error) {
+	fileHash := hashContent(request.Content)
+
+	importFile, err := s.importFileRepository.GetImportFileByHash(ctx, fileHash)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	if importFile != nil {
+		result := DatabaseToImportResult(*importFile)
+		result.DryRun = request.DryRun
+		result.AlreadyImported = true
+
+		return result, nil
+	}
+
+	fileRows, err := parseRows(request)
+	if err != nil {
+		return ImportResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid file",
+		}, cerror.FieldError{
+			Field:   "file",
+			Message: err.Error(),
+		})
+	}
+
+	if len(fileRows) == 0 {
+		return ImportResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "File has no transactions",
+		})
+	}
+
+	rows, invalidRows, err := s.validateRows(ctx, fileRows)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	result := ImportResult{
+		FileName:   request.FileName,
+		FileHash:   fileHash,
+		Format:     request.Format,
+		Status:     ImportValidated,
+		DryRun:     request.DryRun,
+		TotalRows:  len(rows),
+		FailedRows: invalidRows,
+		Rows:       rows,
+	}
+
+	if invalidRows > 0 {
+		result.Status = ImportRejected
+
+		return result, nil
+	}
+
+	if request.DryRun {
+		return result, nil
+	}
+
+	return s.postRows(ctx, result)
+}
+
+func (s *service) GetImport(ctx context.Context, request getImportRequest) (ImportResult, error) {
+	importFile, err := s.importFileRepository.GetImportFileByID(ctx, request.ImportID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	if importFile == nil {
+		return ImportResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Import not found",
+		})
+	}
+
+	return DatabaseToImportResult(*importFile), nil
+}
+
+// validateRows checks every row against the accounts and operation types, as
+// the transactions service would, without posting anything.
+func (s *service) validateRows(ctx context.Context, fileRows []fileRow) ([]models.ImportFileRow, int, error) {
+	rows := make([]models.ImportFileRow, 0, len(fileRows))
+	accounts := map[uuid.UUID]bool{}
+	invalidRows := 0
+
+	for _, fileRow := range fileRows {
+		row := models.ImportFileRow{
+			Row:           fileRow.Row,
+			OperationType: models.OperationType(fileRow.OperationType),
+			Status:        models.ImportRowValid,
+		}
+
+		accountID, err := uuid.FromString(fileRow.AccountID)
+		if err != nil {
+			row.Errors = append(row.Errors, "account_id must be a valid UUID")
+		} else {
+			row.CustomerAccountID = &accountID
+
+			exists, checked := accounts[accountID]
+			if !checked {
+				account, err := s.customerAccountRepository.SearchCustomerAccountByID(ctx, &accountID)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				exists = account != nil
+				accounts[accountID] = exists
+			}
+
+			if !exists {
+				row.Errors = append(row.Errors, "Customer account not found")
+			}
+		}
+
+		amount, err := parseAmount(fileRow.Amount)
+		if err != nil {
+			row.Errors = append(row.Errors, err.Error())
+		} else {
+			row.Amount = amount
+		}
+
+		row.Errors = append(row.Errors, s.validateOperationType(row.OperationType, row.Amount)...)
+
+		if len(row.Errors) > 0 {
+			row.Status = models.ImportRowInvalid
+			invalidRows++
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, invalidRows, nil
+}
+
+func (s *service) validateOperationType(code models.OperationType, amount int64) []string {
+	if code == "" {
+		return []string{"operation_type is required"}
+	}
+
+	operationType, ok := s.operationTypes.Lookup(code)
+	if !ok {
+		return []string{"Operation type not found"}
+	}
+
+	var errs []string
+
+	if !operationType.Enabled {
+		errs = append(errs, "Operation type is disabled")
+	}
+
+	if amount > 0 && !operationType.AllowsAmount(amount) {
+		errs = append(errs, "Amount is outside the limits of the operation type")
+	}
+
+	return errs
+}
+
+// postRows creates the transactions of a validated file and stores its
+// report. Rows refused by the transactions service (e.g. for insufficient
+// funds) are reported as failed, but an unexpected error aborts the import
+// without storing it, so the file can be uploaded again.
+func (s *service) postRows(ctx context.Context, result ImportResult) (ImportResult, error) {
+	for index, row := range result.Rows {
+		idempotencyKey := fmt.Sprintf("import:%s:%d", result.FileHash, row.Row)
+
+		transaction, err := s.transactionsService.CreateTransaction(ctx, transactions.CreateTransactionRequest{
+			CustomerAccountID: row.CustomerAccountID,
+			OperationType:     row.OperationType,
+			Amount:            utils.FromCents(row.Amount),
+			IdempotencyKey:    &idempotencyKey,
+		})
+		if err != nil {
+			var customErr *cerror.Error
+			if !errors.As(err, &customErr) || customErr.Status >= http.StatusInternalServerError {
+				return ImportResult{}, err
+			}
+
+			result.Rows[index].Status = models.ImportRowFailed
+			result.Rows[index].Errors = []string{customErr.Message}
+			result.FailedRows++
+
+			continue
+		}
+
+		result.Rows[index].Status = models.ImportRowCreated
+		if transaction.Replayed {
+			result.Rows[index].Status = models.ImportRowReplayed
+		}
+
+		result.Rows[index].TransactionID = transaction.ID
+		result.ImportedRows++
+	}
+
+	importFile, err := s.importFileRepository.CreateImportFile(ctx, models.ImportFile{
+		FileHash:     result.FileHash,
+		FileName:     result.FileName,
+		Format:       result.Format,
+		TotalRows:    result.TotalRows,
+		ImportedRows: result.ImportedRows,
+		FailedRows:   result.FailedRows,
+		Rows:         result.Rows,
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return DatabaseToImportResult(*importFile), nil
+}
+
+// parseAmount reads a positive amount with at most two decimal places and
+// returns it in cents.
+func parseAmount(value string) (int64, error) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return 0, errors.New("amount must be a number")
+	}
+
+	if !amount.IsPositive() {
+		return 0, errors.New("amount must be greater than 0")
+	}
+
+	if !amount.Equal(amount.Round(2)) {
+		return 0, errors.New("amount must have at most 2 decimal places")
+	}
+
+	return amount.Shift(2).IntPart(), nil
+}
+
+func hashContent(content []byte) string {
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:])
+}
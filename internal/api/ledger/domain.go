@@ -0,0 +1,35 @@
+package ledger
+
+import "github.com/tiagovaldrich/accounts-api/internal/repository"
+
+type TrialBalanceAccount struct {
+	AccountType string
+	Account     string
+	Debits      int64
+	Credits     int64
+}
+
+type TrialBalanceResult struct {
+	Accounts     []TrialBalanceAccount
+	TotalDebits  int64
+	TotalCredits int64
+}
+
+func DatabaseToTrialBalanceResult(dbResult []repository.TrialBalanceResult) TrialBalanceResult {
+	result := TrialBalanceResult{
+		Accounts: make([]TrialBalanceAccount, 0, len(dbResult)),
+	}
+
+	for _, row := range dbResult {
+		result.Accounts = append(result.Accounts, TrialBalanceAccount{
+			AccountType: row.AccountType,
+			Account:     row.Account,
+			Debits:      row.Debits,
+			Credits:     row.Credits,
+		})
+		result.TotalDebits += row.Debits
+		result.TotalCredits += row.Credits
+	}
+
+	return result
+}
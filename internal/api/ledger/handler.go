@@ -0,0 +1,29 @@
+package ledger
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/ledger")
+	routeGroup.Get("/trial-balance", httpHandler.getTrialBalance)
+}
+
+func (h *httpHandler) getTrialBalance(c *fiber.Ctx) error {
+	trialBalance, err := h.service.GetTrialBalance(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToTrialBalanceResponse(trialBalance))
+}
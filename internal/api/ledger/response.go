@@ -0,0 +1,38 @@
+package ledger
+
+import "github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+
+type TrialBalanceAccountResponse struct {
+	AccountType string  `json:"account_type"`
+	Account     string  `json:"account"`
+	Debits      float64 `json:"debits"`
+	Credits     float64 `json:"credits"`
+	Balance     float64 `json:"balance"`
+}
+
+type TrialBalanceResponse struct {
+	Accounts     []TrialBalanceAccountResponse `json:"accounts"`
+	TotalDebits  float64                       `json:"total_debits"`
+	TotalCredits float64                       `json:"total_credits"`
+	Balanced     bool                          `json:"balanced"`
+}
+
+func DomainToTrialBalanceResponse(result TrialBalanceResult) TrialBalanceResponse {
+	accounts := make([]TrialBalanceAccountResponse, 0, len(result.Accounts))
+	for _, account := range result.Accounts {
+		accounts = append(accounts, TrialBalanceAccountResponse{
+			AccountType: account.AccountType,
+			Account:     account.Account,
+			Debits:      utils.FromCents(account.Debits),
+			Credits:     utils.FromCents(account.Credits),
+			Balance:     utils.FromCents(account.Credits - account.Debits),
+		})
+	}
+
+	return TrialBalanceResponse{
+		Accounts:     accounts,
+		TotalDebits:  utils.FromCents(result.TotalDebits),
+		TotalCredits: utils.FromCents(result.TotalCredits),
+		Balanced:     result.TotalDebits == result.TotalCredits,
+	}
+}
@@ -0,0 +1,33 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+type Servicer interface {
+	GetTrialBalance(context.Context) (TrialBalanceResult, error)
+}
+
+type service struct {
+	ledgerRepository repository.LedgerRepository
+}
+
+func NewService(ledgerRepository repository.LedgerRepository) Servicer {
+	return &service{
+		ledgerRepository: ledgerRepository,
+	}
+}
+
+func (s *service) GetTrialBalance(ctx context.Context) (TrialBalanceResult, error) {
+	trialBalance, err := s.ledgerRepository.GetTrialBalance(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to get trial balance")
+
+		return TrialBalanceResult{}, err
+	}
+
+	return DatabaseToTrialBalanceResult(trialBalance), nil
+}
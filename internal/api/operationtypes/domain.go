@@ -0,0 +1,51 @@
+package operationtypes
+
+import (
+	"time"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type OperationTypeResult struct {
+	Code                      models.OperationType
+	Direction                 models.OperationDirection
+	Description               string
+	Enabled                   bool
+	MinAmount                 *int64
+	MaxAmount                 *int64
+	CounterpartyLedgerAccount models.LedgerAccountCode
+	CreatedAt                 time.Time
+	UpdatedAt                 time.Time
+}
+
+func (o OperationTypeResult) IsCredit() bool {
+	return o.Direction == models.CreditDirection
+}
+
+// AllowsAmount tells whether the amount, in cents and without direction, is
+// within the configured limits of the operation type.
+func (o OperationTypeResult) AllowsAmount(amount int64) bool {
+	if o.MinAmount != nil && amount < *o.MinAmount {
+		return false
+	}
+
+	if o.MaxAmount != nil && amount > *o.MaxAmount {
+		return false
+	}
+
+	return true
+}
+
+func DatabaseToOperationTypeResult(operationType models.OperationTypeDefinition) OperationTypeResult {
+	return OperationTypeResult{
+		Code:                      operationType.Code,
+		Direction:                 operationType.Direction,
+		Description:               operationType.Description,
+		Enabled:                   operationType.Enabled,
+		MinAmount:                 operationType.MinAmount,
+		MaxAmount:                 operationType.MaxAmount,
+		CounterpartyLedgerAccount: operationType.CounterpartyLedgerAccount,
+		CreatedAt:                 operationType.CreatedAt,
+		UpdatedAt:                 operationType.UpdatedAt,
+	}
+}
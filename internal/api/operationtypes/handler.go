@@ -0,0 +1,91 @@
+package operationtypes
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/operation-types")
+	routeGroup.Get("/", httpHandler.listOperationTypes)
+	routeGroup.Post("/", httpHandler.createOperationType)
+	routeGroup.Patch("/:code", httpHandler.updateOperationType)
+}
+
+func (h *httpHandler) listOperationTypes(c *fiber.Ctx) error {
+	operationTypes, err := h.service.ListOperationTypes(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToOperationTypesResponse(operationTypes))
+}
+
+func (h *httpHandler) createOperationType(c *fiber.Ctx) error {
+	var body createOperationTypeRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid operation type payload",
+		})
+	}
+
+	if err := validator.ValidateStruct(body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	operationType, err := h.service.CreateOperationType(c.Context(), body)
+	if err != nil {
+		log.Err(err).Msg("failed to create operation type")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToOperationTypeResponse(operationType))
+}
+
+func (h *httpHandler) updateOperationType(c *fiber.Ctx) error {
+	var body updateOperationTypeRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid operation type payload",
+		})
+	}
+
+	body.Code = models.OperationType(c.Params("code"))
+
+	if err := validator.ValidateStruct(body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	operationType, err := h.service.UpdateOperationType(c.Context(), body)
+	if err != nil {
+		log.Err(err).Msg("failed to update operation type")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToOperationTypeResponse(operationType))
+}
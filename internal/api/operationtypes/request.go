@@ -0,0 +1,26 @@
+package operationtypes
+
+import (
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+type createOperationTypeRequest struct {
+	Code                      models.OperationType      `json:"code" validate:"required,max=64"`
+	Direction                 models.OperationDirection `json:"direction" validate:"required,oneof=credit debit"`
+	Description               string                    `json:"description" validate:"required,max=255"`
+	Enabled                   *bool                     `json:"enabled" validate:"omitempty"`
+	MinAmount                 *float64                  `json:"min_amount" validate:"omitempty,gte=0.01"`
+	MaxAmount                 *float64                  `json:"max_amount" validate:"omitempty,gte=0.01"`
+	CounterpartyLedgerAccount *models.LedgerAccountCode `json:"counterparty_ledger_account" validate:"omitempty"`
+}
+
+// updateOperationTypeRequest changes only the fields present in the payload.
+// The limits can be removed with an explicit null.
+type updateOperationTypeRequest struct {
+	Code        models.OperationType    `json:"-"`
+	Description *string                 `json:"description" validate:"omitempty,max=255"`
+	Enabled     *bool                   `json:"enabled" validate:"omitempty"`
+	MinAmount   utils.Nullable[float64] `json:"min_amount" validate:"omitempty,gte=0.01"`
+	MaxAmount   utils.Nullable[float64] `json:"max_amount" validate:"omitempty,gte=0.01"`
+}
@@ -0,0 +1,43 @@
+package operationtypes
+
+import (
+	"time"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+type OperationTypeResponse struct {
+	Code                      models.OperationType      `json:"code"`
+	Direction                 models.OperationDirection `json:"direction"`
+	Description               string                    `json:"description"`
+	Enabled                   bool                      `json:"enabled"`
+	MinAmount                 *float64                  `json:"min_amount"`
+	MaxAmount                 *float64                  `json:"max_amount"`
+	CounterpartyLedgerAccount models.LedgerAccountCode  `json:"counterparty_ledger_account"`
+	CreatedAt                 time.Time                 `json:"created_at"`
+	UpdatedAt                 time.Time                 `json:"updated_at"`
+}
+
+func DomainToOperationTypeResponse(result OperationTypeResult) OperationTypeResponse {
+	return OperationTypeResponse{
+		Code:                      result.Code,
+		Direction:                 result.Direction,
+		Description:               result.Description,
+		Enabled:                   result.Enabled,
+		MinAmount:                 utils.FromCentsPointer(result.MinAmount),
+		MaxAmount:                 utils.FromCentsPointer(result.MaxAmount),
+		CounterpartyLedgerAccount: result.CounterpartyLedgerAccount,
+		CreatedAt:                 result.CreatedAt,
+		UpdatedAt:                 result.UpdatedAt,
+	}
+}
+
+func DomainToOperationTypesResponse(results []OperationTypeResult) []OperationTypeResponse {
+	response := make([]OperationTypeResponse, 0, len(results))
+	for _, result := range results {
+		response = append(response, DomainToOperationTypeResponse(result))
+	}
+
+	return response
+}
@@ -0,0 +1,253 @@
+package operationtypes
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+// Registry resolves operation types from the in-memory cache, so the
+// transactions hot path doesn't hit the database for them.
+type Registry interface {
+	Lookup(code models.OperationType) (OperationTypeResult, bool)
+}
+
+type Servicer interface {
+	Registry
+	Load(context.Context) error
+	ListOperationTypes(context.Context) ([]OperationTypeResult, error)
+	CreateOperationType(context.Context, createOperationTypeRequest) (OperationTypeResult, error)
+	UpdateOperationType(context.Context, updateOperationTypeRequest) (OperationTypeResult, error)
+}
+
+type service struct {
+	operationTypeRepository repository.OperationTypeRepository
+	ledgerRepository        repository.LedgerRepository
+
+	mu             sync.RWMutex
+	operationTypes map[models.OperationType]OperationTypeResult
+}
+
+func NewService(
+	operationTypeRepository repository.OperationTypeRepository,
+	ledgerRepository repository.LedgerRepository,
+) Servicer {
+	return &service{
+		operationTypeRepository: operationTypeRepository,
+		ledgerRepository:        ledgerRepository,
+		operationTypes:          map[models.OperationType]OperationTypeResult{},
+	}
+}
+
+// Load replaces the cache with the operation types currently in the database.
+func (s *service) Load(ctx context.Context) error {
+	operationTypes, err := s.operationTypeRepository.ListOperationTypes(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to load operation types")
+
+		return err
+	}
+
+	cache := make(map[models.OperationType]OperationTypeResult, len(operationTypes))
+	for _, operationType := range operationTypes {
+		cache[operationType.Code] = DatabaseToOperationTypeResult(operationType)
+	}
+
+	s.mu.Lock()
+	s.operationTypes = cache
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *service) Lookup(code models.OperationType) (OperationTypeResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	operationType, ok := s.operationTypes[code]
+
+	return operationType, ok
+}
+
+func (s *service) ListOperationTypes(ctx context.Context) ([]OperationTypeResult, error) {
+	operationTypes, err := s.operationTypeRepository.ListOperationTypes(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to list operation types")
+
+		return nil, err
+	}
+
+	results := make([]OperationTypeResult, 0, len(operationTypes))
+	for _, operationType := range operationTypes {
+		results = append(results, DatabaseToOperationTypeResult(operationType))
+	}
+
+	return results, nil
+}
+
+func (s *service) CreateOperationType(
+	ctx context.Context, request createOperationTypeRequest,
+) (OperationTypeResult, error) {
+	existing, err := s.operationTypeRepository.GetOperationTypeByCode(ctx, request.Code)
+	if err != nil {
+		log.Err(err).
+			Str("operation_type", string(request.Code)).
+			Msg("failed to get operation type")
+
+		return OperationTypeResult{}, err
+	}
+
+	if existing != nil {
+		return OperationTypeResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusConflict,
+			Message: "Operation type already exists",
+		})
+	}
+
+	counterparty := s.defaultCounterparty(request.Direction)
+	if request.CounterpartyLedgerAccount != nil {
+		counterparty = *request.CounterpartyLedgerAccount
+	}
+
+	if err := s.validateCounterparty(ctx, counterparty); err != nil {
+		return OperationTypeResult{}, err
+	}
+
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+
+	operationType := models.OperationTypeDefinition{
+		Code:                      request.Code,
+		Direction:                 request.Direction,
+		Description:               request.Description,
+		Enabled:                   enabled,
+		MinAmount:                 utils.ToCentsPointer(request.MinAmount),
+		MaxAmount:                 utils.ToCentsPointer(request.MaxAmount),
+		CounterpartyLedgerAccount: counterparty,
+	}
+
+	if err := s.validateLimits(operationType); err != nil {
+		return OperationTypeResult{}, err
+	}
+
+	created, err := s.operationTypeRepository.CreateOperationType(ctx, operationType)
+	if err != nil {
+		log.Err(err).
+			Str("operation_type", string(request.Code)).
+			Msg("failed to create operation type")
+
+		return OperationTypeResult{}, err
+	}
+
+	if err := s.Load(ctx); err != nil {
+		return OperationTypeResult{}, err
+	}
+
+	return DatabaseToOperationTypeResult(*created), nil
+}
+
+func (s *service) UpdateOperationType(
+	ctx context.Context, request updateOperationTypeRequest,
+) (OperationTypeResult, error) {
+	operationType, err := s.operationTypeRepository.GetOperationTypeByCode(ctx, request.Code)
+	if err != nil {
+		log.Err(err).
+			Str("operation_type", string(request.Code)).
+			Msg("failed to get operation type")
+
+		return OperationTypeResult{}, err
+	}
+
+	if operationType == nil {
+		return OperationTypeResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Operation type not found",
+		})
+	}
+
+	if request.Description != nil {
+		operationType.Description = *request.Description
+	}
+
+	if request.Enabled != nil {
+		operationType.Enabled = *request.Enabled
+	}
+
+	if request.MinAmount.Set {
+		operationType.MinAmount = utils.ToCentsPointer(request.MinAmount.Value)
+	}
+
+	if request.MaxAmount.Set {
+		operationType.MaxAmount = utils.ToCentsPointer(request.MaxAmount.Value)
+	}
+
+	if err := s.validateLimits(*operationType); err != nil {
+		return OperationTypeResult{}, err
+	}
+
+	updated, err := s.operationTypeRepository.UpdateOperationType(ctx, *operationType)
+	if err != nil {
+		log.Err(err).
+			Str("operation_type", string(request.Code)).
+			Msg("failed to update operation type")
+
+		return OperationTypeResult{}, err
+	}
+
+	if err := s.Load(ctx); err != nil {
+		return OperationTypeResult{}, err
+	}
+
+	return DatabaseToOperationTypeResult(*updated), nil
+}
+
+func (s *service) defaultCounterparty(direction models.OperationDirection) models.LedgerAccountCode {
+	if direction == models.CreditDirection {
+		return models.VoucherFundingLedgerAccount
+	}
+
+	return models.SettlementLedgerAccount
+}
+
+func (s *service) validateCounterparty(ctx context.Context, code models.LedgerAccountCode) error {
+	ledgerAccount, err := s.ledgerRepository.GetLedgerAccountByCode(ctx, code)
+	if err != nil {
+		log.Err(err).
+			Str("ledger_account_code", string(code)).
+			Msg("failed to get ledger account")
+
+		return err
+	}
+
+	if ledgerAccount == nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Counterparty ledger account not found",
+		})
+	}
+
+	return nil
+}
+
+func (s *service) validateLimits(operationType models.OperationTypeDefinition) error {
+	if operationType.MinAmount == nil || operationType.MaxAmount == nil {
+		return nil
+	}
+
+	if *operationType.MaxAmount < *operationType.MinAmount {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Maximum amount must be greater than or equal to the minimum amount",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+type DriftResult struct {
+	ID                *uuid.UUID
+	RunID             *uuid.UUID
+	CustomerAccountID *uuid.UUID
+	Balance           int64
+	TransactionsTotal int64
+	Drift             int64
+	Status            models.ReconciliationDriftStatus
+	AccountBlocked    bool
+	ResolvedAt        *time.Time
+	CreatedAt         time.Time
+}
+
+type ReportResult struct {
+	RunID           *uuid.UUID
+	AccountsChecked int
+	BlockAccounts   bool
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	Drifts          []DriftResult
+}
+
+func DatabaseToDriftResult(drift models.ReconciliationDrift) DriftResult {
+	return DriftResult{
+		ID:                drift.ID,
+		RunID:             drift.ReconciliationRunID,
+		CustomerAccountID: drift.CustomerAccountID,
+		Balance:           drift.Balance,
+		TransactionsTotal: drift.TransactionsTotal,
+		Drift:             drift.Drift,
+		Status:            drift.Status,
+		AccountBlocked:    drift.AccountBlocked,
+		ResolvedAt:        drift.ResolvedAt,
+		CreatedAt:         drift.CreatedAt,
+	}
+}
+
+type BalanceDiffStatus string
+
+const (
+	// BalanceDiffPending is a difference found by a dry run, not applied.
+	BalanceDiffPending BalanceDiffStatus = "pending"
+	// BalanceDiffApplied is a difference corrected with an adjustment.
+	BalanceDiffApplied BalanceDiffStatus = "applied"
+	// BalanceDiffSkipped is a difference gone by the time it was applied.
+	BalanceDiffSkipped BalanceDiffStatus = "skipped"
+)
+
+// BalanceDiffResult is an account whose balance differs from the sum of its
+// transactions. Balance is nil when the account has no balance row.
+type BalanceDiffResult struct {
+	CustomerAccountID *uuid.UUID
+	Balance           *int64
+	RebuiltBalance    int64
+	Difference        int64
+	Status            BalanceDiffStatus
+	AdjustmentID      *uuid.UUID
+}
+
+type RebuildResult struct {
+	RebuildID       *uuid.UUID
+	Applied         bool
+	Operator        string
+	Reason          string
+	AccountsChecked int
+	SnapshotAt      time.Time
+	FinishedAt      time.Time
+	Diffs           []BalanceDiffResult
+}
+
+// Adjusted counts the differences corrected with an adjustment.
+func (r RebuildResult) Adjusted() int {
+	adjusted := 0
+	for _, diff := range r.Diffs {
+		if diff.Status == BalanceDiffApplied {
+			adjusted++
+		}
+	}
+
+	return adjusted
+}
+
+func ComparisonToBalanceDiffResult(comparison repository.AccountBalanceComparisonResult) BalanceDiffResult {
+	var balance int64
+	if comparison.Balance != nil {
+		balance = *comparison.Balance
+	}
+
+	return BalanceDiffResult{
+		CustomerAccountID: comparison.CustomerAccountID,
+		Balance:           comparison.Balance,
+		RebuiltBalance:    comparison.TransactionsTotal,
+		Difference:        comparison.TransactionsTotal - balance,
+		Status:            BalanceDiffPending,
+	}
+}
@@ -0,0 +1,67 @@
+package reconciliation
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/reconciliations")
+	routeGroup.Get("/drifts", httpHandler.listDrifts)
+	routeGroup.Post("/drifts/:driftId/resolve", httpHandler.resolveDrift)
+}
+
+func (h *httpHandler) listDrifts(c *fiber.Ctx) error {
+	var request listDriftsRequest
+
+	if status := c.Query("status"); status != "" {
+		driftStatus := models.ReconciliationDriftStatus(status)
+		request.Status = &driftStatus
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid query parameters",
+		}, err.FieldErrors...)
+	}
+
+	drifts, err := h.service.ListDrifts(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToDriftsResponse(drifts))
+}
+
+func (h *httpHandler) resolveDrift(c *fiber.Ctx) error {
+	driftID, err := uuid.FromString(c.Params("driftId"))
+	if err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid drift id",
+		})
+	}
+
+	drift, err := h.service.ResolveDrift(c.Context(), resolveDriftRequest{
+		DriftID: &driftID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToDriftResponse(drift))
+}
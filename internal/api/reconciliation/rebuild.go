@@ -0,0 +1,241 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+// RebuildBalances recomputes the balance of every account from its
+// transactions and reports the ones that differ from the stored balance.
+//
+// The differences come from a single repeatable read snapshot, so they are
+// consistent across accounts. When applied, each one is corrected in its own
+// transaction holding the balance lock: the sum is taken again under the lock
+// and the correction is recorded as an adjustment of the rebuild, with a
+// balance.changed event, instead of silently overwriting the balance.
+func (s *service) RebuildBalances(ctx context.Context, request RebuildBalancesRequest) (RebuildResult, error) {
+	result, err := s.diffBalances(ctx)
+	if err != nil {
+		return RebuildResult{}, err
+	}
+
+	if !request.Apply {
+		result.FinishedAt = time.Now()
+
+		return result, nil
+	}
+
+	rebuild, err := s.reconciliationRepository.CreateBalanceRebuild(ctx, models.BalanceRebuild{
+		Operator:        request.Operator,
+		Reason:          request.Reason,
+		AccountsChecked: result.AccountsChecked,
+		SnapshotAt:      result.SnapshotAt,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to create balance rebuild")
+
+		return RebuildResult{}, err
+	}
+
+	result.RebuildID = rebuild.ID
+	result.Applied = true
+	result.Operator = rebuild.Operator
+	result.Reason = rebuild.Reason
+
+	for index, diff := range result.Diffs {
+		result.Diffs[index], err = s.applyBalanceDiff(ctx, rebuild, diff)
+		if err != nil {
+			return RebuildResult{}, err
+		}
+	}
+
+	finishedAt := time.Now()
+	rebuild.AdjustmentsCount = result.Adjusted()
+	rebuild.FinishedAt = &finishedAt
+
+	if _, err := s.reconciliationRepository.UpdateBalanceRebuild(ctx, *rebuild); err != nil {
+		log.Err(err).
+			Str("balance_rebuild_id", rebuild.ID.String()).
+			Msg("failed to finish balance rebuild")
+
+		return RebuildResult{}, err
+	}
+
+	result.FinishedAt = finishedAt
+
+	return result, nil
+}
+
+func (s *service) diffBalances(ctx context.Context) (RebuildResult, error) {
+	var result RebuildResult
+
+	err := s.reconciliationRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		result = RebuildResult{
+			SnapshotAt: time.Now(),
+			Diffs:      []BalanceDiffResult{},
+		}
+
+		var lastCustomerAccountID *uuid.UUID
+		for {
+			comparisons, err := s.reconciliationRepository.CompareAccountBalances(
+				txCtx, lastCustomerAccountID, reconciliationBatchSize,
+			)
+			if err != nil {
+				log.Err(err).Msg("failed to compare account balances")
+
+				return err
+			}
+
+			if len(comparisons) == 0 {
+				return nil
+			}
+
+			for _, comparison := range comparisons {
+				result.AccountsChecked++
+
+				if comparison.Balance != nil && *comparison.Balance == comparison.TransactionsTotal {
+					continue
+				}
+
+				result.Diffs = append(result.Diffs, ComparisonToBalanceDiffResult(comparison))
+			}
+
+			lastCustomerAccountID = comparisons[len(comparisons)-1].CustomerAccountID
+		}
+	}, repository.WithIsolation(sql.LevelRepeatableRead), repository.ReadOnly())
+
+	return result, err
+}
+
+// applyBalanceDiff corrects the balance of the account with the sum of its
+// transactions at the time it's applied, which may differ from the snapshot.
+func (s *service) applyBalanceDiff(
+	ctx context.Context, rebuild *models.BalanceRebuild, diff BalanceDiffResult,
+) (BalanceDiffResult, error) {
+	var result BalanceDiffResult
+
+	err := s.reconciliationRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		accountBalance, err := s.balanceRepository.GetCustomerAccountBalance(txCtx, diff.CustomerAccountID)
+		if err != nil {
+			log.Err(err).
+				Str("customer_account_id", diff.CustomerAccountID.String()).
+				Msg("failed to get account balance")
+
+			return err
+		}
+
+		transactionsTotal, err := s.reconciliationRepository.GetTransactionsTotal(txCtx, diff.CustomerAccountID)
+		if err != nil {
+			log.Err(err).
+				Str("customer_account_id", diff.CustomerAccountID.String()).
+				Msg("failed to sum account transactions")
+
+			return err
+		}
+
+		var previousBalance *int64
+		if accountBalance != nil {
+			balance := accountBalance.Balance
+			previousBalance = &balance
+		}
+
+		result = ComparisonToBalanceDiffResult(repository.AccountBalanceComparisonResult{
+			CustomerAccountID: diff.CustomerAccountID,
+			Balance:           previousBalance,
+			TransactionsTotal: transactionsTotal,
+		})
+
+		if accountBalance != nil && accountBalance.Balance == transactionsTotal {
+			result.Status = BalanceDiffSkipped
+
+			return nil
+		}
+
+		if err := s.correctBalance(txCtx, diff.CustomerAccountID, accountBalance, transactionsTotal); err != nil {
+			return err
+		}
+
+		adjustment, err := s.reconciliationRepository.CreateBalanceAdjustment(txCtx, models.BalanceAdjustment{
+			BalanceRebuildID:  rebuild.ID,
+			CustomerAccountID: diff.CustomerAccountID,
+			PreviousBalance:   previousBalance,
+			RebuiltBalance:    transactionsTotal,
+			Adjustment:        result.Difference,
+		})
+		if err != nil {
+			log.Err(err).
+				Str("customer_account_id", diff.CustomerAccountID.String()).
+				Msg("failed to record balance adjustment")
+
+			return err
+		}
+
+		balanceChanged, err := events.NewOutboxEvent(
+			events.BalanceChanged,
+			diff.CustomerAccountID,
+			events.BalanceChangedPayload{
+				AccountID:       diff.CustomerAccountID,
+				PreviousBalance: utils.FromCents(transactionsTotal - result.Difference),
+				Balance:         utils.FromCents(transactionsTotal),
+				ChangedAt:       adjustment.CreatedAt,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := s.outboxRepository.CreateOutboxEvents(txCtx, balanceChanged); err != nil {
+			log.Err(err).
+				Str("customer_account_id", diff.CustomerAccountID.String()).
+				Msg("failed to record balance rebuild events")
+
+			return err
+		}
+
+		result.Status = BalanceDiffApplied
+		result.AdjustmentID = adjustment.ID
+
+		return nil
+	})
+
+	return result, err
+}
+
+// correctBalance sets the balance to the sum of the transactions, creating
+// the balance row when the account is missing it.
+func (s *service) correctBalance(
+	ctx context.Context, customerAccountID *uuid.UUID, accountBalance *models.Balance, transactionsTotal int64,
+) error {
+	if accountBalance == nil {
+		_, err := s.balanceRepository.CreateCustomerBalance(ctx, models.Balance{
+			CustomerAccountID: customerAccountID,
+			Balance:           transactionsTotal,
+		})
+		if err != nil {
+			log.Err(err).
+				Str("customer_account_id", customerAccountID.String()).
+				Msg("failed to create customer account balance")
+		}
+
+		return err
+	}
+
+	accountBalance.Balance = transactionsTotal
+	if _, err := s.balanceRepository.UpdateCustomerAccountBalance(ctx, *accountBalance); err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Msg("failed to update customer account balance")
+
+		return err
+	}
+
+	return nil
+}
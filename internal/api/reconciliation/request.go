@@ -0,0 +1,26 @@
+package reconciliation
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type ReconcileRequest struct {
+	BlockAccounts bool
+}
+
+type listDriftsRequest struct {
+	Status *models.ReconciliationDriftStatus `validate:"omitempty,oneof=open resolved"`
+}
+
+type resolveDriftRequest struct {
+	DriftID *uuid.UUID
+}
+
+// RebuildBalancesRequest only reports the differences unless Apply is set;
+// applying them needs the operator and the reason to audit the adjustments.
+type RebuildBalancesRequest struct {
+	Apply    bool
+	Operator string `validate:"required_if=Apply true"`
+	Reason   string `validate:"required_if=Apply true"`
+}
@@ -0,0 +1,127 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+type DriftResponse struct {
+	ID                *uuid.UUID                       `json:"id"`
+	RunID             *uuid.UUID                       `json:"run_id"`
+	CustomerAccountID *uuid.UUID                       `json:"account_id"`
+	Balance           float64                          `json:"balance"`
+	TransactionsTotal float64                          `json:"transactions_total"`
+	Drift             float64                          `json:"drift"`
+	Status            models.ReconciliationDriftStatus `json:"status"`
+	AccountBlocked    bool                             `json:"account_blocked"`
+	ResolvedAt        *time.Time                       `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time                        `json:"created_at"`
+}
+
+type ReportResponse struct {
+	RunID           *uuid.UUID      `json:"run_id"`
+	AccountsChecked int             `json:"accounts_checked"`
+	DriftsFound     int             `json:"drifts_found"`
+	BlockAccounts   bool            `json:"block_accounts"`
+	StartedAt       time.Time       `json:"started_at"`
+	FinishedAt      time.Time       `json:"finished_at"`
+	Drifts          []DriftResponse `json:"drifts"`
+}
+
+func DomainToDriftResponse(result DriftResult) DriftResponse {
+	return DriftResponse{
+		ID:                result.ID,
+		RunID:             result.RunID,
+		CustomerAccountID: result.CustomerAccountID,
+		Balance:           utils.FromCents(result.Balance),
+		TransactionsTotal: utils.FromCents(result.TransactionsTotal),
+		Drift:             utils.FromCents(result.Drift),
+		Status:            result.Status,
+		AccountBlocked:    result.AccountBlocked,
+		ResolvedAt:        result.ResolvedAt,
+		CreatedAt:         result.CreatedAt,
+	}
+}
+
+func DomainToDriftsResponse(results []DriftResult) []DriftResponse {
+	response := make([]DriftResponse, 0, len(results))
+	for _, result := range results {
+		response = append(response, DomainToDriftResponse(result))
+	}
+
+	return response
+}
+
+func DomainToReportResponse(result ReportResult) ReportResponse {
+	return ReportResponse{
+		RunID:           result.RunID,
+		AccountsChecked: result.AccountsChecked,
+		DriftsFound:     len(result.Drifts),
+		BlockAccounts:   result.BlockAccounts,
+		StartedAt:       result.StartedAt,
+		FinishedAt:      result.FinishedAt,
+		Drifts:          DomainToDriftsResponse(result.Drifts),
+	}
+}
+
+type BalanceDiffResponse struct {
+	CustomerAccountID *uuid.UUID        `json:"account_id"`
+	Balance           *float64          `json:"balance"`
+	RebuiltBalance    float64           `json:"rebuilt_balance"`
+	Difference        float64           `json:"difference"`
+	Status            BalanceDiffStatus `json:"status"`
+	AdjustmentID      *uuid.UUID        `json:"adjustment_id,omitempty"`
+}
+
+type RebuildResponse struct {
+	RebuildID       *uuid.UUID            `json:"rebuild_id,omitempty"`
+	Applied         bool                  `json:"applied"`
+	Operator        string                `json:"operator,omitempty"`
+	Reason          string                `json:"reason,omitempty"`
+	AccountsChecked int                   `json:"accounts_checked"`
+	DiffsFound      int                   `json:"diffs_found"`
+	Adjusted        int                   `json:"adjusted"`
+	SnapshotAt      time.Time             `json:"snapshot_at"`
+	FinishedAt      time.Time             `json:"finished_at"`
+	Diffs           []BalanceDiffResponse `json:"diffs"`
+}
+
+func DomainToBalanceDiffResponse(result BalanceDiffResult) BalanceDiffResponse {
+	response := BalanceDiffResponse{
+		CustomerAccountID: result.CustomerAccountID,
+		RebuiltBalance:    utils.FromCents(result.RebuiltBalance),
+		Difference:        utils.FromCents(result.Difference),
+		Status:            result.Status,
+		AdjustmentID:      result.AdjustmentID,
+	}
+
+	if result.Balance != nil {
+		balance := utils.FromCents(*result.Balance)
+		response.Balance = &balance
+	}
+
+	return response
+}
+
+func DomainToRebuildResponse(result RebuildResult) RebuildResponse {
+	diffs := make([]BalanceDiffResponse, 0, len(result.Diffs))
+	for _, diff := range result.Diffs {
+		diffs = append(diffs, DomainToBalanceDiffResponse(diff))
+	}
+
+	return RebuildResponse{
+		RebuildID:       result.RebuildID,
+		Applied:         result.Applied,
+		Operator:        result.Operator,
+		Reason:          result.Reason,
+		AccountsChecked: result.AccountsChecked,
+		DiffsFound:      len(result.Diffs),
+		Adjusted:        result.Adjusted(),
+		SnapshotAt:      result.SnapshotAt,
+		FinishedAt:      result.FinishedAt,
+		Diffs:           diffs,
+	}
+}
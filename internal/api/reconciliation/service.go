@@ -0,0 +1,272 @@
+package reconciliation
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const reconciliationBatchSize = 500
+
+type Servicer interface {
+	Reconcile(context.Context, ReconcileRequest) (ReportResult, error)
+	ListDrifts(context.Context, listDriftsRequest) ([]DriftResult, error)
+	ResolveDrift(context.Context, resolveDriftRequest) (DriftResult, error)
+	RebuildBalances(context.Context, RebuildBalancesRequest) (RebuildResult, error)
+}
+
+type service struct {
+	reconciliationRepository  repository.ReconciliationRepository
+	customerAccountRepository repository.CustomerAccountRepository
+	balanceRepository         repository.BalanceRepository
+	outboxRepository          repository.OutboxRepository
+}
+
+func NewService(
+	reconciliationRepository repository.ReconciliationRepository,
+	customerAccountRepository repository.CustomerAccountRepository,
+	balanceRepository repository.BalanceRepository,
+	outboxRepository repository.OutboxRepository,
+) Servicer {
+	return &service{
+		reconciliationRepository:  reconciliationRepository,
+		customerAccountRepository: customerAccountRepository,
+		balanceRepository:         balanceRepository,
+		outboxRepository:          outboxRepository,
+	}
+}
+
+// Reconcile compares the stored balance of every account with the sum of its
+// transactions, recording each mismatch as a drift and, when requested,
+// blocking the account until the drift is resolved.
+func (s *service) Reconcile(ctx context.Context, request ReconcileRequest) (ReportResult, error) {
+	run, err := s.reconciliationRepository.CreateReconciliationRun(ctx, models.ReconciliationRun{
+		BlockAccounts: request.BlockAccounts,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to create reconciliation run")
+
+		return ReportResult{}, err
+	}
+
+	report := ReportResult{
+		RunID:         run.ID,
+		BlockAccounts: request.BlockAccounts,
+		StartedAt:     run.StartedAt,
+		Drifts:        []DriftResult{},
+	}
+
+	var lastCustomerAccountID *uuid.UUID
+	for {
+		comparisons, err := s.reconciliationRepository.CompareBalances(ctx, lastCustomerAccountID, reconciliationBatchSize)
+		if err != nil {
+			log.Err(err).
+				Str("reconciliation_run_id", run.ID.String()).
+				Msg("failed to compare balances")
+
+			return ReportResult{}, err
+		}
+
+		if len(comparisons) == 0 {
+			break
+		}
+
+		for _, comparison := range comparisons {
+			report.AccountsChecked++
+
+			if comparison.Balance == comparison.TransactionsTotal {
+				continue
+			}
+
+			drift, err := s.recordDrift(ctx, run, comparison)
+			if err != nil {
+				return ReportResult{}, err
+			}
+
+			report.Drifts = append(report.Drifts, DatabaseToDriftResult(*drift))
+		}
+
+		lastCustomerAccountID = comparisons[len(comparisons)-1].CustomerAccountID
+	}
+
+	finishedAt := time.Now()
+	run.AccountsChecked = report.AccountsChecked
+	run.DriftsFound = len(report.Drifts)
+	run.FinishedAt = &finishedAt
+
+	if _, err := s.reconciliationRepository.UpdateReconciliationRun(ctx, *run); err != nil {
+		log.Err(err).
+			Str("reconciliation_run_id", run.ID.String()).
+			Msg("failed to finish reconciliation run")
+
+		return ReportResult{}, err
+	}
+
+	report.FinishedAt = finishedAt
+
+	return report, nil
+}
+
+func (s *service) recordDrift(
+	ctx context.Context,
+	run *models.ReconciliationRun,
+	comparison repository.BalanceComparisonResult,
+) (*models.ReconciliationDrift, error) {
+	var drift *models.ReconciliationDrift
+
+	err := s.reconciliationRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+
+		drift, err = s.reconciliationRepository.CreateReconciliationDrift(txCtx, models.ReconciliationDrift{
+			ReconciliationRunID: run.ID,
+			CustomerAccountID:   comparison.CustomerAccountID,
+			Balance:             comparison.Balance,
+			TransactionsTotal:   comparison.TransactionsTotal,
+			Drift:               comparison.Balance - comparison.TransactionsTotal,
+			Status:              models.ReconciliationDriftOpen,
+			AccountBlocked:      run.BlockAccounts,
+		})
+		if err != nil {
+			log.Err(err).
+				Str("customer_account_id", comparison.CustomerAccountID.String()).
+				Msg("failed to record reconciliation drift")
+
+			return err
+		}
+
+		if !run.BlockAccounts {
+			return nil
+		}
+
+		blockedAt := time.Now()
+		if err := s.customerAccountRepository.SetCustomerAccountBlockedAt(
+			txCtx, comparison.CustomerAccountID, &blockedAt,
+		); err != nil {
+			log.Err(err).
+				Str("customer_account_id", comparison.CustomerAccountID.String()).
+				Msg("failed to block customer account")
+
+			return err
+		}
+
+		return nil
+	})
+
+	return drift, err
+}
+
+func (s *service) ListDrifts(ctx context.Context, request listDriftsRequest) ([]DriftResult, error) {
+	drifts, err := s.reconciliationRepository.ListReconciliationDrifts(ctx, request.Status)
+	if err != nil {
+		log.Err(err).Msg("failed to list reconciliation drifts")
+
+		return nil, err
+	}
+
+	results := make([]DriftResult, 0, len(drifts))
+	for _, drift := range drifts {
+		results = append(results, DatabaseToDriftResult(drift))
+	}
+
+	return results, nil
+}
+
+// ResolveDrift closes every open drift of the account and lifts its block,
+// once its balance matches the sum of its transactions again. The balance
+// stays locked until the drift is resolved, so no transaction can move it in
+// between.
+func (s *service) ResolveDrift(ctx context.Context, request resolveDriftRequest) (DriftResult, error) {
+	var result DriftResult
+
+	err := s.reconciliationRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		drift, err := s.reconciliationRepository.GetReconciliationDriftByID(txCtx, request.DriftID)
+		if err != nil {
+			log.Err(err).
+				Str("reconciliation_drift_id", request.DriftID.String()).
+				Msg("failed to get reconciliation drift")
+
+			return err
+		}
+
+		if drift == nil {
+			return cerror.New(cerror.Params{
+				Status:  http.StatusNotFound,
+				Message: "Reconciliation drift not found",
+			})
+		}
+
+		if drift.Status == models.ReconciliationDriftResolved {
+			result = DatabaseToDriftResult(*drift)
+
+			return nil
+		}
+
+		if err := s.verifyBalance(txCtx, drift.CustomerAccountID); err != nil {
+			return err
+		}
+
+		resolvedAt := time.Now()
+		if err := s.reconciliationRepository.ResolveOpenReconciliationDrifts(
+			txCtx, drift.CustomerAccountID, resolvedAt,
+		); err != nil {
+			log.Err(err).
+				Str("customer_account_id", drift.CustomerAccountID.String()).
+				Msg("failed to resolve reconciliation drifts")
+
+			return err
+		}
+
+		if err := s.customerAccountRepository.SetCustomerAccountBlockedAt(txCtx, drift.CustomerAccountID, nil); err != nil {
+			log.Err(err).
+				Str("customer_account_id", drift.CustomerAccountID.String()).
+				Msg("failed to unblock customer account")
+
+			return err
+		}
+
+		drift.Status = models.ReconciliationDriftResolved
+		drift.ResolvedAt = &resolvedAt
+		result = DatabaseToDriftResult(*drift)
+
+		return nil
+	})
+
+	return result, err
+}
+
+// verifyBalance checks that the balance of the account matches the sum of its
+// transactions, locking the balance for the rest of the transaction.
+func (s *service) verifyBalance(ctx context.Context, customerAccountID *uuid.UUID) error {
+	balance, err := s.balanceRepository.GetCustomerAccountBalance(ctx, customerAccountID)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Msg("failed to get customer account balance")
+
+		return err
+	}
+
+	transactionsTotal, err := s.reconciliationRepository.GetTransactionsTotal(ctx, customerAccountID)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Msg("failed to sum customer account transactions")
+
+		return err
+	}
+
+	if balance == nil || balance.Balance != transactionsTotal {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusConflict,
+			Message: "Account balance still doesn't match its transactions",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package schedules
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type ScheduleResult struct {
+	ID                      *uuid.UUID
+	CustomerAccountID       *uuid.UUID
+	OperationType           models.OperationType
+	Amount                  int64
+	StartAt                 time.Time
+	RRule                   *string
+	InsufficientFundsPolicy models.InsufficientFundsPolicy
+	Status                  models.TransactionScheduleStatus
+	NextOccurrence          int
+	NextRunAt               *time.Time
+	Attempts                int
+	LastError               *string
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+	Runs                    []RunResult
+}
+
+type RunResult struct {
+	Occurrence    int
+	ScheduledFor  time.Time
+	Status        models.TransactionScheduleRunStatus
+	TransactionID *uuid.UUID
+	Attempts      int
+	Error         *string
+	CreatedAt     time.Time
+}
+
+type RunDueResult struct {
+	Created int
+	Skipped int
+	Retried int
+	Failed  int
+}
+
+func DatabaseToScheduleResult(schedule models.TransactionSchedule) ScheduleResult {
+	return ScheduleResult{
+		ID:                      schedule.ID,
+		CustomerAccountID:       schedule.CustomerAccountID,
+		OperationType:           schedule.OperationType,
+		Amount:                  schedule.Amount,
+		StartAt:                 schedule.StartAt,
+		RRule:                   schedule.RRule,
+		InsufficientFundsPolicy: schedule.InsufficientFundsPolicy,
+		Status:                  schedule.Status,
+		NextOccurrence:          schedule.NextOccurrence,
+		NextRunAt:               schedule.NextRunAt,
+		Attempts:                schedule.Attempts,
+		LastError:               schedule.LastError,
+		CreatedAt:               schedule.CreatedAt,
+		UpdatedAt:               schedule.UpdatedAt,
+	}
+}
+
+func DatabaseToRunResult(run models.TransactionScheduleRun) RunResult {
+	return RunResult{
+		Occurrence:    run.Occurrence,
+		ScheduledFor:  run.ScheduledFor,
+		Status:        run.Status,
+		TransactionID: run.TransactionID,
+		Attempts:      run.Attempts,
+		Error:         run.Error,
+		CreatedAt:     run.CreatedAt,
+	}
+}
@@ -0,0 +1,169 @@
+package schedules
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/schedules")
+	routeGroup.Post("/", httpHandler.createSchedule)
+	routeGroup.Get("/", httpHandler.listSchedules)
+	routeGroup.Get("/:scheduleId", httpHandler.getSchedule)
+	routeGroup.Post("/:scheduleId/pause", httpHandler.pauseSchedule)
+	routeGroup.Post("/:scheduleId/resume", httpHandler.resumeSchedule)
+	routeGroup.Post("/:scheduleId/cancel", httpHandler.cancelSchedule)
+}
+
+func (h *httpHandler) createSchedule(c *fiber.Ctx) error {
+	var body createScheduleRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid schedule payload",
+		})
+	}
+
+	if err := validator.ValidateStruct(body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	schedule, err := h.service.CreateSchedule(c.Context(), body)
+	if err != nil {
+		log.Err(err).Msg("failed to create schedule")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToScheduleResponse(schedule))
+}
+
+func (h *httpHandler) listSchedules(c *fiber.Ctx) error {
+	var request listSchedulesRequest
+
+	if accountID := c.Query("account_id"); accountID != "" {
+		customerAccountID, err := uuid.FromString(accountID)
+		if err != nil {
+			return cerror.New(cerror.Params{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid query parameters",
+			}, cerror.FieldError{
+				Field:   "account_id",
+				Message: "account_id must be a valid UUID",
+			})
+		}
+
+		request.CustomerAccountID = &customerAccountID
+	}
+
+	if status := c.Query("status"); status != "" {
+		scheduleStatus := models.TransactionScheduleStatus(status)
+		request.Status = &scheduleStatus
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid query parameters",
+		}, err.FieldErrors...)
+	}
+
+	schedules, err := h.service.ListSchedules(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToSchedulesResponse(schedules))
+}
+
+func (h *httpHandler) getSchedule(c *fiber.Ctx) error {
+	request, err := parseScheduleRequest(c)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.GetSchedule(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToScheduleResponse(schedule))
+}
+
+func (h *httpHandler) pauseSchedule(c *fiber.Ctx) error {
+	request, err := parseScheduleRequest(c)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.PauseSchedule(c.Context(), request)
+	if err != nil {
+		log.Err(err).Msg("failed to pause schedule")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToScheduleResponse(schedule))
+}
+
+func (h *httpHandler) resumeSchedule(c *fiber.Ctx) error {
+	request, err := parseScheduleRequest(c)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.ResumeSchedule(c.Context(), request)
+	if err != nil {
+		log.Err(err).Msg("failed to resume schedule")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToScheduleResponse(schedule))
+}
+
+func (h *httpHandler) cancelSchedule(c *fiber.Ctx) error {
+	request, err := parseScheduleRequest(c)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.CancelSchedule(c.Context(), request)
+	if err != nil {
+		log.Err(err).Msg("failed to cancel schedule")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToScheduleResponse(schedule))
+}
+
+func parseScheduleRequest(c *fiber.Ctx) (scheduleRequest, error) {
+	scheduleID, err := uuid.FromString(c.Params("scheduleId"))
+	if err != nil {
+		return scheduleRequest{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid schedule id",
+		})
+	}
+
+	return scheduleRequest{ScheduleID: &scheduleID}, nil
+}
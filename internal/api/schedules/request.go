@@ -0,0 +1,28 @@
+package schedules
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type createScheduleRequest struct {
+	CustomerAccountID *uuid.UUID           `json:"account_id" validate:"required"`
+	OperationType     models.OperationType `json:"operation_type" validate:"required"`
+	Amount            float64              `json:"amount" validate:"required,gte=0.01"`
+	StartAt           *time.Time           `json:"start_at" validate:"required"`
+	// RRule makes the schedule recurring; without it the transaction is
+	// posted once, at StartAt.
+	RRule                   *string                         `json:"rrule" validate:"omitempty,max=255"`
+	InsufficientFundsPolicy *models.InsufficientFundsPolicy `json:"insufficient_funds_policy" validate:"omitempty,oneof=retry skip"`
+}
+
+type listSchedulesRequest struct {
+	CustomerAccountID *uuid.UUID
+	Status            *models.TransactionScheduleStatus `validate:"omitempty,oneof=active paused cancelled completed"`
+}
+
+type scheduleRequest struct {
+	ScheduleID *uuid.UUID
+}
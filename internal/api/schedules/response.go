@@ -0,0 +1,85 @@
+package schedules
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+type ScheduleResponse struct {
+	ID                      *uuid.UUID                       `json:"id"`
+	CustomerAccountID       *uuid.UUID                       `json:"account_id"`
+	OperationType           models.OperationType             `json:"operation_type"`
+	Amount                  float64                          `json:"amount"`
+	StartAt                 time.Time                        `json:"start_at"`
+	RRule                   *string                          `json:"rrule"`
+	InsufficientFundsPolicy models.InsufficientFundsPolicy   `json:"insufficient_funds_policy"`
+	Status                  models.TransactionScheduleStatus `json:"status"`
+	NextOccurrence          int                              `json:"next_occurrence"`
+	NextRunAt               *time.Time                       `json:"next_run_at"`
+	Attempts                int                              `json:"attempts"`
+	LastError               *string                          `json:"last_error"`
+	CreatedAt               time.Time                        `json:"created_at"`
+	UpdatedAt               time.Time                        `json:"updated_at"`
+	Runs                    []RunResponse                    `json:"runs,omitempty"`
+}
+
+type RunResponse struct {
+	Occurrence    int                                 `json:"occurrence"`
+	ScheduledFor  time.Time                           `json:"scheduled_for"`
+	Status        models.TransactionScheduleRunStatus `json:"status"`
+	TransactionID *uuid.UUID                          `json:"transaction_id"`
+	Attempts      int                                 `json:"attempts"`
+	Error         *string                             `json:"error"`
+	CreatedAt     time.Time                           `json:"created_at"`
+}
+
+type SchedulesResponse struct {
+	Schedules []ScheduleResponse `json:"schedules"`
+}
+
+func DomainToScheduleResponse(result ScheduleResult) ScheduleResponse {
+	var runs []RunResponse
+
+	for _, run := range result.Runs {
+		runs = append(runs, RunResponse{
+			Occurrence:    run.Occurrence,
+			ScheduledFor:  run.ScheduledFor,
+			Status:        run.Status,
+			TransactionID: run.TransactionID,
+			Attempts:      run.Attempts,
+			Error:         run.Error,
+			CreatedAt:     run.CreatedAt,
+		})
+	}
+
+	return ScheduleResponse{
+		ID:                      result.ID,
+		CustomerAccountID:       result.CustomerAccountID,
+		OperationType:           result.OperationType,
+		Amount:                  utils.FromCents(result.Amount),
+		StartAt:                 result.StartAt,
+		RRule:                   result.RRule,
+		InsufficientFundsPolicy: result.InsufficientFundsPolicy,
+		Status:                  result.Status,
+		NextOccurrence:          result.NextOccurrence,
+		NextRunAt:               result.NextRunAt,
+		Attempts:                result.Attempts,
+		LastError:               result.LastError,
+		CreatedAt:               result.CreatedAt,
+		UpdatedAt:               result.UpdatedAt,
+		Runs:                    runs,
+	}
+}
+
+func DomainToSchedulesResponse(results []ScheduleResult) SchedulesResponse {
+	schedules := make([]ScheduleResponse, 0, len(results))
+
+	for _, result := range results {
+		schedules = append(schedules, DomainToScheduleResponse(result))
+	}
+
+	return SchedulesResponse{Schedules: schedules}
+}
@@ -0,0 +1,591 @@
+package schedules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/rrule"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	defaultBatchSize     = 50
+	defaultMaxAttempts   = 3
+	defaultRetryInterval = time.Hour
+
+	// scheduleLease is how long a claimed schedule is kept from other workers
+	// while its occurrence is being posted.
+	scheduleLease = 5 * time.Minute
+)
+
+// Servicer manages the schedules of future and recurring transactions.
+// RunDue posts their due occurrences through the transactions service.
+type Servicer interface {
+	CreateSchedule(context.Context, createScheduleRequest) (ScheduleResult, error)
+	ListSchedules(context.Context, listSchedulesRequest) ([]ScheduleResult, error)
+	GetSchedule(context.Context, scheduleRequest) (ScheduleResult, error)
+	PauseSchedule(context.Context, scheduleRequest) (ScheduleResult, error)
+	ResumeSchedule(context.Context, scheduleRequest) (ScheduleResult, error)
+	CancelSchedule(context.Context, scheduleRequest) (ScheduleResult, error)
+	RunDue(context.Context) (RunDueResult, error)
+}
+
+// Options tunes the worker. Zero values fall back to the defaults.
+type Options struct {
+	// BatchSize is how many due schedules are claimed at a time.
+	BatchSize int
+	// MaxAttempts is how many times an occurrence refused for insufficient
+	// funds is tried, under the retry policy, before it's failed.
+	MaxAttempts int
+	// RetryInterval is how long to wait before trying such an occurrence
+	// again.
+	RetryInterval time.Duration
+	// Location is where the occurrences of recurring schedules are computed,
+	// so they keep their local day and time of day. Defaults to UTC.
+	Location *time.Location
+}
+
+type service struct {
+	transactionScheduleRepository repository.TransactionScheduleRepository
+	customerAccountRepository     repository.CustomerAccountRepository
+	transactionsService           transactions.Servicer
+	operationTypes                operationtypes.Registry
+	options                       Options
+}
+
+func NewService(
+	transactionScheduleRepository repository.TransactionScheduleRepository,
+	customerAccountRepository repository.CustomerAccountRepository,
+	transactionsService transactions.Servicer,
+	operationTypes operationtypes.Registry,
+	options Options,
+) Servicer {
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBatchSize
+	}
+
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = defaultRetryInterval
+	}
+
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+
+	return &service{
+		transactionScheduleRepository: transactionScheduleRepository,
+		customerAccountRepository:     customerAccountRepository,
+		transactionsService:           transactionsService,
+		operationTypes:                operationTypes,
+		options:                       options,
+	}
+}
+
+func (s *service) CreateSchedule(ctx context.Context, request createScheduleRequest) (ScheduleResult, error) {
+	schedule := models.TransactionSchedule{
+		CustomerAccountID:       request.CustomerAccountID,
+		OperationType:           request.OperationType,
+		Amount:                  utils.ToCents(request.Amount),
+		StartAt:                 *request.StartAt,
+		InsufficientFundsPolicy: models.InsufficientFundsRetry,
+		Status:                  models.TransactionScheduleActive,
+	}
+
+	if request.RRule != nil && *request.RRule != "" {
+		schedule.RRule = request.RRule
+	}
+
+	if request.InsufficientFundsPolicy != nil {
+		schedule.InsufficientFundsPolicy = *request.InsufficientFundsPolicy
+	}
+
+	if err := s.validateSchedule(schedule); err != nil {
+		return ScheduleResult{}, err
+	}
+
+	if err := s.checkCustomerAccount(ctx, schedule.CustomerAccountID); err != nil {
+		return ScheduleResult{}, err
+	}
+
+	firstRunAt, _, err := s.occurrence(schedule, 0)
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+
+	schedule.NextRunAt = &firstRunAt
+
+	created, err := s.transactionScheduleRepository.CreateTransactionSchedule(ctx, schedule)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", schedule.CustomerAccountID.String()).
+			Msg("failed to create transaction schedule")
+
+		return ScheduleResult{}, err
+	}
+
+	return DatabaseToScheduleResult(*created), nil
+}
+
+func (s *service) ListSchedules(ctx context.Context, request listSchedulesRequest) ([]ScheduleResult, error) {
+	schedules, err := s.transactionScheduleRepository.ListTransactionSchedules(ctx, repository.TransactionScheduleFilter{
+		CustomerAccountID: request.CustomerAccountID,
+		Status:            request.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ScheduleResult, 0, len(schedules))
+	for _, schedule := range schedules {
+		results = append(results, DatabaseToScheduleResult(schedule))
+	}
+
+	return results, nil
+}
+
+// GetSchedule returns the schedule with the outcome of each of its past
+// occurrences.
+func (s *service) GetSchedule(ctx context.Context, request scheduleRequest) (ScheduleResult, error) {
+	schedule, err := s.transactionScheduleRepository.GetTransactionSchedule(ctx, request.ScheduleID)
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+
+	if schedule == nil {
+		return ScheduleResult{}, scheduleNotFoundError()
+	}
+
+	runs, err := s.transactionScheduleRepository.ListTransactionScheduleRuns(ctx, schedule.ID)
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+
+	result := DatabaseToScheduleResult(*schedule)
+	for _, run := range runs {
+		result.Runs = append(result.Runs, DatabaseToRunResult(run))
+	}
+
+	return result, nil
+}
+
+func (s *service) PauseSchedule(ctx context.Context, request scheduleRequest) (ScheduleResult, error) {
+	return s.changeSchedule(ctx, request.ScheduleID, func(schedule *models.TransactionSchedule) error {
+		if schedule.Status != models.TransactionScheduleActive {
+			return invalidTransitionError(schedule.Status, "paused")
+		}
+
+		schedule.Status = models.TransactionSchedulePaused
+		schedule.NextRunAt = nil
+
+		return nil
+	})
+}
+
+// ResumeSchedule reactivates a paused schedule. The occurrences of a
+// recurring schedule that were missed while it was paused are skipped; a
+// one-off schedule whose date has passed is posted right away.
+func (s *service) ResumeSchedule(ctx context.Context, request scheduleRequest) (ScheduleResult, error) {
+	return s.changeSchedule(ctx, request.ScheduleID, func(schedule *models.TransactionSchedule) error {
+		if schedule.Status != models.TransactionSchedulePaused {
+			return invalidTransitionError(schedule.Status, "resumed")
+		}
+
+		now := time.Now()
+
+		for {
+			nextRunAt, ok, err := s.occurrence(*schedule, schedule.NextOccurrence)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				schedule.Status = models.TransactionScheduleCompleted
+				schedule.NextRunAt = nil
+
+				return nil
+			}
+
+			if schedule.RRule == nil || !nextRunAt.Before(now) {
+				if nextRunAt.Before(now) {
+					nextRunAt = now
+				}
+
+				schedule.Status = models.TransactionScheduleActive
+				schedule.NextRunAt = &nextRunAt
+
+				return nil
+			}
+
+			schedule.NextOccurrence++
+			schedule.Attempts = 0
+			schedule.LastError = nil
+		}
+	})
+}
+
+// CancelSchedule stops the schedule for good. An occurrence being posted
+// while it's cancelled is still recorded.
+func (s *service) CancelSchedule(ctx context.Context, request scheduleRequest) (ScheduleResult, error) {
+	return s.changeSchedule(ctx, request.ScheduleID, func(schedule *models.TransactionSchedule) error {
+		if schedule.Status != models.TransactionScheduleActive && schedule.Status != models.TransactionSchedulePaused {
+			return invalidTransitionError(schedule.Status, "cancelled")
+		}
+
+		schedule.Status = models.TransactionScheduleCancelled
+		schedule.NextRunAt = nil
+
+		return nil
+	})
+}
+
+// RunDue posts the due occurrences until none is left, BatchSize schedules at
+// a time. Each occurrence is posted with an idempotency key of its own, so
+// one whose outcome couldn't be recorded is never posted twice.
+//
+// An occurrence refused for insufficient funds is skipped or retried
+// according to the policy of the schedule; any other refusal fails it. Either
+// way the schedule moves on to its next occurrence. An unexpected error
+// leaves the occurrence to be tried again once the lease of the schedule ends.
+func (s *service) RunDue(ctx context.Context) (RunDueResult, error) {
+	var result RunDueResult
+
+	for {
+		schedules, err := s.transactionScheduleRepository.ClaimDueTransactionSchedules(
+			ctx, s.options.BatchSize, scheduleLease,
+		)
+		if err != nil {
+			return result, err
+		}
+
+		if len(schedules) == 0 {
+			return result, nil
+		}
+
+		for _, schedule := range schedules {
+			if err := s.runOccurrence(ctx, schedule, &result); err != nil {
+				log.Err(err).
+					Str("schedule_id", schedule.ID.String()).
+					Int("occurrence", schedule.NextOccurrence).
+					Msg("failed to run transaction schedule")
+			}
+		}
+	}
+}
+
+// runOccurrence posts the next occurrence of a claimed schedule and records
+// its outcome.
+func (s *service) runOccurrence(
+	ctx context.Context, schedule models.TransactionSchedule, result *RunDueResult,
+) error {
+	scheduledFor, ok, err := s.occurrence(schedule, schedule.NextOccurrence)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return s.advanceSchedule(ctx, schedule.ID, schedule.NextOccurrence, nil)
+	}
+
+	idempotencyKey := fmt.Sprintf("schedule:%s:%d", schedule.ID, schedule.NextOccurrence)
+
+	created, postErr := s.transactionsService.CreateTransaction(ctx, transactions.CreateTransactionRequest{
+		CustomerAccountID: schedule.CustomerAccountID,
+		OperationType:     schedule.OperationType,
+		Amount:            utils.FromCents(schedule.Amount),
+		IdempotencyKey:    &idempotencyKey,
+	})
+
+	run := models.TransactionScheduleRun{
+		TransactionScheduleID: schedule.ID,
+		Occurrence:            schedule.NextOccurrence,
+		ScheduledFor:          scheduledFor,
+		Attempts:              schedule.Attempts + 1,
+	}
+
+	var customErr *cerror.Error
+
+	switch {
+	case postErr == nil:
+		run.Status = models.TransactionScheduleRunCreated
+		run.TransactionID = created.ID
+	case transactions.IsInsufficientFunds(postErr) &&
+		schedule.InsufficientFundsPolicy == models.InsufficientFundsRetry &&
+		run.Attempts < s.options.MaxAttempts:
+		if err := s.retryOccurrence(ctx, run, postErr.Error()); err != nil {
+			return err
+		}
+
+		result.Retried++
+
+		return nil
+	case transactions.IsInsufficientFunds(postErr) &&
+		schedule.InsufficientFundsPolicy == models.InsufficientFundsSkip:
+		run.Status = models.TransactionScheduleRunSkipped
+		run.Error = errorMessage(postErr)
+	case errors.As(postErr, &customErr) && isRefusal(customErr):
+		run.Status = models.TransactionScheduleRunFailed
+		run.Error = errorMessage(postErr)
+	default:
+		return postErr
+	}
+
+	if err := s.advanceSchedule(ctx, schedule.ID, schedule.NextOccurrence, &run); err != nil {
+		return err
+	}
+
+	switch run.Status {
+	case models.TransactionScheduleRunCreated:
+		result.Created++
+	case models.TransactionScheduleRunSkipped:
+		result.Skipped++
+	case models.TransactionScheduleRunFailed:
+		result.Failed++
+	}
+
+	return nil
+}
+
+// advanceSchedule records the run of an occurrence, when there's one, and
+// moves the schedule on to its next occurrence, completing it when there's
+// none left.
+func (s *service) advanceSchedule(
+	ctx context.Context, scheduleID *uuid.UUID, occurrence int, run *models.TransactionScheduleRun,
+) error {
+	return s.transactionScheduleRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		schedule, err := s.transactionScheduleRepository.GetTransactionScheduleForUpdate(txCtx, scheduleID)
+		if err != nil {
+			return err
+		}
+
+		if run != nil {
+			if _, err := s.transactionScheduleRepository.CreateTransactionScheduleRun(txCtx, *run); err != nil {
+				return err
+			}
+		}
+
+		// The schedule already moved past the occurrence, e.g. it was resumed
+		// after it or another worker recorded it once the lease ended.
+		if schedule.NextOccurrence != occurrence {
+			return nil
+		}
+
+		schedule.NextOccurrence++
+		schedule.Attempts = 0
+		schedule.LastError = nil
+
+		if run != nil {
+			schedule.LastError = run.Error
+		}
+
+		nextRunAt, ok, err := s.occurrence(*schedule, schedule.NextOccurrence)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !ok:
+			if schedule.Status != models.TransactionScheduleCancelled {
+				schedule.Status = models.TransactionScheduleCompleted
+			}
+
+			schedule.NextRunAt = nil
+		case schedule.Status == models.TransactionScheduleActive:
+			schedule.NextRunAt = &nextRunAt
+		}
+
+		return s.transactionScheduleRepository.UpdateTransactionSchedule(txCtx, *schedule)
+	})
+}
+
+// retryOccurrence reschedules an occurrence refused for insufficient funds
+// RetryInterval from now.
+func (s *service) retryOccurrence(ctx context.Context, run models.TransactionScheduleRun, lastError string) error {
+	return s.transactionScheduleRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		schedule, err := s.transactionScheduleRepository.GetTransactionScheduleForUpdate(txCtx, run.TransactionScheduleID)
+		if err != nil {
+			return err
+		}
+
+		if schedule.NextOccurrence != run.Occurrence {
+			return nil
+		}
+
+		schedule.Attempts = run.Attempts
+		schedule.LastError = &lastError
+
+		if schedule.Status == models.TransactionScheduleActive {
+			nextRunAt := time.Now().Add(s.options.RetryInterval)
+			schedule.NextRunAt = &nextRunAt
+		}
+
+		return s.transactionScheduleRepository.UpdateTransactionSchedule(txCtx, *schedule)
+	})
+}
+
+// changeSchedule applies a change of state to the schedule, locked so it
+// doesn't race the worker.
+func (s *service) changeSchedule(
+	ctx context.Context, scheduleID *uuid.UUID, change func(*models.TransactionSchedule) error,
+) (ScheduleResult, error) {
+	err := s.transactionScheduleRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		schedule, err := s.transactionScheduleRepository.GetTransactionScheduleForUpdate(txCtx, scheduleID)
+		if err != nil {
+			return err
+		}
+
+		if schedule == nil {
+			return scheduleNotFoundError()
+		}
+
+		if err := change(schedule); err != nil {
+			return err
+		}
+
+		return s.transactionScheduleRepository.UpdateTransactionSchedule(txCtx, *schedule)
+	})
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+
+	return s.GetSchedule(ctx, scheduleRequest{ScheduleID: scheduleID})
+}
+
+// occurrence returns when occurrence n of the schedule is due, and whether the
+// schedule has it. A schedule without a rule has a single occurrence.
+func (s *service) occurrence(schedule models.TransactionSchedule, n int) (time.Time, bool, error) {
+	if schedule.RRule == nil {
+		return schedule.StartAt, n == 0, nil
+	}
+
+	rule, err := rrule.Parse(*schedule.RRule)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	occurrence, ok := rule.Occurrence(schedule.StartAt.In(s.options.Location), n)
+
+	return occurrence, ok, nil
+}
+
+func (s *service) validateSchedule(schedule models.TransactionSchedule) error {
+	var fieldErrors []cerror.FieldError
+
+	operationType, ok := s.operationTypes.Lookup(schedule.OperationType)
+	switch {
+	case !ok:
+		fieldErrors = append(fieldErrors, cerror.FieldError{
+			Field:   "operation_type",
+			Message: "Unknown operation type",
+		})
+	case !operationType.Enabled:
+		fieldErrors = append(fieldErrors, cerror.FieldError{
+			Field:   "operation_type",
+			Message: "Operation type is disabled",
+		})
+	case !operationType.AllowsAmount(schedule.Amount):
+		fieldErrors = append(fieldErrors, cerror.FieldError{
+			Field:   "amount",
+			Message: "Amount is outside the limits of the operation type",
+		})
+	}
+
+	if !schedule.StartAt.After(time.Now()) {
+		fieldErrors = append(fieldErrors, cerror.FieldError{
+			Field:   "start_at",
+			Message: "Start must be in the future",
+		})
+	}
+
+	if schedule.RRule != nil {
+		if rule, err := rrule.Parse(*schedule.RRule); err != nil {
+			fieldErrors = append(fieldErrors, cerror.FieldError{
+				Field:   "rrule",
+				Message: err.Error(),
+			})
+		} else if _, ok := rule.Occurrence(schedule.StartAt.In(s.options.Location), 0); !ok {
+			fieldErrors = append(fieldErrors, cerror.FieldError{
+				Field:   "rrule",
+				Message: "Rule has no occurrence after the start",
+			})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, fieldErrors...)
+	}
+
+	return nil
+}
+
+func (s *service) checkCustomerAccount(ctx context.Context, customerAccountID *uuid.UUID) error {
+	customerAccount, err := s.customerAccountRepository.SearchCustomerAccountByID(ctx, customerAccountID)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Msg("failed to find customer account")
+
+		return err
+	}
+
+	if customerAccount == nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Customer account not found",
+		})
+	}
+
+	if customerAccount.BlockedAt != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusLocked,
+			Message: "Customer account is blocked",
+		})
+	}
+
+	return nil
+}
+
+// isRefusal tells whether the transactions service refused the occurrence
+// for good, as opposed to a conflict that goes away on its own, such as its
+// idempotency key being held by a concurrent attempt.
+func isRefusal(err *cerror.Error) bool {
+	return err.Status >= http.StatusBadRequest &&
+		err.Status < http.StatusInternalServerError &&
+		err.Status != http.StatusConflict
+}
+
+func errorMessage(err error) *string {
+	message := err.Error()
+
+	return &message
+}
+
+func invalidTransitionError(status models.TransactionScheduleStatus, transition string) error {
+	return cerror.New(cerror.Params{
+		Status:  http.StatusConflict,
+		Message: fmt.Sprintf("Schedule is %s and can't be %s", status, transition),
+	})
+}
+
+func scheduleNotFoundError() error {
+	return cerror.New(cerror.Params{
+		Status:  http.StatusNotFound,
+		Message: "Schedule not found",
+	})
+}
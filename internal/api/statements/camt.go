@@ -0,0 +1,286 @@
+package statements
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/paemuri/brdoc"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+// camtNamespace is the namespace of ISO 20022 camt.053.001.08 bank to
+// customer statements.
+const camtNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+const (
+	camtCredit = "CRDT"
+	camtDebit  = "DBIT"
+
+	// camtMaxText is the length of Max35Text, the type of most identifiers
+	// and codes.
+	camtMaxText = 35
+)
+
+type camtDocument struct {
+	XMLName   xml.Name      `xml:"Document"`
+	Namespace string        `xml:"xmlns,attr"`
+	Statement camtStatement `xml:"BkToCstmrStmt"`
+}
+
+type camtStatement struct {
+	GroupHeader camtGroupHeader      `xml:"GrpHdr"`
+	Statement   camtAccountStatement `xml:"Stmt"`
+}
+
+type camtGroupHeader struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type camtAccountStatement struct {
+	ID        string        `xml:"Id"`
+	CreatedAt string        `xml:"CreDtTm"`
+	Period    camtPeriod    `xml:"FrToDt"`
+	Account   camtAccount   `xml:"Acct"`
+	Balances  []camtBalance `xml:"Bal"`
+	Summary   camtSummary   `xml:"TxsSummry"`
+	Entries   []camtEntry   `xml:"Ntry"`
+}
+
+type camtPeriod struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	ID       string       `xml:"Id>Othr>Id"`
+	Type     string       `xml:"Tp>Cd"`
+	Currency string       `xml:"Ccy"`
+	Owner    camtOwner    `xml:"Ownr"`
+	Servicer camtServicer `xml:"Svcr"`
+}
+
+// camtOwner identifies the owner of the account by their document, as an
+// organisation for CNPJs and as a person for CPFs.
+type camtOwner struct {
+	Organisation *camtGenericID `xml:"Id>OrgId>Othr,omitempty"`
+	Person       *camtGenericID `xml:"Id>PrvtId>Othr,omitempty"`
+}
+
+type camtGenericID struct {
+	ID     string `xml:"Id"`
+	Scheme string `xml:"SchmeNm>Cd"`
+}
+
+type camtServicer struct {
+	ID string `xml:"FinInstnId>Othr>Id"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt,omitempty"`
+	DateTime string `xml:"DtTm,omitempty"`
+}
+
+type camtBalance struct {
+	Type             string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount           camtAmount `xml:"Amt"`
+	CreditDebitIndic string     `xml:"CdtDbtInd"`
+	Date             camtDate   `xml:"Dt"`
+}
+
+type camtSummary struct {
+	Entries       camtEntriesTotal `xml:"TtlNtries"`
+	CreditEntries camtEntriesSum   `xml:"TtlCdtNtries"`
+	DebitEntries  camtEntriesSum   `xml:"TtlDbtNtries"`
+}
+
+type camtEntriesTotal struct {
+	Count            int    `xml:"NbOfNtries"`
+	Sum              string `xml:"Sum"`
+	NetAmount        string `xml:"TtlNetNtry>Amt"`
+	CreditDebitIndic string `xml:"TtlNetNtry>CdtDbtInd"`
+}
+
+type camtEntriesSum struct {
+	Count int    `xml:"NbOfNtries"`
+	Sum   string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	Reference        string           `xml:"NtryRef"`
+	Amount           camtAmount       `xml:"Amt"`
+	CreditDebitIndic string           `xml:"CdtDbtInd"`
+	Status           string           `xml:"Sts>Cd"`
+	BookingDate      camtDate         `xml:"BookgDt"`
+	ValueDate        camtDate         `xml:"ValDt"`
+	ServicerRef      string           `xml:"AcctSvcrRef"`
+	TransactionCode  string           `xml:"BkTxCd>Prtry>Cd"`
+	Details          camtEntryDetails `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo   string           `xml:"AddtlNtryInf,omitempty"`
+}
+
+type camtEntryDetails struct {
+	ServicerRef      string     `xml:"Refs>AcctSvcrRef"`
+	Amount           camtAmount `xml:"Amt"`
+	CreditDebitIndic string     `xml:"CdtDbtInd"`
+}
+
+// writeCAMT writes the statement as an ISO 20022 camt.053.001.08 document.
+// Each transaction is a booked entry, booked when it was created and valued
+// on that day. Amounts are unsigned, with the sign as the credit or debit
+// indicator. Ids are written as 32 hex digits, since ISO 20022 identifiers
+// are at most 35 characters long.
+func writeCAMT(w io.Writer, result StatementResult) error {
+	messageID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	closingAt := result.ClosingAt()
+
+	statement := camtAccountStatement{
+		ID:        camtID(&messageID),
+		CreatedAt: camtDateTime(result.GeneratedAt),
+		Period: camtPeriod{
+			From: camtDateTime(result.From),
+			To:   camtDateTime(closingAt),
+		},
+		Account: camtAccount{
+			ID:       camtID(result.CustomerAccountID),
+			Type:     "CACC",
+			Currency: result.Currency,
+			Owner:    camtAccountOwner(result.Document),
+			Servicer: camtServicer{ID: result.BankID},
+		},
+		Balances: []camtBalance{
+			camtNewBalance("OPBD", result.OpeningBalance, result.Currency, result.From),
+			// The closing balance is the balance at the end of the last day of
+			// the statement, or of today for one that isn't over.
+			camtNewBalance("CLBD", result.ClosingBalance, result.Currency, closingAt.Add(-time.Nanosecond)),
+		},
+	}
+
+	var credits, debits camtEntriesSum
+
+	for _, line := range result.Lines {
+		amount, indicator := camtAmountOf(line.Amount, result.Currency)
+
+		if indicator == camtCredit {
+			credits.Count++
+		} else {
+			debits.Count++
+		}
+
+		statement.Entries = append(statement.Entries, camtEntry{
+			Reference:        camtID(line.TransactionID),
+			Amount:           amount,
+			CreditDebitIndic: indicator,
+			Status:           "BOOK",
+			BookingDate:      camtDate{DateTime: camtDateTime(line.CreatedAt)},
+			ValueDate:        camtDate{Date: camtDay(line.CreatedAt)},
+			ServicerRef:      camtID(line.TransactionID),
+			TransactionCode:  truncate(string(line.OperationType), camtMaxText),
+			Details: camtEntryDetails{
+				ServicerRef:      camtID(line.TransactionID),
+				Amount:           amount,
+				CreditDebitIndic: indicator,
+			},
+			AdditionalInfo: line.Description,
+		})
+	}
+
+	credits.Sum = utils.FormatCents(result.TotalCredits)
+	debits.Sum = utils.FormatCents(-result.TotalDebits)
+
+	net, netIndicator := camtAmountOf(result.TotalCredits+result.TotalDebits, result.Currency)
+
+	statement.Summary = camtSummary{
+		Entries: camtEntriesTotal{
+			Count:            len(result.Lines),
+			Sum:              utils.FormatCents(result.TotalCredits - result.TotalDebits),
+			NetAmount:        net.Value,
+			CreditDebitIndic: netIndicator,
+		},
+		CreditEntries: credits,
+		DebitEntries:  debits,
+	}
+
+	document := camtDocument{
+		Namespace: camtNamespace,
+		Statement: camtStatement{
+			GroupHeader: camtGroupHeader{
+				MessageID: statement.ID,
+				CreatedAt: statement.CreatedAt,
+			},
+			Statement: statement,
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
+
+func camtNewBalance(balanceType string, balance int64, currency string, at time.Time) camtBalance {
+	amount, indicator := camtAmountOf(balance, currency)
+
+	return camtBalance{
+		Type:             balanceType,
+		Amount:           amount,
+		CreditDebitIndic: indicator,
+		Date:             camtDate{Date: camtDay(at)},
+	}
+}
+
+// camtAmountOf splits a signed amount in cents into its absolute value and
+// its credit or debit indicator.
+func camtAmountOf(cents int64, currency string) (camtAmount, string) {
+	if cents < 0 {
+		return camtAmount{Currency: currency, Value: utils.FormatCents(-cents)}, camtDebit
+	}
+
+	return camtAmount{Currency: currency, Value: utils.FormatCents(cents)}, camtCredit
+}
+
+// camtAccountOwner identifies the owner by their tax id, TXID in the ISO
+// 20022 code sets.
+func camtAccountOwner(document string) camtOwner {
+	id := &camtGenericID{ID: document, Scheme: "TXID"}
+
+	if brdoc.IsCNPJ(document) {
+		return camtOwner{Organisation: id}
+	}
+
+	return camtOwner{Person: id}
+}
+
+func camtID(id *uuid.UUID) string {
+	return hex.EncodeToString(id.Bytes())
+}
+
+func camtDateTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func camtDay(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
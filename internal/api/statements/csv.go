@@ -0,0 +1,65 @@
+package statements
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+// csvHeader are the columns of CSV statements. Each row has a record type:
+// the opening balance, a transaction with the balance after it, the closing
+// balance or the total of an operation type.
+var csvHeader = []string{
+	"record_type", "date", "transaction_id", "operation_type", "description", "amount", "balance", "count",
+}
+
+const (
+	csvOpeningBalance = "opening_balance"
+	csvTransaction    = "transaction"
+	csvClosingBalance = "closing_balance"
+	csvTotal          = "total"
+)
+
+func writeCSV(w io.Writer, result StatementResult) error {
+	writer := csv.NewWriter(w)
+
+	rows := [][]string{
+		csvHeader,
+		{csvOpeningBalance, result.From.Format(time.RFC3339), "", "", "Opening balance", "", utils.FormatCents(result.OpeningBalance), ""},
+	}
+
+	for _, line := range result.Lines {
+		rows = append(rows, []string{
+			csvTransaction,
+			line.CreatedAt.Format(time.RFC3339),
+			line.TransactionID.String(),
+			string(line.OperationType),
+			line.Description,
+			utils.FormatCents(line.Amount),
+			utils.FormatCents(line.Balance),
+			"",
+		})
+	}
+
+	rows = append(rows, []string{
+		csvClosingBalance, result.ClosingAt().Format(time.RFC3339), "", "", "Closing balance", "", utils.FormatCents(result.ClosingBalance), "",
+	})
+
+	for _, total := range result.Totals {
+		rows = append(rows, []string{
+			csvTotal,
+			"",
+			"",
+			string(total.OperationType),
+			total.Description,
+			utils.FormatCents(total.Amount),
+			"",
+			strconv.Itoa(total.Count),
+		})
+	}
+
+	return writer.WriteAll(rows)
+}
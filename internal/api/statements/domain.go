@@ -0,0 +1,128 @@
+package statements
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatPDF  Format = "pdf"
+	FormatOFX  Format = "ofx"
+	// ISO 20022 camt.053 bank to customer statement.
+	FormatCAMT053 Format = "camt053"
+)
+
+// StatementResult is the movement of an account from From (inclusive) to To
+// (exclusive). Balances and amounts are in cents.
+type StatementResult struct {
+	CustomerAccountID *uuid.UUID
+	Document          string
+	BankID            string
+	Currency          string
+	Period            string
+	From              time.Time
+	To                time.Time
+	OpeningBalance    int64
+	ClosingBalance    int64
+	TotalCredits      int64
+	TotalDebits       int64
+	Lines             []StatementLine
+	Totals            []OperationTypeTotal
+	GeneratedAt       time.Time
+}
+
+// ClosingAt is when the closing balance was taken: the end of the period, or
+// when the statement was generated for a period that isn't over.
+func (r StatementResult) ClosingAt() time.Time {
+	if r.GeneratedAt.Before(r.To) {
+		return r.GeneratedAt
+	}
+
+	return r.To
+}
+
+// StatementLine is a transaction of the statement, with the balance of the
+// account right after it.
+type StatementLine struct {
+	TransactionID *uuid.UUID
+	OperationType models.OperationType
+	Description   string
+	Amount        int64
+	Balance       int64
+	CreatedAt     time.Time
+}
+
+type OperationTypeTotal struct {
+	OperationType models.OperationType
+	Description   string
+	Count         int
+	Amount        int64
+}
+
+// newStatementResult builds the statement of the transactions, oldest first,
+// from the balance of the account before them.
+func newStatementResult(
+	customerAccountID *uuid.UUID,
+	openingBalance int64,
+	transactions []models.Transaction,
+	describe func(models.OperationType) string,
+) StatementResult {
+	result := StatementResult{
+		CustomerAccountID: customerAccountID,
+		OpeningBalance:    openingBalance,
+		ClosingBalance:    openingBalance,
+	}
+
+	totals := map[models.OperationType]*OperationTypeTotal{}
+
+	for _, transaction := range transactions {
+		result.ClosingBalance += transaction.Amount
+
+		if transaction.Amount >= 0 {
+			result.TotalCredits += transaction.Amount
+		} else {
+			result.TotalDebits += transaction.Amount
+		}
+
+		description := describe(transaction.OperationType)
+
+		result.Lines = append(result.Lines, StatementLine{
+			TransactionID: transaction.ID,
+			OperationType: transaction.OperationType,
+			Description:   description,
+			Amount:        transaction.Amount,
+			Balance:       result.ClosingBalance,
+			CreatedAt:     transaction.CreatedAt,
+		})
+
+		total, ok := totals[transaction.OperationType]
+		if !ok {
+			total = &OperationTypeTotal{
+				OperationType: transaction.OperationType,
+				Description:   description,
+			}
+			totals[transaction.OperationType] = total
+		}
+
+		total.Count++
+		total.Amount += transaction.Amount
+	}
+
+	for _, total := range totals {
+		result.Totals = append(result.Totals, *total)
+	}
+
+	slices.SortFunc(result.Totals, func(a, b OperationTypeTotal) int {
+		return cmp.Compare(a.OperationType, b.OperationType)
+	})
+
+	return result
+}
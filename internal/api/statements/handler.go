@@ -0,0 +1,138 @@
+package statements
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	app.Get("/accounts/:customerAccountId/statements", httpHandler.getStatement)
+	app.Get("/accounts/:customerAccountId/statements/:period", httpHandler.getMonthlyStatement)
+}
+
+func (h *httpHandler) getStatement(c *fiber.Ctx) error {
+	customerAccountID, err := parseCustomerAccountID(c)
+	if err != nil {
+		return err
+	}
+
+	request := statementRequest{
+		CustomerAccountID: customerAccountID,
+		From:              c.Query("from"),
+		To:                c.Query("to"),
+		Format:            Format(c.Query("format", string(FormatJSON))),
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid parameters",
+		}, err.FieldErrors...)
+	}
+
+	statement, err := h.service.GetStatement(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return sendStatement(c, statement, request.Format)
+}
+
+func (h *httpHandler) getMonthlyStatement(c *fiber.Ctx) error {
+	customerAccountID, err := parseCustomerAccountID(c)
+	if err != nil {
+		return err
+	}
+
+	request := monthlyStatementRequest{
+		CustomerAccountID: customerAccountID,
+		Period:            c.Params("period"),
+		Format:            Format(c.Query("format", string(FormatJSON))),
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid parameters",
+		}, err.FieldErrors...)
+	}
+
+	statement, err := h.service.GetMonthlyStatement(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return sendStatement(c, statement, request.Format)
+}
+
+// sendStatement renders the statement in the format, as an attachment unless
+// it's JSON.
+func sendStatement(c *fiber.Ctx, statement StatementResult, format Format) error {
+	if format == FormatJSON {
+		return c.Status(http.StatusOK).JSON(DomainToStatementResponse(statement))
+	}
+
+	var (
+		body        bytes.Buffer
+		contentType string
+		extension   = string(format)
+		err         error
+	)
+
+	switch format {
+	case FormatCSV:
+		contentType = "text/csv; charset=utf-8"
+		err = writeCSV(&body, statement)
+	case FormatPDF:
+		contentType = "application/pdf"
+		err = writePDF(&body, statement)
+	case FormatOFX:
+		contentType = "application/x-ofx"
+		err = writeOFX(&body, statement)
+	case FormatCAMT053:
+		contentType = "application/xml"
+		extension = "xml"
+		err = writeCAMT(&body, statement)
+	}
+
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", statement.CustomerAccountID.String()).
+			Str("format", string(format)).
+			Msg("failed to render statement")
+
+		return err
+	}
+
+	c.Attachment(fmt.Sprintf("statement-%s-%s.%s", statement.CustomerAccountID, statementName(statement), extension))
+	c.Set(fiber.HeaderContentType, contentType)
+
+	return c.Status(http.StatusOK).Send(body.Bytes())
+}
+
+func parseCustomerAccountID(c *fiber.Ctx) (*uuid.UUID, error) {
+	customerAccountID, err := uuid.FromString(c.Params("customerAccountId"))
+	if err != nil {
+		return nil, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid account id",
+		})
+	}
+
+	return &customerAccountID, nil
+}
@@ -0,0 +1,77 @@
+package statements
+
+import (
+	"io"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/ofx"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+// ofxTransactionTypes is the TRNTYPE of the transactions of each built-in
+// operation type, chosen so that importing the file back yields the same
+// types. Other operation types are a generic CREDIT or DEBIT, depending on
+// the sign of the amount.
+var ofxTransactionTypes = map[models.OperationType]string{
+	models.NormalPurchase:            "POS",
+	models.PurcharseWithInstallments: "POS",
+	models.Withdrawal:                "ATM",
+	models.CreditVoucher:             "CREDIT",
+}
+
+// ofxMaxNameLength is the longest NAME the OFX specification allows.
+const ofxMaxNameLength = 32
+
+// writeOFX writes the statement as an OFX file. ACCTID holds the account id
+// in the 22 characters OFX allows, which imports read back.
+func writeOFX(w io.Writer, result StatementResult) error {
+	closingAt := result.ClosingAt()
+
+	statement := ofx.Statement{
+		BankID:    result.BankID,
+		AccountID: ofx.EncodeAccountID(*result.CustomerAccountID),
+		Currency:  result.Currency,
+		StartAt:   &result.From,
+		EndAt:     &closingAt,
+		LedgerBalance: &ofx.Balance{
+			Amount: utils.FormatCents(result.ClosingBalance),
+			AsOf:   &closingAt,
+		},
+	}
+
+	for _, line := range result.Lines {
+		postedAt := line.CreatedAt
+
+		statement.Transactions = append(statement.Transactions, ofx.StatementTransaction{
+			Type:     ofxTransactionType(line),
+			PostedAt: &postedAt,
+			Amount:   utils.FormatCents(line.Amount),
+			FITID:    line.TransactionID.String(),
+			Name:     truncate(line.Description, ofxMaxNameLength),
+			Memo:     string(line.OperationType),
+		})
+	}
+
+	return ofx.Write(w, result.GeneratedAt, statement)
+}
+
+func ofxTransactionType(line StatementLine) string {
+	if transactionType, ok := ofxTransactionTypes[line.OperationType]; ok {
+		return transactionType
+	}
+
+	if line.Amount < 0 {
+		return "DEBIT"
+	}
+
+	return "CREDIT"
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+
+	return string(runes[:length])
+}
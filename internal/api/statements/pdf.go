@@ -0,0 +1,188 @@
+package statements
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/pdf"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+const (
+	pdfMargin     = 40.0
+	pdfFontSize   = 8.0
+	pdfLineHeight = 12.0
+
+	pdfDateLayout     = "2006-01-02"
+	pdfDateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// Right edges of the amount columns, and where the description column starts.
+const (
+	pdfDescriptionX = pdfMargin + 110
+	pdfAmountX      = pdf.PageWidth - pdfMargin - 100
+	pdfBalanceX     = pdf.PageWidth - pdfMargin
+	pdfCountX       = pdfAmountX - 90
+)
+
+// pdfStatement lays out a statement over as many pages as it needs.
+type pdfStatement struct {
+	document *pdf.Document
+	page     *pdf.Page
+	y        float64
+	result   StatementResult
+}
+
+func writePDF(w io.Writer, result StatementResult) error {
+	statement := &pdfStatement{
+		document: pdf.New(fmt.Sprintf("Statement %s %s", result.CustomerAccountID, statementName(result))),
+		result:   result,
+	}
+
+	statement.newPage()
+	statement.writeSummary()
+	statement.writeTransactions()
+	statement.writeTotals()
+	statement.writePageNumbers()
+
+	_, err := statement.document.WriteTo(w)
+
+	return err
+}
+
+func (s *pdfStatement) newPage() {
+	s.page = s.document.AddPage()
+	s.y = pdf.PageHeight - pdfMargin
+
+	s.page.Text(pdfMargin, s.y, pdf.Bold, 14, "Account statement")
+	s.page.TextRight(pdfBalanceX, s.y, pdf.Regular, pdfFontSize, statementName(s.result))
+	s.y -= 10
+	s.page.Line(pdfMargin, s.y, pdfBalanceX, s.y, 0.5)
+	s.y -= 2 * pdfLineHeight
+}
+
+// nextLine moves down a line, starting a new page when there's no room left.
+// It reports whether a page was started.
+func (s *pdfStatement) nextLine() bool {
+	s.y -= pdfLineHeight
+
+	if s.y > pdfMargin+pdfLineHeight {
+		return false
+	}
+
+	s.newPage()
+
+	return true
+}
+
+func (s *pdfStatement) writeSummary() {
+	location := s.result.From.Location()
+
+	for _, field := range [][2]string{
+		{"Account", s.result.CustomerAccountID.String()},
+		{"Document", s.result.Document},
+		{"Period", fmt.Sprintf(
+			"%s to %s (%s)",
+			s.result.From.Format(pdfDateLayout),
+			s.result.To.AddDate(0, 0, -1).Format(pdfDateLayout),
+			location,
+		)},
+		{"Generated at", s.result.GeneratedAt.Format(pdfDateTimeLayout)},
+	} {
+		s.page.Text(pdfMargin, s.y, pdf.Bold, pdfFontSize, field[0])
+		s.page.Text(pdfDescriptionX, s.y, pdf.Regular, pdfFontSize, field[1])
+		s.nextLine()
+	}
+
+	s.nextLine()
+}
+
+func (s *pdfStatement) writeTransactionsHeader() {
+	s.page.Text(pdfMargin, s.y, pdf.Bold, pdfFontSize, "Date")
+	s.page.Text(pdfDescriptionX, s.y, pdf.Bold, pdfFontSize, "Description")
+	s.page.TextRight(pdfAmountX, s.y, pdf.Bold, pdfFontSize, "Amount")
+	s.page.TextRight(pdfBalanceX, s.y, pdf.Bold, pdfFontSize, "Balance")
+	s.page.Line(pdfMargin, s.y-4, pdfBalanceX, s.y-4, 0.5)
+	s.nextLine()
+}
+
+func (s *pdfStatement) writeTransactions() {
+	s.writeTransactionsHeader()
+
+	s.writeRow(s.result.From.Format(pdfDateTimeLayout), "Opening balance", "", s.result.OpeningBalance, pdf.Bold)
+
+	// The longest description that fits before the amount column.
+	maxDescription := int((pdfAmountX - pdfDescriptionX - 80) / pdf.TextWidth(pdfFontSize, "0"))
+
+	for _, line := range s.result.Lines {
+		description := line.Description
+		if runes := []rune(description); len(runes) > maxDescription {
+			description = string(runes[:maxDescription-1]) + "~"
+		}
+
+		s.writeRow(line.CreatedAt.Format(pdfDateTimeLayout), description, utils.FormatCents(line.Amount), line.Balance, pdf.Regular)
+	}
+
+	s.writeRow(s.result.ClosingAt().Format(pdfDateTimeLayout), "Closing balance", "", s.result.ClosingBalance, pdf.Bold)
+	s.nextLine()
+}
+
+func (s *pdfStatement) writeRow(date string, description string, amount string, balance int64, font pdf.Font) {
+	s.page.Text(pdfMargin, s.y, pdf.Regular, pdfFontSize, date)
+	s.page.Text(pdfDescriptionX, s.y, font, pdfFontSize, description)
+	s.page.TextRight(pdfAmountX, s.y, pdf.Regular, pdfFontSize, amount)
+	s.page.TextRight(pdfBalanceX, s.y, font, pdfFontSize, utils.FormatCents(balance))
+
+	if s.nextLine() {
+		s.writeTransactionsHeader()
+	}
+}
+
+func (s *pdfStatement) writeTotals() {
+	// Keep the heading of the totals with at least their first line.
+	if s.y < pdfMargin+4*pdfLineHeight {
+		s.newPage()
+	}
+
+	s.page.Text(pdfMargin, s.y, pdf.Bold, pdfFontSize, "Totals by operation type")
+	s.page.TextRight(pdfCountX, s.y, pdf.Bold, pdfFontSize, "Count")
+	s.page.TextRight(pdfAmountX, s.y, pdf.Bold, pdfFontSize, "Amount")
+	s.page.Line(pdfMargin, s.y-4, pdfBalanceX, s.y-4, 0.5)
+	s.nextLine()
+
+	for _, total := range s.result.Totals {
+		s.page.Text(pdfMargin, s.y, pdf.Regular, pdfFontSize, total.Description)
+		s.page.TextRight(pdfCountX, s.y, pdf.Regular, pdfFontSize, strconv.Itoa(total.Count))
+		s.page.TextRight(pdfAmountX, s.y, pdf.Regular, pdfFontSize, utils.FormatCents(total.Amount))
+		s.nextLine()
+	}
+
+	for _, total := range [][2]string{
+		{"Total credits", utils.FormatCents(s.result.TotalCredits)},
+		{"Total debits", utils.FormatCents(s.result.TotalDebits)},
+	} {
+		s.page.Text(pdfMargin, s.y, pdf.Bold, pdfFontSize, total[0])
+		s.page.TextRight(pdfAmountX, s.y, pdf.Bold, pdfFontSize, total[1])
+		s.nextLine()
+	}
+}
+
+// writePageNumbers numbers the pages once they're all laid out.
+func (s *pdfStatement) writePageNumbers() {
+	pages := s.document.Pages()
+
+	for index, page := range pages {
+		page.TextRight(pdfBalanceX, pdfMargin/2, pdf.Regular, pdfFontSize, fmt.Sprintf("Page %d of %d", index+1, len(pages)))
+	}
+}
+
+// statementName names the statement after its month, or its dates when it
+// doesn't cover a single month.
+func statementName(result StatementResult) string {
+	if result.Period != "" {
+		return result.Period
+	}
+
+	return fmt.Sprintf("%s_%s", result.From.Format(pdfDateLayout), result.To.AddDate(0, 0, -1).Format(pdfDateLayout))
+}
@@ -0,0 +1,25 @@
+package statements
+
+import (
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	// periodLayout is the layout of the month of a monthly statement.
+	periodLayout = "2006-01"
+	// dateLayout is the layout of the days a statement range starts and ends.
+	dateLayout = "2006-01-02"
+)
+
+type monthlyStatementRequest struct {
+	CustomerAccountID *uuid.UUID
+	Period            string `validate:"required,datetime=2006-01"`
+	Format            Format `validate:"required,oneof=json csv pdf ofx camt053"`
+}
+
+type statementRequest struct {
+	CustomerAccountID *uuid.UUID
+	From              string `validate:"required,datetime=2006-01-02"`
+	To                string `validate:"required,datetime=2006-01-02"`
+	Format            Format `validate:"required,oneof=json csv pdf ofx camt053"`
+}
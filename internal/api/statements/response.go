@@ -0,0 +1,81 @@
+package statements
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+)
+
+type StatementResponse struct {
+	AccountID      *uuid.UUID                   `json:"account_id"`
+	Document       string                       `json:"document_number"`
+	Currency       string                       `json:"currency"`
+	Period         string                       `json:"period,omitempty"`
+	From           time.Time                    `json:"from"`
+	To             time.Time                    `json:"to"`
+	OpeningBalance float64                      `json:"opening_balance"`
+	ClosingBalance float64                      `json:"closing_balance"`
+	TotalCredits   float64                      `json:"total_credits"`
+	TotalDebits    float64                      `json:"total_debits"`
+	Transactions   []StatementLineResponse      `json:"transactions"`
+	Totals         []OperationTypeTotalResponse `json:"totals"`
+	GeneratedAt    time.Time                    `json:"generated_at"`
+}
+
+type StatementLineResponse struct {
+	TransactionID *uuid.UUID           `json:"transaction_id"`
+	OperationType models.OperationType `json:"operation_type"`
+	Description   string               `json:"description"`
+	Amount        float64              `json:"amount"`
+	Balance       float64              `json:"balance"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+type OperationTypeTotalResponse struct {
+	OperationType models.OperationType `json:"operation_type"`
+	Description   string               `json:"description"`
+	Count         int                  `json:"count"`
+	Amount        float64              `json:"amount"`
+}
+
+func DomainToStatementResponse(result StatementResult) StatementResponse {
+	transactions := make([]StatementLineResponse, 0, len(result.Lines))
+	for _, line := range result.Lines {
+		transactions = append(transactions, StatementLineResponse{
+			TransactionID: line.TransactionID,
+			OperationType: line.OperationType,
+			Description:   line.Description,
+			Amount:        utils.FromCents(line.Amount),
+			Balance:       utils.FromCents(line.Balance),
+			CreatedAt:     line.CreatedAt,
+		})
+	}
+
+	totals := make([]OperationTypeTotalResponse, 0, len(result.Totals))
+	for _, total := range result.Totals {
+		totals = append(totals, OperationTypeTotalResponse{
+			OperationType: total.OperationType,
+			Description:   total.Description,
+			Count:         total.Count,
+			Amount:        utils.FromCents(total.Amount),
+		})
+	}
+
+	return StatementResponse{
+		AccountID:      result.CustomerAccountID,
+		Document:       result.Document,
+		Currency:       result.Currency,
+		Period:         result.Period,
+		From:           result.From,
+		To:             result.To,
+		OpeningBalance: utils.FromCents(result.OpeningBalance),
+		ClosingBalance: utils.FromCents(result.ClosingBalance),
+		TotalCredits:   utils.FromCents(result.TotalCredits),
+		TotalDebits:    utils.FromCents(result.TotalDebits),
+		Transactions:   transactions,
+		Totals:         totals,
+		GeneratedAt:    result.GeneratedAt,
+	}
+}
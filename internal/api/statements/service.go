@@ -0,0 +1,214 @@
+package statements
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	defaultBankID   = "000"
+	defaultCurrency = "BRL"
+
+	// maxStatementDays is the longest range a statement can cover, so it's
+	// never read from more than a year of transactions.
+	maxStatementDays = 366
+)
+
+type Servicer interface {
+	GetMonthlyStatement(context.Context, monthlyStatementRequest) (StatementResult, error)
+	GetStatement(context.Context, statementRequest) (StatementResult, error)
+}
+
+// Options describes the statements. Zero values fall back to the defaults.
+type Options struct {
+	// Location is where periods start and end at midnight, and where the
+	// dates of the statements are shown. Defaults to UTC.
+	Location *time.Location
+	// BankID identifies the institution in exported files, e.g. as the
+	// BANKID of OFX statements.
+	BankID string
+	// Currency is the ISO 4217 code of the amounts.
+	Currency string
+}
+
+type service struct {
+	transactionRepository     repository.TransactionRepository
+	balanceSnapshotRepository repository.BalanceSnapshotRepository
+	customerAccountRepository repository.CustomerAccountRepository
+	operationTypes            operationtypes.Registry
+	options                   Options
+}
+
+func NewService(
+	transactionRepository repository.TransactionRepository,
+	balanceSnapshotRepository repository.BalanceSnapshotRepository,
+	customerAccountRepository repository.CustomerAccountRepository,
+	operationTypes operationtypes.Registry,
+	options Options,
+) Servicer {
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+
+	if options.BankID == "" {
+		options.BankID = defaultBankID
+	}
+
+	if options.Currency == "" {
+		options.Currency = defaultCurrency
+	}
+
+	return &service{
+		transactionRepository:     transactionRepository,
+		balanceSnapshotRepository: balanceSnapshotRepository,
+		customerAccountRepository: customerAccountRepository,
+		operationTypes:            operationTypes,
+		options:                   options,
+	}
+}
+
+// GetMonthlyStatement returns the statement of a calendar month. The statement
+// of the current month covers the transactions created so far.
+func (s *service) GetMonthlyStatement(ctx context.Context, request monthlyStatementRequest) (StatementResult, error) {
+	from, err := time.ParseInLocation(periodLayout, request.Period, s.options.Location)
+	if err != nil {
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid statement period, expected YYYY-MM",
+		})
+	}
+
+	if from.After(time.Now()) {
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Statement period hasn't started yet",
+		})
+	}
+
+	result, err := s.getStatement(ctx, request.CustomerAccountID, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return StatementResult{}, err
+	}
+
+	result.Period = request.Period
+
+	return result, nil
+}
+
+// GetStatement returns the statement of the days from From to To, both
+// included.
+func (s *service) GetStatement(ctx context.Context, request statementRequest) (StatementResult, error) {
+	from, fromErr := time.ParseInLocation(dateLayout, request.From, s.options.Location)
+	lastDay, toErr := time.ParseInLocation(dateLayout, request.To, s.options.Location)
+	if fromErr != nil || toErr != nil {
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid statement range, expected YYYY-MM-DD dates",
+		})
+	}
+
+	to := lastDay.AddDate(0, 0, 1)
+
+	switch {
+	case lastDay.Before(from):
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Statement range ends before it starts",
+		})
+	case to.After(from.AddDate(0, 0, maxStatementDays)):
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("Statement range can't be longer than %d days", maxStatementDays),
+		})
+	case from.After(time.Now()):
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Statement period hasn't started yet",
+		})
+	}
+
+	return s.getStatement(ctx, request.CustomerAccountID, from, to)
+}
+
+// getStatement reads the opening balance and the transactions of the period
+// from the same snapshot of the database, so they add up.
+func (s *service) getStatement(
+	ctx context.Context, customerAccountID *uuid.UUID, from time.Time, to time.Time,
+) (StatementResult, error) {
+	customerAccount, err := s.customerAccountRepository.SearchCustomerAccountByID(ctx, customerAccountID)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Msg("failed to find customer account")
+
+		return StatementResult{}, err
+	}
+
+	if customerAccount == nil {
+		return StatementResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Customer account not found",
+		})
+	}
+
+	var (
+		openingBalance int64
+		transactions   []models.Transaction
+	)
+
+	err = s.transactionRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+
+		openingBalance, err = s.balanceSnapshotRepository.GetCustomerAccountBalanceBefore(txCtx, customerAccountID, from)
+		if err != nil {
+			return err
+		}
+
+		transactions, err = s.transactionRepository.ListCustomerAccountTransactions(txCtx, customerAccountID, from, to)
+
+		return err
+	}, repository.WithIsolation(sql.LevelRepeatableRead), repository.ReadOnly())
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", customerAccountID.String()).
+			Time("from", from).
+			Time("to", to).
+			Msg("failed to read customer account statement")
+
+		return StatementResult{}, err
+	}
+
+	result := newStatementResult(customerAccountID, openingBalance, transactions, s.describe)
+	result.Document = customerAccount.Document
+	result.BankID = s.options.BankID
+	result.Currency = s.options.Currency
+	result.From = from.In(s.options.Location)
+	result.To = to.In(s.options.Location)
+	result.GeneratedAt = time.Now().In(s.options.Location)
+
+	for index := range result.Lines {
+		result.Lines[index].CreatedAt = result.Lines[index].CreatedAt.In(s.options.Location)
+	}
+
+	return result, nil
+}
+
+// describe is the description of the operation type, or its code when it has
+// none.
+func (s *service) describe(code models.OperationType) string {
+	if operationType, ok := s.operationTypes.Lookup(code); ok && operationType.Description != "" {
+		return operationType.Description
+	}
+
+	return string(code)
+}
@@ -1,8 +1,20 @@
 package transactions
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid/v5"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+)
+
+type BatchMode string
+
+const (
+	// AtomicBatch creates every item of the batch or none of them.
+	AtomicBatch BatchMode = "atomic"
+	// IndependentBatch creates each item on its own, reporting a status per item.
+	IndependentBatch BatchMode = "independent"
 )
 
 type CreateTransactionResult struct {
@@ -10,4 +22,54 @@ type CreateTransactionResult struct {
 	CustomerAccountID *uuid.UUID
 	OperationType     models.OperationType
 	Amount            int64
+	BalanceAfter      *int64
+	Replayed          bool
+}
+
+type CreateTransactionsBatchResult struct {
+	Mode  BatchMode
+	Items []BatchItemResult
+}
+
+type BatchItemResult struct {
+	Index       int
+	Status      int
+	Transaction *CreateTransactionResult
+	Error       *cerror.Error
+}
+
+type TransactionResult struct {
+	ID                *uuid.UUID
+	CustomerAccountID *uuid.UUID
+	OperationType     models.OperationType
+	Amount            int64
+	BalanceAfter      *int64
+	CreatedAt         time.Time
+}
+
+type BackfillBalanceAfterResult struct {
+	AccountsProcessed   int
+	TransactionsUpdated int64
+}
+
+func DatabaseToCreateTransactionResult(transaction models.Transaction, replayed bool) CreateTransactionResult {
+	return CreateTransactionResult{
+		ID:                transaction.ID,
+		CustomerAccountID: transaction.CustomerAccountID,
+		OperationType:     transaction.OperationType,
+		Amount:            transaction.Amount,
+		BalanceAfter:      transaction.BalanceAfter,
+		Replayed:          replayed,
+	}
+}
+
+func DatabaseToTransactionResult(transaction models.Transaction) TransactionResult {
+	return TransactionResult{
+		ID:                transaction.ID,
+		CustomerAccountID: transaction.CustomerAccountID,
+		OperationType:     transaction.OperationType,
+		Amount:            transaction.Amount,
+		BalanceAfter:      transaction.BalanceAfter,
+		CreatedAt:         transaction.CreatedAt,
+	}
 }
@@ -4,8 +4,10 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
 )
 
@@ -20,10 +22,12 @@ func NewHTTPHandler(app *fiber.App, service Servicer) {
 
 	routeGroup := app.Group("/transactions")
 	routeGroup.Post("/", httpHandler.createTransaction)
+	routeGroup.Post("/batch", httpHandler.createTransactionsBatch)
+	routeGroup.Get("/:transactionId", httpHandler.getTransactionByID)
 }
 
 func (h *httpHandler) createTransaction(c *fiber.Ctx) error {
-	var createTransactionReq createTransactionRequest
+	var createTransactionReq CreateTransactionRequest
 
 	if err := c.BodyParser(&createTransactionReq); err != nil {
 		return cerror.New(cerror.Params{
@@ -46,5 +50,55 @@ func (h *httpHandler) createTransaction(c *fiber.Ctx) error {
 		return err
 	}
 
+	if createTransactionResult.Replayed {
+		c.Set(middleware.IdempotentReplayedHeader, "true")
+	}
+
 	return c.Status(http.StatusOK).JSON(DomainToCreateTransactionResponse(createTransactionResult))
 }
+
+func (h *httpHandler) createTransactionsBatch(c *fiber.Ctx) error {
+	var batchReq createTransactionsBatchRequest
+
+	if err := c.BodyParser(&batchReq); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid batch payload",
+		})
+	}
+
+	if err := validator.ValidateStruct(batchReq); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	batchResult, err := h.service.CreateTransactionsBatch(c.Context(), batchReq)
+	if err != nil {
+		log.Err(err).Msg("failed to create transactions batch")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToCreateTransactionsBatchResponse(batchResult))
+}
+
+func (h *httpHandler) getTransactionByID(c *fiber.Ctx) error {
+	transactionID, err := uuid.FromString(c.Params("transactionId"))
+	if err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid transaction id",
+		})
+	}
+
+	transaction, err := h.service.GetTransactionByID(c.Context(), getTransactionRequest{
+		TransactionID: &transactionID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToGetTransactionResponse(transaction))
+}
@@ -0,0 +1,309 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/audit"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const defaultAccountQueueMaxBatch = 50
+
+const (
+	queuedTransactionPending int32 = iota
+	queuedTransactionStarted
+	queuedTransactionAbandoned
+)
+
+// queuedTransaction is a transaction waiting in the queue of its account.
+// The worker sends its outcome on done once the micro-batch commits. It holds
+// copies of what the worker needs from the request, since the context of the
+// request isn't valid anymore once its handler returns.
+type queuedTransaction struct {
+	auditRequest    *audit.Request
+	customerAccount *repository.CustomerAccountByIDResult
+	operationType   operationtypes.OperationTypeResult
+	request         CreateTransactionRequest
+	done            chan queuedTransactionResult
+	state           atomic.Int32
+}
+
+// start marks the transaction as taken by the worker, unless its caller has
+// already given up waiting for it.
+func (q *queuedTransaction) start() bool {
+	return q.state.CompareAndSwap(queuedTransactionPending, queuedTransactionStarted)
+}
+
+// abandon drops the transaction from the queue, unless the worker has already
+// taken it.
+func (q *queuedTransaction) abandon() bool {
+	return q.state.CompareAndSwap(queuedTransactionPending, queuedTransactionAbandoned)
+}
+
+type queuedTransactionResult struct {
+	transaction *models.Transaction
+	replayed    bool
+	err         error
+}
+
+// accountQueue serializes the transactions of each account in this process.
+// A worker goroutine per busy account drains its queue in micro-batches, so
+// concurrent requests for a hot account share one database transaction and
+// one balance update instead of waiting on each other for the balance lock.
+type accountQueue struct {
+	size     int
+	maxBatch int
+	process  func(batch []*queuedTransaction)
+
+	mu      sync.Mutex
+	workers map[uuid.UUID]chan *queuedTransaction
+}
+
+func newAccountQueue(size int, maxBatch int, process func(batch []*queuedTransaction)) *accountQueue {
+	return &accountQueue{
+		size:     size,
+		maxBatch: maxBatch,
+		process:  process,
+		workers:  map[uuid.UUID]chan *queuedTransaction{},
+	}
+}
+
+// enqueue adds the transaction to the queue of its account and waits for its
+// outcome. It fails right away with 503 when the queue is full. When ctx is
+// done first, the transaction is abandoned if the worker hasn't taken it yet;
+// otherwise its outcome is still waited for, so the caller is never told it
+// failed while it's committed.
+func (q *accountQueue) enqueue(ctx context.Context, queued *queuedTransaction) queuedTransactionResult {
+	accountID := *queued.customerAccount.ID
+
+	q.mu.Lock()
+
+	jobs, ok := q.workers[accountID]
+	if !ok {
+		jobs = make(chan *queuedTransaction, q.size)
+		q.workers[accountID] = jobs
+
+		go q.work(accountID, jobs)
+	}
+
+	select {
+	case jobs <- queued:
+		q.mu.Unlock()
+	default:
+		q.mu.Unlock()
+
+		log.Warn().
+			Str("customer_account_id", accountID.String()).
+			Int("queue_size", q.size).
+			Msg("account queue is full")
+
+		return queuedTransactionResult{err: cerror.New(cerror.Params{
+			Status:  http.StatusServiceUnavailable,
+			Message: "Too many transactions queued for this account, try again later",
+		})}
+	}
+
+	select {
+	case result := <-queued.done:
+		return result
+	case <-ctx.Done():
+		if queued.abandon() {
+			return queuedTransactionResult{err: ctx.Err()}
+		}
+
+		return <-queued.done
+	}
+}
+
+// work processes the queue of the account until it's empty. The check and the
+// removal of the worker happen under the lock enqueue sends with, so nothing
+// is left behind in a queue without a worker.
+func (q *accountQueue) work(accountID uuid.UUID, jobs chan *queuedTransaction) {
+	for {
+		q.mu.Lock()
+		if len(jobs) == 0 {
+			delete(q.workers, accountID)
+			q.mu.Unlock()
+
+			return
+		}
+		q.mu.Unlock()
+
+		var batch []*queuedTransaction
+
+	drain:
+		for len(batch) < q.maxBatch {
+			select {
+			case queued := <-jobs:
+				if queued.start() {
+					batch = append(batch, queued)
+				}
+			default:
+				break drain
+			}
+		}
+
+		if len(batch) > 0 {
+			q.process(batch)
+		}
+	}
+}
+
+// processOrEnqueueTransaction sends the transaction through the account queue
+// when it's enabled. Calls that already run in a database transaction (e.g. an
+// atomic batch) can't join a micro-batch and are processed directly.
+func (s *service) processOrEnqueueTransaction(
+	ctx context.Context,
+	customerAccount *repository.CustomerAccountByIDResult,
+	operationType operationtypes.OperationTypeResult,
+	request CreateTransactionRequest,
+) (*models.Transaction, bool, error) {
+	if s.accountQueue == nil || repository.InTransaction(ctx) {
+		return s.processTransaction(ctx, customerAccount, operationType, request)
+	}
+
+	result := s.accountQueue.enqueue(ctx, &queuedTransaction{
+		auditRequest:    audit.RequestFromContext(ctx),
+		customerAccount: customerAccount,
+		operationType:   operationType,
+		request:         request,
+		done:            make(chan queuedTransactionResult, 1),
+	})
+
+	return result.transaction, result.replayed, result.err
+}
+
+// processQueuedTransactions applies a micro-batch of transactions of the same
+// account in one database transaction, with a single balance update. Each one
+// runs in its own savepoint, so a transaction refused for a business reason
+// (e.g. insufficient funds) fails alone; any other error fails the batch.
+func (s *service) processQueuedTransactions(batch []*queuedTransaction) {
+	ctx := context.Background()
+	customerAccount := batch[0].customerAccount
+
+	var results []queuedTransactionResult
+
+	for attempt := 0; ; attempt++ {
+		var err error
+
+		results, err = s.processQueuedTransactionsAttempt(ctx, customerAccount, batch)
+		if !errors.Is(err, errBalanceVersionConflict) {
+			if err != nil {
+				log.Err(err).
+					Str("customer_account_id", customerAccount.ID.String()).
+					Int("batch_size", len(batch)).
+					Msg("failed to process queued transactions")
+
+				results = make([]queuedTransactionResult, len(batch))
+				for index := range results {
+					results[index].err = err
+				}
+			}
+
+			break
+		}
+
+		if attempt >= s.options.OptimisticMaxRetries {
+			conflictErr := cerror.New(cerror.Params{
+				Status:  http.StatusConflict,
+				Message: "Balance was updated concurrently, try again",
+			})
+
+			results = make([]queuedTransactionResult, len(batch))
+			for index := range results {
+				results[index].err = conflictErr
+			}
+
+			break
+		}
+
+		// ctx is never done, so the wait can't fail.
+		_ = repository.WaitRetryBackoff(ctx, attempt)
+	}
+
+	for index, queued := range batch {
+		queued.done <- results[index]
+	}
+}
+
+func (s *service) processQueuedTransactionsAttempt(
+	ctx context.Context,
+	customerAccount *repository.CustomerAccountByIDResult,
+	batch []*queuedTransaction,
+) ([]queuedTransactionResult, error) {
+	var results []queuedTransactionResult
+
+	err := s.transactionRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		results = make([]queuedTransactionResult, len(batch))
+
+		accountBalance, err := s.getAccountBalance(txCtx, customerAccount.ID, batch[0].request)
+		if err != nil {
+			return err
+		}
+
+		balance := accountBalance.Balance
+		created := false
+
+		for index, queued := range batch {
+			// Each transaction is audited with the request that sent it.
+			auditCtx := audit.WithRequest(txCtx, queued.auditRequest)
+
+			err := s.transactionRepository.WithTransaction(auditCtx, func(itemCtx context.Context) error {
+				replayedTransaction, err := s.claimIdempotencyKey(itemCtx, queued.request)
+				if err != nil {
+					return err
+				}
+
+				if replayedTransaction != nil {
+					results[index] = queuedTransactionResult{transaction: replayedTransaction, replayed: true}
+
+					return nil
+				}
+
+				transaction, err := s.applyTransaction(itemCtx, customerAccount, queued.operationType, queued.request, balance)
+				if err != nil {
+					return err
+				}
+
+				results[index] = queuedTransactionResult{transaction: transaction}
+
+				return nil
+			})
+			if err != nil {
+				var customErr *cerror.Error
+				if !errors.As(err, &customErr) {
+					return err
+				}
+
+				results[index] = queuedTransactionResult{err: err}
+
+				continue
+			}
+
+			if !results[index].replayed {
+				balance = *results[index].transaction.BalanceAfter
+				created = true
+			}
+		}
+
+		if !created {
+			return nil
+		}
+
+		return s.updateBalance(txCtx, customerAccount, accountBalance, balance)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
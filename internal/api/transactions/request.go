@@ -1,13 +1,37 @@
 package transactions
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
 	"github.com/gofrs/uuid/v5"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
 )
 
-type createTransactionRequest struct {
+type CreateTransactionRequest struct {
 	CustomerAccountID *uuid.UUID           `json:"account_id" validate:"required"`
-	OperationType     models.OperationType `json:"operation_type" validate:"required,oneof=normal_purchase installment_purchase withdrawal credit_voucher"`
-	Amount            float64              `json:"amount" validate:"required,gt=0"`
+	OperationType     models.OperationType `json:"operation_type" validate:"required"`
+	Amount            float64              `json:"amount" validate:"required,gte=0.01"`
 	IdempotencyKey    *string              `json:"idempotency_key" validate:"omitempty"`
 }
+
+// fingerprint identifies the payload of the request, so a retry reusing the
+// idempotency key can be told apart from a different request. The format must
+// match the backfill in the request fingerprint migration.
+func (r CreateTransactionRequest) fingerprint() string {
+	payload := fmt.Sprintf("%s:%s:%d", r.CustomerAccountID, r.OperationType, utils.ToCents(r.Amount))
+	hash := sha256.Sum256([]byte(payload))
+
+	return hex.EncodeToString(hash[:])
+}
+
+type createTransactionsBatchRequest struct {
+	Mode  BatchMode                  `json:"mode" validate:"required,oneof=atomic independent"`
+	Items []CreateTransactionRequest `json:"items" validate:"required,min=1,max=500"`
+}
+
+type getTransactionRequest struct {
+	TransactionID *uuid.UUID
+}
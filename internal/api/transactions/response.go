@@ -1,8 +1,11 @@
 package transactions
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid/v5"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
 )
 
@@ -11,6 +14,34 @@ type CreateTransactionResponse struct {
 	CustomerAccountID *uuid.UUID           `json:"customer_account_id"`
 	OperationType     models.OperationType `json:"operation_type"`
 	Amount            float64              `json:"amount"`
+	BalanceAfter      *float64             `json:"balance_after"`
+}
+
+type CreateTransactionsBatchResponse struct {
+	Mode  BatchMode           `json:"mode"`
+	Items []BatchItemResponse `json:"items"`
+}
+
+type BatchItemResponse struct {
+	Index       int                        `json:"index"`
+	Status      int                        `json:"status"`
+	Replayed    bool                       `json:"replayed"`
+	Transaction *CreateTransactionResponse `json:"transaction,omitempty"`
+	Error       *cerror.Error              `json:"error,omitempty"`
+}
+
+type GetTransactionResponse struct {
+	ID                *uuid.UUID           `json:"id"`
+	CustomerAccountID *uuid.UUID           `json:"customer_account_id"`
+	OperationType     models.OperationType `json:"operation_type"`
+	Amount            float64              `json:"amount"`
+	BalanceAfter      *float64             `json:"balance_after"`
+	CreatedAt         time.Time            `json:"created_at"`
+}
+
+type BackfillBalanceAfterResponse struct {
+	AccountsProcessed   int   `json:"accounts_processed"`
+	TransactionsUpdated int64 `json:"transactions_updated"`
 }
 
 func DomainToCreateTransactionResponse(result CreateTransactionResult) CreateTransactionResponse {
@@ -19,5 +50,50 @@ func DomainToCreateTransactionResponse(result CreateTransactionResult) CreateTra
 		CustomerAccountID: result.CustomerAccountID,
 		OperationType:     result.OperationType,
 		Amount:            utils.FromCents(result.Amount),
+		BalanceAfter:      utils.FromCentsPointer(result.BalanceAfter),
+	}
+}
+
+func DomainToCreateTransactionsBatchResponse(result CreateTransactionsBatchResult) CreateTransactionsBatchResponse {
+	items := make([]BatchItemResponse, 0, len(result.Items))
+
+	for _, item := range result.Items {
+		itemResponse := BatchItemResponse{
+			Index:  item.Index,
+			Status: item.Status,
+			Error:  item.Error,
+		}
+
+		if item.Transaction != nil {
+			transaction := DomainToCreateTransactionResponse(*item.Transaction)
+
+			itemResponse.Replayed = item.Transaction.Replayed
+			itemResponse.Transaction = &transaction
+		}
+
+		items = append(items, itemResponse)
+	}
+
+	return CreateTransactionsBatchResponse{
+		Mode:  result.Mode,
+		Items: items,
+	}
+}
+
+func DomainToGetTransactionResponse(result TransactionResult) GetTransactionResponse {
+	return GetTransactionResponse{
+		ID:                result.ID,
+		CustomerAccountID: result.CustomerAccountID,
+		OperationType:     result.OperationType,
+		Amount:            utils.FromCents(result.Amount),
+		BalanceAfter:      utils.FromCentsPointer(result.BalanceAfter),
+		CreatedAt:         result.CreatedAt,
+	}
+}
+
+func DomainToBackfillBalanceAfterResponse(result BackfillBalanceAfterResult) BackfillBalanceAfterResponse {
+	return BackfillBalanceAfterResponse{
+		AccountsProcessed:   result.AccountsProcessed,
+		TransactionsUpdated: result.TransactionsUpdated,
 	}
 }
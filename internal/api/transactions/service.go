@@ -2,40 +2,147 @@ package transactions
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/audit"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
 	"github.com/tiagovaldrich/accounts-api/internal/repository"
 )
 
+const backfillBalanceAfterBatchSize = 100
+
+// idempotencyClaimTimeout bounds how long a request waits for a concurrent one
+// holding the same idempotency key before giving up with a conflict.
+const idempotencyClaimTimeout = 5 * time.Second
+
+// BalanceLockStrategy chooses how concurrent transactions on the same account
+// are serialized when updating its balance.
+type BalanceLockStrategy string
+
+const (
+	// PessimisticBalanceLock locks the balance row with SELECT ... FOR UPDATE.
+	PessimisticBalanceLock BalanceLockStrategy = "pessimistic"
+	// OptimisticBalanceLock reads the balance without locking and updates it
+	// with UPDATE ... WHERE version = ?, retrying when another writer won.
+	OptimisticBalanceLock BalanceLockStrategy = "optimistic"
+)
+
+func ParseBalanceLockStrategy(strategy string) (BalanceLockStrategy, error) {
+	switch BalanceLockStrategy(strategy) {
+	case PessimisticBalanceLock, OptimisticBalanceLock:
+		return BalanceLockStrategy(strategy), nil
+	default:
+		return "", fmt.Errorf("unknown balance lock strategy %q", strategy)
+	}
+}
+
+type Options struct {
+	BalanceLockStrategy BalanceLockStrategy
+	// OptimisticMaxRetries is how many times a transaction is retried after
+	// losing a version conflict under the optimistic strategy.
+	OptimisticMaxRetries int
+	// AccountQueueSize enables the per-account queue when positive, bounding
+	// how many transactions can wait for each account.
+	AccountQueueSize int
+	// AccountQueueMaxBatch is how many queued transactions of an account are
+	// applied in the same database transaction.
+	AccountQueueMaxBatch int
+}
+
+var errBalanceVersionConflict = errors.New("balance was updated by a concurrent transaction")
+
+// ErrCodeInsufficientFunds is the code of the refusal of a debit larger than
+// the balance of the account.
+const ErrCodeInsufficientFunds = "insufficient_funds"
+
+// IsInsufficientFunds tells whether err is the refusal of a debit larger than
+// the balance of the account.
+func IsInsufficientFunds(err error) bool {
+	var customErr *cerror.Error
+
+	return errors.As(err, &customErr) && customErr.Code == ErrCodeInsufficientFunds
+}
+
 type Servicer interface {
-	CreateTransaction(context.Context, createTransactionRequest) (CreateTransactionResult, error)
+	CreateTransaction(context.Context, CreateTransactionRequest) (CreateTransactionResult, error)
+	CreateTransactionsBatch(context.Context, createTransactionsBatchRequest) (CreateTransactionsBatchResult, error)
+	GetTransactionByID(context.Context, getTransactionRequest) (TransactionResult, error)
+	BackfillBalanceAfter(context.Context) (BackfillBalanceAfterResult, error)
 }
 
 type service struct {
-	transactionRepository     repository.TransactionRepository
-	customerAccountRepository repository.CustomerAccountRepository
-	balanceRepository         repository.BalanceRepository
+	transactionRepository       repository.TransactionRepository
+	idempotencyRecordRepository repository.IdempotencyRecordRepository
+	customerAccountRepository   repository.CustomerAccountRepository
+	balanceRepository           repository.BalanceRepository
+	ledgerRepository            repository.LedgerRepository
+	outboxRepository            repository.OutboxRepository
+	auditLogRepository          repository.AuditLogRepository
+	operationTypes              operationtypes.Registry
+	options                     Options
+	accountQueue                *accountQueue
 }
 
 func NewService(
 	transactionRepository repository.TransactionRepository,
+	idempotencyRecordRepository repository.IdempotencyRecordRepository,
 	customerAccountRepository repository.CustomerAccountRepository,
 	balanceRepository repository.BalanceRepository,
+	ledgerRepository repository.LedgerRepository,
+	outboxRepository repository.OutboxRepository,
+	auditLogRepository repository.AuditLogRepository,
+	operationTypes operationtypes.Registry,
+	options Options,
 ) Servicer {
-	return &service{
-		transactionRepository:     transactionRepository,
-		customerAccountRepository: customerAccountRepository,
-		balanceRepository:         balanceRepository,
+	if options.BalanceLockStrategy == "" {
+		options.BalanceLockStrategy = PessimisticBalanceLock
+	}
+
+	if options.AccountQueueMaxBatch <= 0 {
+		options.AccountQueueMaxBatch = defaultAccountQueueMaxBatch
+	}
+
+	s := &service{
+		transactionRepository:       transactionRepository,
+		idempotencyRecordRepository: idempotencyRecordRepository,
+		customerAccountRepository:   customerAccountRepository,
+		balanceRepository:           balanceRepository,
+		ledgerRepository:            ledgerRepository,
+		outboxRepository:            outboxRepository,
+		auditLogRepository:          auditLogRepository,
+		operationTypes:              operationTypes,
+		options:                     options,
 	}
+
+	if options.AccountQueueSize > 0 {
+		s.accountQueue = newAccountQueue(options.AccountQueueSize, options.AccountQueueMaxBatch, s.processQueuedTransactions)
+	}
+
+	return s
 }
 
-func (s *service) CreateTransaction(ctx context.Context, request createTransactionRequest) (CreateTransactionResult, error) {
-	if err := s.validateIdempotency(ctx, request); err != nil {
+func (s *service) CreateTransaction(ctx context.Context, request CreateTransactionRequest) (CreateTransactionResult, error) {
+	replayedTransaction, err := s.findIdempotentReplay(ctx, request)
+	if err != nil {
+		return CreateTransactionResult{}, err
+	}
+
+	if replayedTransaction != nil {
+		return DatabaseToCreateTransactionResult(*replayedTransaction, true), nil
+	}
+
+	operationType, err := s.resolveOperationType(request)
+	if err != nil {
 		return CreateTransactionResult{}, err
 	}
 
@@ -44,41 +151,368 @@ func (s *service) CreateTransaction(ctx context.Context, request createTransacti
 		return CreateTransactionResult{}, err
 	}
 
-	transaction, err := s.processTransaction(ctx, customerAccount, request)
+	transaction, replayed, err := s.processOrEnqueueTransaction(ctx, customerAccount, operationType, request)
 	if err != nil {
 		return CreateTransactionResult{}, err
 	}
 
-	return CreateTransactionResult{
-		ID:                transaction.ID,
-		CustomerAccountID: transaction.CustomerAccountID,
-		OperationType:     transaction.OperationType,
-		Amount:            transaction.Amount,
+	return DatabaseToCreateTransactionResult(*transaction, replayed), nil
+}
+
+// CreateTransactionsBatch creates the items of the batch in order, each one
+// with the same validation and idempotency as CreateTransaction. In atomic
+// mode the first failing item rolls the whole batch back; in independent mode
+// each item gets its own status.
+func (s *service) CreateTransactionsBatch(
+	ctx context.Context, request createTransactionsBatchRequest,
+) (CreateTransactionsBatchResult, error) {
+	if request.Mode == AtomicBatch {
+		return s.createTransactionsAtomically(ctx, request.Items)
+	}
+
+	result := CreateTransactionsBatchResult{
+		Mode:  IndependentBatch,
+		Items: make([]BatchItemResult, 0, len(request.Items)),
+	}
+
+	for index, item := range request.Items {
+		transaction, err := s.createBatchItem(ctx, item)
+		if err != nil {
+			itemErr := batchItemError(err)
+
+			result.Items = append(result.Items, BatchItemResult{
+				Index:  index,
+				Status: itemErr.Status,
+				Error:  itemErr,
+			})
+
+			continue
+		}
+
+		result.Items = append(result.Items, BatchItemResult{
+			Index:       index,
+			Status:      http.StatusOK,
+			Transaction: &transaction,
+		})
+	}
+
+	return result, nil
+}
+
+// createTransactionsAtomically runs every item in one database transaction;
+// each item runs in its own savepoint, joined to it.
+func (s *service) createTransactionsAtomically(
+	ctx context.Context, items []CreateTransactionRequest,
+) (CreateTransactionsBatchResult, error) {
+	var results []BatchItemResult
+
+	err := s.transactionRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		results = make([]BatchItemResult, 0, len(items))
+
+		for index, item := range items {
+			transaction, err := s.createBatchItem(txCtx, item)
+			if err != nil {
+				var customErr *cerror.Error
+				if !errors.As(err, &customErr) {
+					return err
+				}
+
+				itemErr := batchItemError(err)
+
+				return cerror.New(cerror.Params{
+					Status:  itemErr.Status,
+					Message: "Batch item failed, no transaction was created",
+				}, cerror.FieldError{
+					Field:   fmt.Sprintf("items[%d]", index),
+					Message: itemErr.Message,
+				})
+			}
+
+			results = append(results, BatchItemResult{
+				Index:       index,
+				Status:      http.StatusOK,
+				Transaction: &transaction,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return CreateTransactionsBatchResult{}, err
+	}
+
+	return CreateTransactionsBatchResult{
+		Mode:  AtomicBatch,
+		Items: results,
 	}, nil
 }
 
-func (s *service) validateIdempotency(ctx context.Context, request createTransactionRequest) error {
+func (s *service) createBatchItem(ctx context.Context, item CreateTransactionRequest) (CreateTransactionResult, error) {
+	if err := validator.ValidateStruct(item); err != nil {
+		return CreateTransactionResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	return s.CreateTransaction(ctx, item)
+}
+
+// batchItemError turns the error of a batch item into the error reported for
+// it, hiding unexpected errors behind a generic one.
+func batchItemError(err error) *cerror.Error {
+	var customErr *cerror.Error
+	if errors.As(err, &customErr) && customErr.Status != 0 {
+		return customErr
+	}
+
+	log.Err(err).Msg("failed to create batch item")
+
+	return cerror.New(cerror.Params{
+		Status:  http.StatusInternalServerError,
+		Message: "Internal server error",
+	})
+}
+
+func (s *service) GetTransactionByID(ctx context.Context, request getTransactionRequest) (TransactionResult, error) {
+	transaction, err := s.transactionRepository.GetTransactionByID(ctx, request.TransactionID)
+	if err != nil {
+		log.Err(err).
+			Str("transaction_id", request.TransactionID.String()).
+			Msg("failed to get transaction")
+
+		return TransactionResult{}, err
+	}
+
+	if transaction == nil {
+		return TransactionResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Transaction not found",
+		})
+	}
+
+	return DatabaseToTransactionResult(*transaction), nil
+}
+
+// BackfillBalanceAfter fills balance_after for transactions created before it
+// was stored. Each account is processed holding its balance lock, so new
+// transactions can't interleave with the recomputation.
+func (s *service) BackfillBalanceAfter(ctx context.Context) (BackfillBalanceAfterResult, error) {
+	var result BackfillBalanceAfterResult
+
+	for {
+		customerAccountIDs, err := s.transactionRepository.ListCustomerAccountsMissingBalanceAfter(
+			ctx, backfillBalanceAfterBatchSize,
+		)
+		if err != nil {
+			log.Err(err).Msg("failed to list customer accounts missing balance after")
+
+			return result, err
+		}
+
+		if len(customerAccountIDs) == 0 {
+			return result, nil
+		}
+
+		for _, customerAccountID := range customerAccountIDs {
+			err := s.transactionRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+				if _, err := s.balanceRepository.GetCustomerAccountBalance(txCtx, &customerAccountID); err != nil {
+					return err
+				}
+
+				updated, err := s.transactionRepository.BackfillBalanceAfter(txCtx, &customerAccountID)
+				if err != nil {
+					return err
+				}
+
+				result.AccountsProcessed++
+				result.TransactionsUpdated += updated
+
+				return nil
+			})
+			if err != nil {
+				log.Err(err).
+					Str("customer_account_id", customerAccountID.String()).
+					Msg("failed to backfill balance after")
+
+				return result, err
+			}
+		}
+	}
+}
+
+// findIdempotentReplay returns the transaction previously created with the
+// idempotency key of the request, so a retry gets the original result without
+// opening a database transaction. Keys are scoped per account.
+func (s *service) findIdempotentReplay(ctx context.Context, request CreateTransactionRequest) (*models.Transaction, error) {
+	if request.IdempotencyKey == nil || *request.IdempotencyKey == "" {
+		return nil, nil
+	}
+
+	record, err := s.idempotencyRecordRepository.GetIdempotencyRecord(
+		ctx, request.CustomerAccountID, *request.IdempotencyKey,
+	)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", request.CustomerAccountID.String()).
+			Str("idempotency_key", *request.IdempotencyKey).
+			Msg("failed to get idempotency record")
+
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, nil
+	}
+
+	return s.replayIdempotencyRecord(ctx, request, record)
+}
+
+// claimIdempotencyKey claims the idempotency key of the request inside the
+// database transaction. When a concurrent request already created a
+// transaction with the same key, that transaction is returned to be replayed.
+func (s *service) claimIdempotencyKey(ctx context.Context, request CreateTransactionRequest) (*models.Transaction, error) {
+	if request.IdempotencyKey == nil || *request.IdempotencyKey == "" {
+		return nil, nil
+	}
+
+	claimed, err := s.idempotencyRecordRepository.ClaimIdempotencyKey(ctx, models.IdempotencyRecord{
+		CustomerAccountID:  request.CustomerAccountID,
+		IdempotencyKey:     *request.IdempotencyKey,
+		RequestFingerprint: request.fingerprint(),
+	}, idempotencyClaimTimeout)
+	if err != nil {
+		if repository.IsLockNotAvailable(err) {
+			return nil, errIdempotencyKeyInProgress()
+		}
+
+		log.Err(err).
+			Str("customer_account_id", request.CustomerAccountID.String()).
+			Str("idempotency_key", *request.IdempotencyKey).
+			Msg("failed to claim idempotency key")
+
+		return nil, err
+	}
+
+	if claimed {
+		return nil, nil
+	}
+
+	record, err := s.idempotencyRecordRepository.GetIdempotencyRecord(
+		ctx, request.CustomerAccountID, *request.IdempotencyKey,
+	)
+	if err != nil {
+		log.Err(err).
+			Str("customer_account_id", request.CustomerAccountID.String()).
+			Str("idempotency_key", *request.IdempotencyKey).
+			Msg("failed to get idempotency record")
+
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, errIdempotencyKeyInProgress()
+	}
+
+	return s.replayIdempotencyRecord(ctx, request, record)
+}
+
+func (s *service) replayIdempotencyRecord(
+	ctx context.Context,
+	request CreateTransactionRequest,
+	record *models.IdempotencyRecord,
+) (*models.Transaction, error) {
+	if record.RequestFingerprint != request.fingerprint() {
+		return nil, cerror.New(cerror.Params{
+			Status:  http.StatusUnprocessableEntity,
+			Message: "Idempotency key was already used with a different payload",
+		})
+	}
+
+	if record.TransactionID == nil {
+		return nil, errIdempotencyKeyInProgress()
+	}
+
+	transaction, err := s.transactionRepository.GetTransactionByID(ctx, record.TransactionID)
+	if err != nil {
+		log.Err(err).
+			Str("transaction_id", record.TransactionID.String()).
+			Str("idempotency_key", record.IdempotencyKey).
+			Msg("failed to get idempotent transaction")
+
+		return nil, err
+	}
+
+	if transaction == nil {
+		return nil, cerror.New(cerror.Params{
+			Status:  http.StatusInternalServerError,
+			Message: "Idempotent transaction not found",
+		})
+	}
+
+	return transaction, nil
+}
+
+func (s *service) linkIdempotencyRecord(
+	ctx context.Context,
+	request CreateTransactionRequest,
+	transaction *models.Transaction,
+) error {
 	if request.IdempotencyKey == nil || *request.IdempotencyKey == "" {
 		return nil
 	}
 
-	transaction, err := s.transactionRepository.GetTransactionByIdempotencyKey(ctx, *request.IdempotencyKey)
+	err := s.idempotencyRecordRepository.SetIdempotencyRecordTransaction(ctx, models.IdempotencyRecord{
+		CustomerAccountID: request.CustomerAccountID,
+		IdempotencyKey:    *request.IdempotencyKey,
+		TransactionID:     transaction.ID,
+	})
 	if err != nil {
 		log.Err(err).
+			Str("transaction_id", transaction.ID.String()).
 			Str("idempotency_key", *request.IdempotencyKey).
-			Msg("failed to check transaction by idempotency key")
+			Msg("failed to link idempotency record to transaction")
 
 		return err
 	}
 
-	if transaction != nil {
-		return cerror.New(cerror.Params{
-			Status:  http.StatusConflict,
-			Message: "Transaction is already created with that idempotency key",
+	return nil
+}
+
+func errIdempotencyKeyInProgress() error {
+	return cerror.New(cerror.Params{
+		Status:  http.StatusConflict,
+		Message: "A transaction with that idempotency key is in progress",
+	})
+}
+
+func (s *service) resolveOperationType(request CreateTransactionRequest) (operationtypes.OperationTypeResult, error) {
+	operationType, ok := s.operationTypes.Lookup(request.OperationType)
+	if !ok {
+		return operationtypes.OperationTypeResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, cerror.FieldError{
+			Field:   "OperationType",
+			Message: "Unknown operation type",
 		})
 	}
 
-	return nil
+	if !operationType.Enabled {
+		return operationtypes.OperationTypeResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Operation type is disabled",
+		})
+	}
+
+	if !operationType.AllowsAmount(utils.ToCents(request.Amount)) {
+		return operationtypes.OperationTypeResult{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Amount is outside the limits of the operation type",
+		})
+	}
+
+	return operationType, nil
 }
 
 func (s *service) findCustomerAccount(ctx context.Context, customerAccountID *uuid.UUID) (*repository.CustomerAccountByIDResult, error) {
@@ -101,33 +535,84 @@ func (s *service) findCustomerAccount(ctx context.Context, customerAccountID *uu
 		})
 	}
 
+	if customerAccount.BlockedAt != nil {
+		return nil, cerror.New(cerror.Params{
+			Status:  http.StatusLocked,
+			Message: "Customer account is blocked",
+		})
+	}
+
 	return customerAccount, nil
 }
 
+// processTransaction runs the transaction, retrying it under the optimistic
+// strategy while it keeps losing the balance version to concurrent ones, with
+// the same jittered backoff as the database transaction retries.
 func (s *service) processTransaction(
 	ctx context.Context,
 	customerAccount *repository.CustomerAccountByIDResult,
-	request createTransactionRequest,
-) (*models.Transaction, error) {
-	var transactionCreated *models.Transaction
+	operationType operationtypes.OperationTypeResult,
+	request CreateTransactionRequest,
+) (*models.Transaction, bool, error) {
+	for attempt := 0; ; attempt++ {
+		transaction, replayed, err := s.processTransactionAttempt(ctx, customerAccount, operationType, request)
+		if !errors.Is(err, errBalanceVersionConflict) {
+			return transaction, replayed, err
+		}
+
+		if attempt >= s.options.OptimisticMaxRetries {
+			log.Warn().
+				Str("customer_account_id", customerAccount.ID.String()).
+				Int("attempts", attempt+1).
+				Msg("gave up updating balance after version conflicts")
+
+			return nil, false, cerror.New(cerror.Params{
+				Status:  http.StatusConflict,
+				Message: "Balance was updated concurrently, try again",
+			})
+		}
+
+		if err := repository.WaitRetryBackoff(ctx, attempt); err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+func (s *service) processTransactionAttempt(
+	ctx context.Context,
+	customerAccount *repository.CustomerAccountByIDResult,
+	operationType operationtypes.OperationTypeResult,
+	request CreateTransactionRequest,
+) (*models.Transaction, bool, error) {
+	var (
+		transactionCreated *models.Transaction
+		replayed           bool
+	)
 
 	err := s.transactionRepository.WithTransaction(ctx, func(txCtx context.Context) error {
-		accountBalance, err := s.getAccountBalance(txCtx, customerAccount.ID, request)
+		replayedTransaction, err := s.claimIdempotencyKey(txCtx, request)
 		if err != nil {
 			return err
 		}
 
-		amountCents, err := s.calculateTransactionAmount(request, accountBalance.Balance)
+		if replayedTransaction != nil {
+			transactionCreated = replayedTransaction
+			replayed = true
+
+			return nil
+		}
+
+		accountBalance, err := s.getAccountBalance(txCtx, customerAccount.ID, request)
 		if err != nil {
 			return err
 		}
 
-		transactionCreated, err = s.createTransaction(txCtx, customerAccount, request, amountCents)
+		transactionCreated, err = s.applyTransaction(txCtx, customerAccount, operationType, request, accountBalance.Balance)
 		if err != nil {
 			return err
 		}
 
-		if err := s.updateBalance(txCtx, customerAccount, accountBalance.Balance, amountCents); err != nil {
+		if err := s.updateBalance(txCtx, customerAccount, accountBalance, *transactionCreated.BalanceAfter); err != nil {
 			return err
 		}
 
@@ -135,18 +620,132 @@ func (s *service) processTransaction(
 	})
 
 	if err != nil {
+		return nil, false, err
+	}
+
+	return transactionCreated, replayed, nil
+}
+
+// applyTransaction writes the transaction on top of currentBalance, with its
+// journal entry, leaving the balance update to the caller.
+func (s *service) applyTransaction(
+	ctx context.Context,
+	customerAccount *repository.CustomerAccountByIDResult,
+	operationType operationtypes.OperationTypeResult,
+	request CreateTransactionRequest,
+	currentBalance int64,
+) (*models.Transaction, error) {
+	amountCents, err := s.calculateTransactionAmount(operationType, request, currentBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.createTransaction(ctx, customerAccount, request, amountCents, currentBalance+amountCents)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.linkIdempotencyRecord(ctx, request, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := s.postJournalEntry(ctx, transaction, operationType); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTransactionEvents(ctx, transaction, currentBalance); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTransactionAudit(ctx, transaction); err != nil {
 		return nil, err
 	}
 
-	return transactionCreated, nil
+	return transaction, nil
+}
+
+// recordTransactionEvents writes the transaction.created and balance.changed
+// events of the transaction to the outbox, in the database transaction that
+// creates it.
+func (s *service) recordTransactionEvents(
+	ctx context.Context, transaction *models.Transaction, previousBalance int64,
+) error {
+	transactionCreated, err := events.NewOutboxEvent(
+		events.TransactionCreated,
+		transaction.CustomerAccountID,
+		events.TransactionCreatedPayload{
+			TransactionID: transaction.ID,
+			AccountID:     transaction.CustomerAccountID,
+			OperationType: transaction.OperationType,
+			Amount:        utils.FromCents(transaction.Amount),
+			BalanceAfter:  utils.FromCentsPointer(transaction.BalanceAfter),
+			CreatedAt:     transaction.CreatedAt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	balanceChanged, err := events.NewOutboxEvent(
+		events.BalanceChanged,
+		transaction.CustomerAccountID,
+		events.BalanceChangedPayload{
+			AccountID:       transaction.CustomerAccountID,
+			TransactionID:   transaction.ID,
+			PreviousBalance: utils.FromCents(previousBalance),
+			Balance:         utils.FromCents(*transaction.BalanceAfter),
+			ChangedAt:       transaction.CreatedAt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s.outboxRepository.CreateOutboxEvents(ctx, transactionCreated, balanceChanged); err != nil {
+		log.Err(err).
+			Str("transaction_id", transaction.ID.String()).
+			Msg("failed to record transaction events")
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) recordTransactionAudit(ctx context.Context, transaction *models.Transaction) error {
+	entry, err := audit.NewEntry(ctx, audit.Change{
+		Action:            audit.ActionCreate,
+		EntityType:        audit.EntityTransaction,
+		EntityID:          transaction.ID,
+		CustomerAccountID: transaction.CustomerAccountID,
+		After:             DomainToGetTransactionResponse(DatabaseToTransactionResult(*transaction)),
+	})
+	if err == nil {
+		_, err = s.auditLogRepository.CreateAuditLog(ctx, entry)
+	}
+
+	if err != nil {
+		log.Err(err).
+			Str("transaction_id", transaction.ID.String()).
+			Msg("failed to record transaction audit log")
+
+		return err
+	}
+
+	return nil
 }
 
 func (s *service) getAccountBalance(
 	ctx context.Context,
 	customerAccountID *uuid.UUID,
-	request createTransactionRequest,
+	request CreateTransactionRequest,
 ) (*models.Balance, error) {
-	accountBalance, err := s.balanceRepository.GetCustomerAccountBalance(ctx, customerAccountID)
+	getBalance := s.balanceRepository.GetCustomerAccountBalance
+	if s.options.BalanceLockStrategy == OptimisticBalanceLock {
+		getBalance = s.balanceRepository.GetCustomerAccountBalanceUnlocked
+	}
+
+	accountBalance, err := getBalance(ctx, customerAccountID)
 	if err != nil {
 		log.Err(err).
 			Str("customer_account_id", customerAccountID.String()).
@@ -160,14 +759,18 @@ func (s *service) getAccountBalance(
 	return accountBalance, nil
 }
 
-func (s *service) calculateTransactionAmount(request createTransactionRequest, currentBalance int64) (int64, error) {
+func (s *service) calculateTransactionAmount(
+	operationType operationtypes.OperationTypeResult,
+	request CreateTransactionRequest,
+	currentBalance int64,
+) (int64, error) {
 	amountCents := utils.ToCents(request.Amount)
 
-	if err := s.isValidOperation(request.OperationType, amountCents, currentBalance); err != nil {
+	if err := s.isValidOperation(operationType, amountCents, currentBalance); err != nil {
 		return 0, err
 	}
 
-	amountWithDirection, err := utils.ApplyMoneyDirection(amountCents, request.OperationType)
+	amountWithDirection, err := utils.ApplyMoneyDirection(amountCents, operationType.Direction)
 	if err != nil {
 		return 0, err
 	}
@@ -178,13 +781,15 @@ func (s *service) calculateTransactionAmount(request createTransactionRequest, c
 func (s *service) createTransaction(
 	ctx context.Context,
 	customerAccount *repository.CustomerAccountByIDResult,
-	request createTransactionRequest,
+	request CreateTransactionRequest,
 	amountCents int64,
+	balanceAfter int64,
 ) (*models.Transaction, error) {
 	transaction, err := s.transactionRepository.CreateTransaction(ctx, models.Transaction{
 		CustomerAccountID: customerAccount.ID,
 		OperationType:     request.OperationType,
 		Amount:            amountCents,
+		BalanceAfter:      &balanceAfter,
 		IdempotencyKey:    request.IdempotencyKey,
 	})
 	if err != nil {
@@ -200,18 +805,84 @@ func (s *service) createTransaction(
 	return transaction, nil
 }
 
+// postJournalEntry records the transaction as a balanced journal entry, moving
+// the amount between the customer account and the counterparty ledger account.
+func (s *service) postJournalEntry(
+	ctx context.Context,
+	transaction *models.Transaction,
+	operationType operationtypes.OperationTypeResult,
+) error {
+	counterpartyCode := operationType.CounterpartyLedgerAccount
+
+	counterparty, err := s.ledgerRepository.GetLedgerAccountByCode(ctx, counterpartyCode)
+	if err != nil {
+		log.Err(err).
+			Str("ledger_account_code", string(counterpartyCode)).
+			Msg("failed to get counterparty ledger account")
+
+		return err
+	}
+
+	if counterparty == nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusInternalServerError,
+			Message: "Counterparty ledger account not found",
+		})
+	}
+
+	_, err = s.ledgerRepository.CreateJournalEntry(ctx, models.JournalEntry{
+		TransactionID: transaction.ID,
+	}, []models.JournalPosting{
+		{
+			CustomerAccountID: transaction.CustomerAccountID,
+			Amount:            transaction.Amount,
+		},
+		{
+			LedgerAccountID: counterparty.ID,
+			Amount:          -transaction.Amount,
+		},
+	})
+	if err != nil {
+		log.Err(err).
+			Str("transaction_id", transaction.ID.String()).
+			Msg("failed to create journal entry")
+
+		return err
+	}
+
+	return nil
+}
+
 func (s *service) updateBalance(
 	ctx context.Context,
 	customerAccount *repository.CustomerAccountByIDResult,
-	currentBalance int64,
-	amountCents int64,
+	currentBalance *models.Balance,
+	newBalance int64,
 ) error {
-	newBalance := currentBalance + amountCents
-
-	_, err := s.balanceRepository.UpdateCustomerAccountBalance(ctx, models.Balance{
+	balance := models.Balance{
 		CustomerAccountID: customerAccount.ID,
 		Balance:           newBalance,
-	})
+	}
+
+	if s.options.BalanceLockStrategy == OptimisticBalanceLock {
+		updated, err := s.balanceRepository.UpdateCustomerAccountBalanceIfVersion(ctx, balance, currentBalance.Version)
+		if err != nil {
+			log.Err(err).
+				Str("customer_account_id", customerAccount.ID.String()).
+				Int64("new_balance", newBalance).
+				Msg("failed to update customer balance")
+
+			return err
+		}
+
+		if !updated {
+			return errBalanceVersionConflict
+		}
+
+		return nil
+	}
+
+	_, err := s.balanceRepository.UpdateCustomerAccountBalance(ctx, balance)
 	if err != nil {
 		log.Err(err).
 			Str("customer_account_id", customerAccount.ID.String()).
@@ -224,21 +895,20 @@ func (s *service) updateBalance(
 	return nil
 }
 
-func (s *service) isValidOperation(operation models.OperationType, amount int64, customerAccountBalance int64) error {
-	if s.isCreditOperation(operation) {
+func (s *service) isValidOperation(
+	operationType operationtypes.OperationTypeResult, amount int64, customerAccountBalance int64,
+) error {
+	if operationType.IsCredit() {
 		return nil
 	}
 
 	if amount > customerAccountBalance {
 		return cerror.New(cerror.Params{
 			Status:  http.StatusBadRequest,
+			Code:    ErrCodeInsufficientFunds,
 			Message: "Insufficient funds to perform operation",
 		})
 	}
 
 	return nil
 }
-
-func (s *service) isCreditOperation(operation models.OperationType) bool {
-	return operation == models.CreditVoucher
-}
@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type WebhookResult struct {
+	ID         *uuid.UUID
+	URL        string
+	EventTypes []string
+	// Secret is only set when the webhook is created; it's never shown again.
+	Secret    string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type DeliveryResult struct {
+	ID             *uuid.UUID
+	WebhookID      *uuid.UUID
+	EventID        *uuid.UUID
+	EventType      string
+	Payload        json.RawMessage
+	Status         models.WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LastAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      *string
+	CreatedAt      time.Time
+	AttemptLog     []DeliveryAttemptResult
+}
+
+type DeliveryAttemptResult struct {
+	Attempt        int
+	ResponseStatus *int
+	ResponseBody   *string
+	Error          *string
+	DurationMs     int64
+	CreatedAt      time.Time
+}
+
+type DeliverResult struct {
+	Succeeded int
+	Retried   int
+	Failed    int
+}
+
+func DatabaseToWebhookResult(subscription models.WebhookSubscription) WebhookResult {
+	return WebhookResult{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Enabled:    subscription.Enabled,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func DatabaseToDeliveryResult(delivery models.WebhookDelivery) DeliveryResult {
+	result := DeliveryResult{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookSubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	if delivery.Status == models.WebhookDeliveryPending {
+		result.NextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	return result
+}
+
+func DatabaseToDeliveryAttemptResult(attempt models.WebhookDeliveryAttempt) DeliveryAttemptResult {
+	return DeliveryAttemptResult{
+		Attempt:        attempt.Attempt,
+		ResponseStatus: attempt.ResponseStatus,
+		ResponseBody:   attempt.ResponseBody,
+		Error:          attempt.Error,
+		DurationMs:     attempt.DurationMs,
+		CreatedAt:      attempt.CreatedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/validator"
+)
+
+type httpHandler struct {
+	service Servicer
+}
+
+func NewHTTPHandler(app *fiber.App, service Servicer) {
+	httpHandler := &httpHandler{
+		service: service,
+	}
+
+	routeGroup := app.Group("/webhooks")
+	routeGroup.Post("/", httpHandler.createWebhook)
+	routeGroup.Get("/", httpHandler.listWebhooks)
+	routeGroup.Get("/:webhookId", httpHandler.getWebhook)
+	routeGroup.Patch("/:webhookId", httpHandler.updateWebhook)
+	routeGroup.Delete("/:webhookId", httpHandler.deleteWebhook)
+	routeGroup.Get("/:webhookId/deliveries", httpHandler.listDeliveries)
+	routeGroup.Get("/:webhookId/deliveries/:deliveryId", httpHandler.getDelivery)
+	routeGroup.Post("/:webhookId/deliveries/:deliveryId/redeliver", httpHandler.redeliver)
+}
+
+func (h *httpHandler) createWebhook(c *fiber.Ctx) error {
+	var body createWebhookRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook payload",
+		})
+	}
+
+	if err := validator.ValidateStruct(body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	webhook, err := h.service.CreateWebhook(c.Context(), body)
+	if err != nil {
+		log.Err(err).Msg("failed to create webhook")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToWebhookResponse(webhook))
+}
+
+func (h *httpHandler) listWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.service.ListWebhooks(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToWebhooksResponse(webhooks))
+}
+
+func (h *httpHandler) getWebhook(c *fiber.Ctx) error {
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := h.service.GetWebhook(c.Context(), webhookRequest{
+		WebhookID: webhookID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToWebhookResponse(webhook))
+}
+
+func (h *httpHandler) updateWebhook(c *fiber.Ctx) error {
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	var body updateWebhookRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook payload",
+		})
+	}
+
+	body.WebhookID = webhookID
+
+	if err := validator.ValidateStruct(body); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	webhook, err := h.service.UpdateWebhook(c.Context(), body)
+	if err != nil {
+		log.Err(err).Msg("failed to update webhook")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToWebhookResponse(webhook))
+}
+
+func (h *httpHandler) deleteWebhook(c *fiber.Ctx) error {
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteWebhook(c.Context(), webhookRequest{WebhookID: webhookID}); err != nil {
+		log.Err(err).Msg("failed to delete webhook")
+
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func (h *httpHandler) listDeliveries(c *fiber.Ctx) error {
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	request := listDeliveriesRequest{
+		WebhookID: webhookID,
+	}
+
+	if status := c.Query("status"); status != "" {
+		deliveryStatus := models.WebhookDeliveryStatus(status)
+		request.Status = &deliveryStatus
+	}
+
+	if err := validator.ValidateStruct(request); err != nil {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, err.FieldErrors...)
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToDeliveriesResponse(deliveries))
+}
+
+func (h *httpHandler) getDelivery(c *fiber.Ctx) error {
+	request, err := parseDeliveryRequest(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := h.service.GetDelivery(c.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToDeliveryResponse(delivery))
+}
+
+func (h *httpHandler) redeliver(c *fiber.Ctx) error {
+	request, err := parseDeliveryRequest(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := h.service.Redeliver(c.Context(), request)
+	if err != nil {
+		log.Err(err).Msg("failed to redeliver webhook")
+
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(DomainToDeliveryResponse(delivery))
+}
+
+func parseWebhookID(c *fiber.Ctx) (*uuid.UUID, error) {
+	webhookID, err := uuid.FromString(c.Params("webhookId"))
+	if err != nil {
+		return nil, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook id",
+		})
+	}
+
+	return &webhookID, nil
+}
+
+func parseDeliveryRequest(c *fiber.Ctx) (deliveryRequest, error) {
+	webhookID, err := parseWebhookID(c)
+	if err != nil {
+		return deliveryRequest{}, err
+	}
+
+	deliveryID, err := uuid.FromString(c.Params("deliveryId"))
+	if err != nil {
+		return deliveryRequest{}, cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid delivery id",
+		})
+	}
+
+	return deliveryRequest{
+		WebhookID:  webhookID,
+		DeliveryID: &deliveryID,
+	}, nil
+}
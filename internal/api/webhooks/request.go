@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Secret     *string  `json:"secret" validate:"omitempty,min=16,max=255"`
+	Enabled    *bool    `json:"enabled" validate:"omitempty"`
+}
+
+type updateWebhookRequest struct {
+	WebhookID  *uuid.UUID `json:"-"`
+	URL        *string    `json:"url" validate:"omitempty,http_url,max=2048"`
+	EventTypes []string   `json:"event_types" validate:"omitempty,min=1,dive,required"`
+	Secret     *string    `json:"secret" validate:"omitempty,min=16,max=255"`
+	Enabled    *bool      `json:"enabled" validate:"omitempty"`
+}
+
+type webhookRequest struct {
+	WebhookID *uuid.UUID
+}
+
+type listDeliveriesRequest struct {
+	WebhookID *uuid.UUID
+	Status    *models.WebhookDeliveryStatus `validate:"omitempty,oneof=pending succeeded failed"`
+}
+
+type deliveryRequest struct {
+	WebhookID  *uuid.UUID
+	DeliveryID *uuid.UUID
+}
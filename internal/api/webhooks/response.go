@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type WebhookResponse struct {
+	ID         *uuid.UUID `json:"id"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	Secret     string     `json:"secret,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type WebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type DeliveryResponse struct {
+	ID             *uuid.UUID                   `json:"id"`
+	WebhookID      *uuid.UUID                   `json:"webhook_id"`
+	EventID        *uuid.UUID                   `json:"event_id"`
+	EventType      string                       `json:"event_type"`
+	Payload        json.RawMessage              `json:"payload"`
+	Status         models.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	NextAttemptAt  *time.Time                   `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time                   `json:"last_attempt_at"`
+	ResponseStatus *int                         `json:"response_status"`
+	LastError      *string                      `json:"last_error"`
+	CreatedAt      time.Time                    `json:"created_at"`
+	AttemptLog     []DeliveryAttemptResponse    `json:"attempt_log,omitempty"`
+}
+
+type DeliveryAttemptResponse struct {
+	Attempt        int       `json:"attempt"`
+	ResponseStatus *int      `json:"response_status"`
+	ResponseBody   *string   `json:"response_body"`
+	Error          *string   `json:"error"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type DeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
+
+func DomainToWebhookResponse(result WebhookResult) WebhookResponse {
+	return WebhookResponse{
+		ID:         result.ID,
+		URL:        result.URL,
+		EventTypes: result.EventTypes,
+		Secret:     result.Secret,
+		Enabled:    result.Enabled,
+		CreatedAt:  result.CreatedAt,
+		UpdatedAt:  result.UpdatedAt,
+	}
+}
+
+func DomainToWebhooksResponse(results []WebhookResult) WebhooksResponse {
+	webhooks := make([]WebhookResponse, 0, len(results))
+
+	for _, result := range results {
+		webhooks = append(webhooks, DomainToWebhookResponse(result))
+	}
+
+	return WebhooksResponse{Webhooks: webhooks}
+}
+
+func DomainToDeliveryResponse(result DeliveryResult) DeliveryResponse {
+	var attemptLog []DeliveryAttemptResponse
+
+	for _, attempt := range result.AttemptLog {
+		attemptLog = append(attemptLog, DeliveryAttemptResponse{
+			Attempt:        attempt.Attempt,
+			ResponseStatus: attempt.ResponseStatus,
+			ResponseBody:   attempt.ResponseBody,
+			Error:          attempt.Error,
+			DurationMs:     attempt.DurationMs,
+			CreatedAt:      attempt.CreatedAt,
+		})
+	}
+
+	return DeliveryResponse{
+		ID:             result.ID,
+		WebhookID:      result.WebhookID,
+		EventID:        result.EventID,
+		EventType:      result.EventType,
+		Payload:        result.Payload,
+		Status:         result.Status,
+		Attempts:       result.Attempts,
+		NextAttemptAt:  result.NextAttemptAt,
+		LastAttemptAt:  result.LastAttemptAt,
+		ResponseStatus: result.ResponseStatus,
+		LastError:      result.LastError,
+		CreatedAt:      result.CreatedAt,
+		AttemptLog:     attemptLog,
+	}
+}
+
+func DomainToDeliveriesResponse(results []DeliveryResult) DeliveriesResponse {
+	deliveries := make([]DeliveryResponse, 0, len(results))
+
+	for _, result := range results {
+		deliveries = append(deliveries, DomainToDeliveryResponse(result))
+	}
+
+	return DeliveriesResponse{Deliveries: deliveries}
+}
@@ -0,0 +1,525 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	defaultMaxAttempts = 8
+	defaultTimeout     = 10 * time.Second
+	defaultBatchSize   = 50
+
+	// deliveryLeaseMargin is added to the timeout of a request to lease the
+	// deliveries being sent, so another worker doesn't send them meanwhile.
+	deliveryLeaseMargin = 30 * time.Second
+
+	// maxResponseBodySize is how much of the answer of the receiver is kept in
+	// the delivery log.
+	maxResponseBodySize = 1024
+)
+
+// Servicer manages the webhook subscriptions and their deliveries. As an
+// events.Publisher it enqueues a delivery of each published event for every
+// enabled subscription to its type; DeliverDue sends them.
+type Servicer interface {
+	events.Publisher
+	CreateWebhook(context.Context, createWebhookRequest) (WebhookResult, error)
+	ListWebhooks(context.Context) ([]WebhookResult, error)
+	GetWebhook(context.Context, webhookRequest) (WebhookResult, error)
+	UpdateWebhook(context.Context, updateWebhookRequest) (WebhookResult, error)
+	DeleteWebhook(context.Context, webhookRequest) error
+	ListDeliveries(context.Context, listDeliveriesRequest) ([]DeliveryResult, error)
+	GetDelivery(context.Context, deliveryRequest) (DeliveryResult, error)
+	Redeliver(context.Context, deliveryRequest) (DeliveryResult, error)
+	DeliverDue(context.Context) (DeliverResult, error)
+}
+
+// Options tunes the deliveries. Zero values fall back to the defaults.
+type Options struct {
+	// MaxAttempts is how many times a delivery is tried before it's failed.
+	MaxAttempts int
+	// Timeout bounds each request sent to a receiver.
+	Timeout time.Duration
+	// BatchSize is how many deliveries are sent concurrently.
+	BatchSize int
+}
+
+type service struct {
+	webhookRepository repository.WebhookRepository
+	httpClient        *http.Client
+	options           Options
+}
+
+func NewService(webhookRepository repository.WebhookRepository, options Options) Servicer {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBatchSize
+	}
+
+	return &service{
+		webhookRepository: webhookRepository,
+		httpClient:        &http.Client{Timeout: options.Timeout},
+		options:           options,
+	}
+}
+
+func (s *service) CreateWebhook(ctx context.Context, request createWebhookRequest) (WebhookResult, error) {
+	if err := validateEventTypes(request.EventTypes); err != nil {
+		return WebhookResult{}, err
+	}
+
+	subscription := models.WebhookSubscription{
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+		Enabled:    true,
+	}
+
+	if request.Enabled != nil {
+		subscription.Enabled = *request.Enabled
+	}
+
+	if request.Secret != nil {
+		subscription.Secret = *request.Secret
+	} else {
+		secret, err := generateSecret()
+		if err != nil {
+			return WebhookResult{}, err
+		}
+
+		subscription.Secret = secret
+	}
+
+	created, err := s.webhookRepository.CreateWebhookSubscription(ctx, subscription)
+	if err != nil {
+		return WebhookResult{}, err
+	}
+
+	result := DatabaseToWebhookResult(*created)
+	result.Secret = created.Secret
+
+	return result, nil
+}
+
+func (s *service) ListWebhooks(ctx context.Context) ([]WebhookResult, error) {
+	subscriptions, err := s.webhookRepository.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]WebhookResult, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		results = append(results, DatabaseToWebhookResult(subscription))
+	}
+
+	return results, nil
+}
+
+func (s *service) GetWebhook(ctx context.Context, request webhookRequest) (WebhookResult, error) {
+	subscription, err := s.getSubscription(ctx, request.WebhookID)
+	if err != nil {
+		return WebhookResult{}, err
+	}
+
+	return DatabaseToWebhookResult(*subscription), nil
+}
+
+func (s *service) UpdateWebhook(ctx context.Context, request updateWebhookRequest) (WebhookResult, error) {
+	if request.EventTypes != nil {
+		if err := validateEventTypes(request.EventTypes); err != nil {
+			return WebhookResult{}, err
+		}
+	}
+
+	subscription, err := s.getSubscription(ctx, request.WebhookID)
+	if err != nil {
+		return WebhookResult{}, err
+	}
+
+	if request.URL != nil {
+		subscription.URL = *request.URL
+	}
+
+	if request.EventTypes != nil {
+		subscription.EventTypes = request.EventTypes
+	}
+
+	if request.Secret != nil {
+		subscription.Secret = *request.Secret
+	}
+
+	if request.Enabled != nil {
+		subscription.Enabled = *request.Enabled
+	}
+
+	if err := s.webhookRepository.UpdateWebhookSubscription(ctx, *subscription); err != nil {
+		return WebhookResult{}, err
+	}
+
+	return s.GetWebhook(ctx, webhookRequest{WebhookID: request.WebhookID})
+}
+
+// DeleteWebhook removes the subscription along with its deliveries.
+func (s *service) DeleteWebhook(ctx context.Context, request webhookRequest) error {
+	deleted, err := s.webhookRepository.DeleteWebhookSubscription(ctx, request.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return webhookNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *service) ListDeliveries(ctx context.Context, request listDeliveriesRequest) ([]DeliveryResult, error) {
+	if _, err := s.getSubscription(ctx, request.WebhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepository.ListWebhookDeliveries(ctx, request.WebhookID, request.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DeliveryResult, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		results = append(results, DatabaseToDeliveryResult(delivery))
+	}
+
+	return results, nil
+}
+
+// GetDelivery returns the delivery with every attempt made to send it.
+func (s *service) GetDelivery(ctx context.Context, request deliveryRequest) (DeliveryResult, error) {
+	delivery, err := s.getDelivery(ctx, request)
+	if err != nil {
+		return DeliveryResult{}, err
+	}
+
+	attempts, err := s.webhookRepository.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		return DeliveryResult{}, err
+	}
+
+	result := DatabaseToDeliveryResult(*delivery)
+	for _, attempt := range attempts {
+		result.AttemptLog = append(result.AttemptLog, DatabaseToDeliveryAttemptResult(attempt))
+	}
+
+	return result, nil
+}
+
+// Redeliver sends the delivery right away, whatever its status, e.g. after the
+// receiver of a failed delivery is fixed. A pending delivery that fails again
+// keeps being retried; any other is marked as failed, as a manual attempt
+// doesn't restart the retries.
+func (s *service) Redeliver(ctx context.Context, request deliveryRequest) (DeliveryResult, error) {
+	subscription, err := s.getSubscription(ctx, request.WebhookID)
+	if err != nil {
+		return DeliveryResult{}, err
+	}
+
+	delivery, err := s.getDelivery(ctx, request)
+	if err != nil {
+		return DeliveryResult{}, err
+	}
+
+	retry := delivery.Status == models.WebhookDeliveryPending
+
+	if err := s.attemptDelivery(ctx, *subscription, delivery, retry); err != nil {
+		log.Err(err).Str("delivery_id", delivery.ID.String()).Msg("failed to record webhook delivery")
+
+		return DeliveryResult{}, err
+	}
+
+	return s.GetDelivery(ctx, request)
+}
+
+// Publish enqueues a delivery of the event for every enabled subscription to
+// its type. An event published again isn't enqueued twice.
+func (s *service) Publish(ctx context.Context, event events.Event) error {
+	subscriptions, err := s.webhookRepository.ListWebhookSubscriptionsForEvent(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			WebhookSubscriptionID: subscription.ID,
+			EventID:               event.ID,
+			EventType:             event.Type,
+			Payload:               payload,
+		})
+	}
+
+	return s.webhookRepository.CreateWebhookDeliveries(ctx, deliveries...)
+}
+
+// DeliverDue sends the due deliveries until none is left, up to BatchSize at
+// a time. A delivery that fails is retried with an exponential backoff until
+// it's failed after MaxAttempts.
+func (s *service) DeliverDue(ctx context.Context) (DeliverResult, error) {
+	var result DeliverResult
+
+	subscriptions := map[uuid.UUID]*models.WebhookSubscription{}
+
+	for {
+		deliveries, err := s.webhookRepository.ClaimDueWebhookDeliveries(
+			ctx, s.options.BatchSize, s.options.Timeout+deliveryLeaseMargin,
+		)
+		if err != nil {
+			return result, err
+		}
+
+		if len(deliveries) == 0 {
+			return result, nil
+		}
+
+		for _, delivery := range deliveries {
+			if _, ok := subscriptions[*delivery.WebhookSubscriptionID]; ok {
+				continue
+			}
+
+			subscription, err := s.webhookRepository.GetWebhookSubscription(ctx, delivery.WebhookSubscriptionID)
+			if err != nil {
+				return result, err
+			}
+
+			subscriptions[*delivery.WebhookSubscriptionID] = subscription
+		}
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			firstErr error
+		)
+
+		for index := range deliveries {
+			delivery := &deliveries[index]
+
+			subscription := subscriptions[*delivery.WebhookSubscriptionID]
+			if subscription == nil {
+				continue
+			}
+
+			wg.Go(func() {
+				err := s.attemptDelivery(ctx, *subscription, delivery, true)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+
+					return
+				}
+
+				switch delivery.Status {
+				case models.WebhookDeliverySucceeded:
+					result.Succeeded++
+				case models.WebhookDeliveryFailed:
+					result.Failed++
+				default:
+					result.Retried++
+				}
+			})
+		}
+
+		wg.Wait()
+
+		if firstErr != nil {
+			return result, firstErr
+		}
+	}
+}
+
+// attemptDelivery sends the delivery once and records the attempt. When it
+// fails and retry is set, the delivery is rescheduled unless it ran out of
+// attempts.
+func (s *service) attemptDelivery(
+	ctx context.Context,
+	subscription models.WebhookSubscription,
+	delivery *models.WebhookDelivery,
+	retry bool,
+) error {
+	startedAt := time.Now()
+	responseStatus, responseBody, sendErr := s.send(ctx, subscription, *delivery)
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &startedAt
+	delivery.ResponseStatus = responseStatus
+
+	attempt := models.WebhookDeliveryAttempt{
+		WebhookDeliveryID: delivery.ID,
+		Attempt:           delivery.Attempts,
+		ResponseStatus:    responseStatus,
+		ResponseBody:      responseBody,
+		DurationMs:        time.Since(startedAt).Milliseconds(),
+	}
+
+	if sendErr == nil && *responseStatus >= 200 && *responseStatus < 300 {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = nil
+	} else {
+		var lastError string
+		if sendErr != nil {
+			lastError = sendErr.Error()
+		} else {
+			lastError = fmt.Sprintf("receiver answered with status %d", *responseStatus)
+		}
+
+		attempt.Error = &lastError
+		delivery.LastError = &lastError
+
+		if retry && delivery.Attempts < s.options.MaxAttempts {
+			delivery.Status = models.WebhookDeliveryPending
+			delivery.NextAttemptAt = time.Now().Add(events.RetryDelay(delivery.Attempts))
+		} else {
+			delivery.Status = models.WebhookDeliveryFailed
+		}
+
+		log.Warn().
+			Str("delivery_id", delivery.ID.String()).
+			Str("webhook_id", subscription.ID.String()).
+			Int("attempts", delivery.Attempts).
+			Str("status", string(delivery.Status)).
+			Msg(lastError)
+	}
+
+	return s.webhookRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.webhookRepository.CreateWebhookDeliveryAttempt(txCtx, attempt); err != nil {
+			return err
+		}
+
+		return s.webhookRepository.UpdateWebhookDeliveryResult(txCtx, *delivery)
+	})
+}
+
+// send posts the payload of the delivery to the receiver, signed with the
+// secret of the subscription, and returns what it answered.
+func (s *service) send(
+	ctx context.Context,
+	subscription models.WebhookSubscription,
+	delivery models.WebhookDelivery,
+) (*int, *string, error) {
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(IDHeader, delivery.EventID.String())
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseBodySize))
+	if err != nil {
+		return &response.StatusCode, nil, err
+	}
+
+	responseBody := string(body)
+
+	return &response.StatusCode, &responseBody, nil
+}
+
+func (s *service) getSubscription(ctx context.Context, webhookID *uuid.UUID) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepository.GetWebhookSubscription(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription == nil {
+		return nil, webhookNotFoundError()
+	}
+
+	return subscription, nil
+}
+
+func (s *service) getDelivery(ctx context.Context, request deliveryRequest) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepository.GetWebhookDelivery(ctx, request.WebhookID, request.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery == nil {
+		return nil, cerror.New(cerror.Params{
+			Status:  http.StatusNotFound,
+			Message: "Webhook delivery not found",
+		})
+	}
+
+	return delivery, nil
+}
+
+func validateEventTypes(eventTypes []string) error {
+	var fieldErrors []cerror.FieldError
+
+	for index, eventType := range eventTypes {
+		if _, ok := events.SchemaVersions[eventType]; !ok {
+			fieldErrors = append(fieldErrors, cerror.FieldError{
+				Field:   fmt.Sprintf("event_types[%d]", index),
+				Message: fmt.Sprintf("unknown event type %q", eventType),
+			})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid payload",
+		}, fieldErrors...)
+	}
+
+	return nil
+}
+
+func webhookNotFoundError() error {
+	return cerror.New(cerror.Params{
+		Status:  http.StatusNotFound,
+		Message: "Webhook not found",
+	})
+}
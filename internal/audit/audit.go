@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+type ContextKey string
+
+// RequestKey is where the request being audited is kept in the context (or
+// in the user values of the fasthttp request).
+const RequestKey ContextKey = "audit_request"
+
+const (
+	ActionCreate  = "create"
+	ActionRequest = "request"
+
+	EntityAccount     = "account"
+	EntityTransaction = "transaction"
+
+	// SystemActor is the actor of changes made outside of a request, e.g. by
+	// a background job.
+	SystemActor = "system"
+)
+
+// Request is who made a request and from where, for the entries recorded
+// while handling it.
+type Request struct {
+	Actor     string
+	ClientIP  string
+	UserAgent string
+	RequestID string
+	Method    string
+	Route     string
+
+	recorded atomic.Bool
+}
+
+// Recorded tells whether an entry was built for the request.
+func (r *Request) Recorded() bool {
+	return r.recorded.Load()
+}
+
+func WithRequest(ctx context.Context, request *Request) context.Context {
+	return context.WithValue(ctx, RequestKey, request)
+}
+
+func RequestFromContext(ctx context.Context) *Request {
+	request, _ := ctx.Value(RequestKey).(*Request)
+
+	return request
+}
+
+// Change is what an entry records about the entity it refers to. Before and
+// After are marshaled to JSON, and left empty when nil.
+type Change struct {
+	Action            string
+	EntityType        string
+	EntityID          *uuid.UUID
+	CustomerAccountID *uuid.UUID
+	Before            any
+	After             any
+}
+
+// NewEntry builds the audit log row of a change made by the request carried
+// by ctx, or by the system when there's none. It must be written in the same
+// database transaction as the change, so an entry exists if and only if the
+// change was committed.
+func NewEntry(ctx context.Context, change Change) (models.AuditLog, error) {
+	before, err := marshalSnapshot(change.Before)
+	if err != nil {
+		return models.AuditLog{}, err
+	}
+
+	after, err := marshalSnapshot(change.After)
+	if err != nil {
+		return models.AuditLog{}, err
+	}
+
+	entry := models.AuditLog{
+		Action:            change.Action,
+		EntityType:        optional(change.EntityType),
+		EntityID:          change.EntityID,
+		CustomerAccountID: change.CustomerAccountID,
+		Actor:             SystemActor,
+		Before:            before,
+		After:             after,
+	}
+
+	request := RequestFromContext(ctx)
+	if request == nil {
+		return entry, nil
+	}
+
+	request.recorded.Store(true)
+
+	if request.Actor != "" {
+		entry.Actor = request.Actor
+	}
+
+	entry.ClientIP = optional(request.ClientIP)
+	entry.UserAgent = optional(request.UserAgent)
+	entry.RequestID = optional(request.RequestID)
+	entry.Method = optional(request.Method)
+	entry.Route = optional(request.Route)
+
+	return entry, nil
+}
+
+func marshalSnapshot(snapshot any) (json.RawMessage, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(snapshot)
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
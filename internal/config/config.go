@@ -12,7 +12,14 @@ type AppConfig struct {
 }
 
 type EnvironmentVariables struct {
-	Database DatabaseConfig
+	Database     DatabaseConfig
+	Events       EventsConfig
+	HTTP         HTTPConfig
+	Jobs         JobsConfig
+	Schedules    SchedulesConfig
+	Statements   StatementsConfig
+	Transactions TransactionsConfig
+	Webhooks     WebhooksConfig
 }
 
 func load() (*AppConfig, error) {
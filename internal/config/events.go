@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type EventsConfig struct {
+	// Publishers are the adapters every event is published to: log, stdout,
+	// file, nats or http.
+	Publishers []string `env:"EVENT_PUBLISHERS" envSeparator:"," envDefault:"log"`
+
+	FilePath string `env:"EVENT_FILE_PATH" envDefault:"events.jsonl"`
+
+	NATSURL           string        `env:"EVENT_NATS_URL" envDefault:"nats://127.0.0.1:4222"`
+	NATSSubjectPrefix string        `env:"EVENT_NATS_SUBJECT_PREFIX" envDefault:"accounts"`
+	NATSTimeout       time.Duration `env:"EVENT_NATS_TIMEOUT" envDefault:"5s"`
+	NATSEmbedded      bool          `env:"EVENT_NATS_EMBEDDED" envDefault:"false"`
+	NATSEmbeddedPort  int           `env:"EVENT_NATS_EMBEDDED_PORT" envDefault:"4222"`
+
+	HTTPSinkURL           string        `env:"EVENT_HTTP_SINK_URL"`
+	HTTPSinkAuthorization string        `env:"EVENT_HTTP_SINK_AUTHORIZATION"`
+	HTTPSinkTimeout       time.Duration `env:"EVENT_HTTP_SINK_TIMEOUT" envDefault:"10s"`
+}
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+type HTTPConfig struct {
+	IdempotencyLease time.Duration `env:"IDEMPOTENCY_LEASE" envDefault:"30s"`
+	MetricsEnabled   bool          `env:"METRICS_ENABLED" envDefault:"false"`
+	MetricsPort      string        `env:"METRICS_PORT" envDefault:":9090"`
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+type JobsConfig struct {
+	BalanceSnapshotEnabled  bool          `env:"BALANCE_SNAPSHOT_ENABLED" envDefault:"true"`
+	BalanceSnapshotInterval time.Duration `env:"BALANCE_SNAPSHOT_INTERVAL" envDefault:"1h"`
+	BalanceSnapshotTimezone string        `env:"BALANCE_SNAPSHOT_TIMEZONE" envDefault:"America/Sao_Paulo"`
+
+	ReconciliationEnabled       bool          `env:"RECONCILIATION_ENABLED" envDefault:"false"`
+	ReconciliationInterval      time.Duration `env:"RECONCILIATION_INTERVAL" envDefault:"24h"`
+	ReconciliationBlockAccounts bool          `env:"RECONCILIATION_BLOCK_ACCOUNTS" envDefault:"false"`
+
+	IdempotencyKeysCleanupEnabled  bool          `env:"IDEMPOTENCY_KEYS_CLEANUP_ENABLED" envDefault:"true"`
+	IdempotencyKeysCleanupInterval time.Duration `env:"IDEMPOTENCY_KEYS_CLEANUP_INTERVAL" envDefault:"1h"`
+	IdempotencyKeyRetention        time.Duration `env:"IDEMPOTENCY_KEY_RETENTION" envDefault:"720h"`
+
+	OperationTypesRefreshInterval time.Duration `env:"OPERATION_TYPES_REFRESH_INTERVAL" envDefault:"1m"`
+
+	OutboxDispatcherEnabled  bool          `env:"OUTBOX_DISPATCHER_ENABLED" envDefault:"true"`
+	OutboxDispatcherInterval time.Duration `env:"OUTBOX_DISPATCHER_INTERVAL" envDefault:"1s"`
+	OutboxBatchSize          int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	OutboxMaxAttempts        int           `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"10"`
+}
+
+func (j *JobsConfig) BalanceSnapshotLocation() (*time.Location, error) {
+	return time.LoadLocation(j.BalanceSnapshotTimezone)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
+	"github.com/rs/zerolog/log"
+)
+
+// MetricsRouter serves the runtime metrics (expvar) on /debug/vars. It has
+// its own listener, so the metrics aren't reachable through the API port and
+// their port can be kept private.
+type MetricsRouter struct {
+	app  *fiber.App
+	port string
+}
+
+func NewMetricsRouter(httpConfig HTTPConfig) *MetricsRouter {
+	app := fiber.New(fiber.Config{
+		AppName:               AppName,
+		DisableStartupMessage: true,
+	})
+
+	app.Use(expvar.New())
+
+	return &MetricsRouter{
+		app:  app,
+		port: httpConfig.MetricsPort,
+	}
+}
+
+func (router *MetricsRouter) Start() error {
+	log.Info().Msg("starting metrics server on port " + router.port)
+
+	if err := router.app.Listen(router.port); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func (router *MetricsRouter) GetApp() *fiber.App {
+	return router.app
+}
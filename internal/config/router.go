@@ -7,13 +7,19 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/rs/zerolog/log"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
 )
 
 type AppRouter struct {
 	app *fiber.App
 }
 
-func NewRouter() *AppRouter {
+func NewRouter(
+	idempotentRequestRepository repository.IdempotentRequestRepository,
+	auditLogRepository repository.AuditLogRepository,
+	httpConfig HTTPConfig,
+) *AppRouter {
 	router := &AppRouter{}
 
 	app := fiber.New(fiber.Config{
@@ -23,6 +29,10 @@ func NewRouter() *AppRouter {
 	})
 
 	app.Use(cors.New())
+	app.Use(middleware.Audit(auditLogRepository))
+	app.Use(middleware.Idempotency(idempotentRequestRepository, middleware.IdempotencyOptions{
+		Lease: httpConfig.IdempotencyLease,
+	}))
 	app.Get("/status", func(c *fiber.Ctx) error {
 		return c.SendString("ok")
 	})
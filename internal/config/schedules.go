@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+type SchedulesConfig struct {
+	Enabled       bool          `env:"SCHEDULES_ENABLED" envDefault:"true"`
+	Interval      time.Duration `env:"SCHEDULES_INTERVAL" envDefault:"10s"`
+	BatchSize     int           `env:"SCHEDULES_BATCH_SIZE" envDefault:"50"`
+	MaxAttempts   int           `env:"SCHEDULES_MAX_ATTEMPTS" envDefault:"3"`
+	RetryInterval time.Duration `env:"SCHEDULES_RETRY_INTERVAL" envDefault:"1h"`
+	Timezone      string        `env:"SCHEDULES_TIMEZONE" envDefault:"America/Sao_Paulo"`
+}
+
+func (s *SchedulesConfig) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
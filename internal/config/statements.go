@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type StatementsConfig struct {
+	Timezone string `env:"STATEMENTS_TIMEZONE" envDefault:"America/Sao_Paulo"`
+	BankID   string `env:"STATEMENTS_BANK_ID" envDefault:"000"`
+	Currency string `env:"STATEMENTS_CURRENCY" envDefault:"BRL"`
+}
+
+func (s *StatementsConfig) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
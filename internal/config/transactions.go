@@ -0,0 +1,9 @@
+package config
+
+type TransactionsConfig struct {
+	BalanceLockStrategy         string `env:"BALANCE_LOCK_STRATEGY" envDefault:"pessimistic"`
+	BalanceOptimisticMaxRetries int    `env:"BALANCE_OPTIMISTIC_MAX_RETRIES" envDefault:"5"`
+	AccountQueueEnabled         bool   `env:"ACCOUNT_QUEUE_ENABLED" envDefault:"false"`
+	AccountQueueSize            int    `env:"ACCOUNT_QUEUE_SIZE" envDefault:"100"`
+	AccountQueueMaxBatch        int    `env:"ACCOUNT_QUEUE_MAX_BATCH" envDefault:"50"`
+}
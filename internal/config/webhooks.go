@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+type WebhooksConfig struct {
+	DeliveryEnabled   bool          `env:"WEBHOOK_DELIVERY_ENABLED" envDefault:"true"`
+	DeliveryInterval  time.Duration `env:"WEBHOOK_DELIVERY_INTERVAL" envDefault:"1s"`
+	DeliveryBatchSize int           `env:"WEBHOOK_DELIVERY_BATCH_SIZE" envDefault:"50"`
+	MaxAttempts       int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	Timeout           time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+}
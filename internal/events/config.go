@@ -0,0 +1,86 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tiagovaldrich/accounts-api/internal/config"
+)
+
+// Adapters the events can be published to, as named in EVENT_PUBLISHERS.
+const (
+	LogAdapter    = "log"
+	StdoutAdapter = "stdout"
+	FileAdapter   = "file"
+	NATSAdapter   = "nats"
+	HTTPAdapter   = "http"
+)
+
+// NewPublisherFromConfig returns a publisher to every adapter of the config.
+// With EVENT_NATS_EMBEDDED the NATS adapter starts a server in this process
+// and publishes to it instead of EVENT_NATS_URL. The publisher must be closed
+// with ClosePublisher.
+func NewPublisherFromConfig(cfg config.EventsConfig) (Publisher, error) {
+	var publishers []Publisher
+
+	for _, adapter := range cfg.Publishers {
+		publisher, err := newAdapterPublisher(strings.TrimSpace(adapter), cfg)
+		if err != nil {
+			for _, opened := range publishers {
+				_ = ClosePublisher(opened)
+			}
+
+			return nil, fmt.Errorf("event publisher %q: %w", adapter, err)
+		}
+
+		publishers = append(publishers, publisher)
+	}
+
+	if len(publishers) == 0 {
+		return nil, errors.New("no event publisher configured")
+	}
+
+	if len(publishers) == 1 {
+		return publishers[0], nil
+	}
+
+	return NewMultiPublisher(publishers...), nil
+}
+
+func newAdapterPublisher(adapter string, cfg config.EventsConfig) (Publisher, error) {
+	switch adapter {
+	case LogAdapter:
+		return NewLogPublisher(), nil
+	case StdoutAdapter:
+		return NewStdoutPublisher(), nil
+	case FileAdapter:
+		return NewFilePublisher(cfg.FilePath)
+	case NATSAdapter:
+		if !cfg.NATSEmbedded {
+			return NewNATSPublisher(cfg.NATSURL, cfg.NATSSubjectPrefix, cfg.NATSTimeout)
+		}
+
+		natsServer, err := RunEmbeddedNATSServer(cfg.NATSEmbeddedPort)
+		if err != nil {
+			return nil, err
+		}
+
+		publisher, err := newNATSPublisher(natsServer.ClientURL(), cfg.NATSSubjectPrefix, cfg.NATSTimeout, natsServer)
+		if err != nil {
+			natsServer.Shutdown()
+
+			return nil, err
+		}
+
+		return publisher, nil
+	case HTTPAdapter:
+		if cfg.HTTPSinkURL == "" {
+			return nil, errors.New("EVENT_HTTP_SINK_URL is required")
+		}
+
+		return NewHTTPPublisher(cfg.HTTPSinkURL, cfg.HTTPSinkAuthorization, cfg.HTTPSinkTimeout), nil
+	default:
+		return nil, errors.New("unknown adapter")
+	}
+}
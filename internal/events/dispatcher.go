@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	dispatchRetryBaseDelay = time.Second
+	dispatchRetryMaxDelay  = time.Hour
+)
+
+type DispatchResult struct {
+	Dispatched   int
+	Retried      int
+	DeadLettered int
+}
+
+// Dispatcher publishes the events of the outbox. Events of the same account
+// are published one at a time in the order they were written; an event that
+// fails is retried with an exponential backoff, holding back the events after
+// it, until it's moved to the dead letter state after maxAttempts.
+type Dispatcher struct {
+	outboxRepository repository.OutboxRepository
+	publisher        Publisher
+	batchSize        int
+	maxAttempts      int
+}
+
+func NewDispatcher(
+	outboxRepository repository.OutboxRepository,
+	publisher Publisher,
+	batchSize int,
+	maxAttempts int,
+) *Dispatcher {
+	return &Dispatcher{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+		batchSize:        batchSize,
+		maxAttempts:      maxAttempts,
+	}
+}
+
+// Dispatch publishes the due events until none is left.
+func (d *Dispatcher) Dispatch(ctx context.Context) (DispatchResult, error) {
+	var result DispatchResult
+
+	for {
+		batchResult, claimed, err := d.dispatchBatch(ctx)
+		if err != nil {
+			return result, err
+		}
+
+		result.Dispatched += batchResult.Dispatched
+		result.Retried += batchResult.Retried
+		result.DeadLettered += batchResult.DeadLettered
+
+		if claimed == 0 {
+			return result, nil
+		}
+	}
+}
+
+// dispatchBatch publishes the oldest due event of up to batchSize accounts,
+// holding their rows locked so other dispatchers skip them.
+func (d *Dispatcher) dispatchBatch(ctx context.Context) (DispatchResult, int, error) {
+	var (
+		result  DispatchResult
+		claimed int
+	)
+
+	err := d.outboxRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		result = DispatchResult{}
+
+		outboxEvents, err := d.outboxRepository.ClaimDueOutboxEvents(txCtx, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		claimed = len(outboxEvents)
+
+		for _, outboxEvent := range outboxEvents {
+			outboxEvent.Attempts++
+
+			if err := d.publisher.Publish(ctx, DatabaseToEvent(outboxEvent)); err != nil {
+				d.reschedule(&outboxEvent, err)
+
+				if outboxEvent.Status == models.OutboxEventDeadLetter {
+					result.DeadLettered++
+				} else {
+					result.Retried++
+				}
+			} else {
+				dispatchedAt := time.Now()
+
+				outboxEvent.Status = models.OutboxEventDispatched
+				outboxEvent.DispatchedAt = &dispatchedAt
+				outboxEvent.LastError = nil
+				result.Dispatched++
+			}
+
+			if err := d.outboxRepository.UpdateOutboxEventDelivery(txCtx, outboxEvent); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, claimed, err
+}
+
+func (d *Dispatcher) reschedule(outboxEvent *models.OutboxEvent, publishErr error) {
+	lastError := publishErr.Error()
+	outboxEvent.LastError = &lastError
+
+	logEvent := log.Warn()
+	if outboxEvent.Attempts >= d.maxAttempts {
+		outboxEvent.Status = models.OutboxEventDeadLetter
+		logEvent = log.Error()
+	} else {
+		outboxEvent.NextAttemptAt = time.Now().Add(RetryDelay(outboxEvent.Attempts))
+	}
+
+	logEvent.Err(publishErr).
+		Str("event_id", outboxEvent.EventID.String()).
+		Str("event_type", outboxEvent.EventType).
+		Int("attempts", outboxEvent.Attempts).
+		Str("status", string(outboxEvent.Status)).
+		Msg("failed to publish event")
+}
+
+// RetryDelay is how long to wait before the next attempt of something that
+// failed attempts times: one second doubling each time, capped at an hour.
+func RetryDelay(attempts int) time.Duration {
+	delay := dispatchRetryBaseDelay
+
+	for range attempts - 1 {
+		delay *= 2
+
+		if delay >= dispatchRetryMaxDelay {
+			return dispatchRetryMaxDelay
+		}
+	}
+
+	return delay
+}
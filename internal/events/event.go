@@ -0,0 +1,85 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+const (
+	AccountCreated     = "account.created"
+	TransactionCreated = "transaction.created"
+	BalanceChanged     = "balance.changed"
+)
+
+// SchemaVersions is the current version of the payload of each event type.
+// A change that isn't backwards compatible (a field removed, renamed or with a
+// new meaning) must bump it, so consumers can tell the payloads apart.
+var SchemaVersions = map[string]int{
+	AccountCreated:     1,
+	TransactionCreated: 1,
+	BalanceChanged:     1,
+}
+
+// Event is the envelope events are published in.
+type Event struct {
+	ID         *uuid.UUID      `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	AccountID  *uuid.UUID      `json:"account_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type AccountCreatedPayload struct {
+	AccountID      *uuid.UUID `json:"account_id"`
+	CustomerID     *uuid.UUID `json:"customer_id"`
+	DocumentNumber string     `json:"document_number"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type TransactionCreatedPayload struct {
+	TransactionID *uuid.UUID           `json:"transaction_id"`
+	AccountID     *uuid.UUID           `json:"account_id"`
+	OperationType models.OperationType `json:"operation_type"`
+	Amount        float64              `json:"amount"`
+	BalanceAfter  *float64             `json:"balance_after"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+type BalanceChangedPayload struct {
+	AccountID       *uuid.UUID `json:"account_id"`
+	TransactionID   *uuid.UUID `json:"transaction_id"`
+	PreviousBalance float64    `json:"previous_balance"`
+	Balance         float64    `json:"balance"`
+	ChangedAt       time.Time  `json:"changed_at"`
+}
+
+// NewOutboxEvent builds the outbox row of an event of the account, with the
+// payload at the current schema version of its type.
+func NewOutboxEvent(eventType string, accountID *uuid.UUID, payload any) (models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.OutboxEvent{}, err
+	}
+
+	return models.OutboxEvent{
+		EventType:         eventType,
+		SchemaVersion:     SchemaVersions[eventType],
+		CustomerAccountID: accountID,
+		Payload:           data,
+	}, nil
+}
+
+func DatabaseToEvent(outboxEvent models.OutboxEvent) Event {
+	return Event{
+		ID:         outboxEvent.EventID,
+		Type:       outboxEvent.EventType,
+		Version:    outboxEvent.SchemaVersion,
+		AccountID:  outboxEvent.CustomerAccountID,
+		OccurredAt: outboxEvent.CreatedAt,
+		Data:       outboxEvent.Payload,
+	}
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type httpPublisher struct {
+	url           string
+	authorization string
+	client        *http.Client
+}
+
+// NewHTTPPublisher returns a publisher that POSTs each event as JSON to the
+// URL, with the Authorization header when it's set. Any answer but a 2xx
+// fails the publishing, so the event is retried.
+func NewHTTPPublisher(url string, authorization string, timeout time.Duration) Publisher {
+	return &httpPublisher{
+		url:           url,
+		authorization: authorization,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, event.ID.String())
+	request.Header.Set(EventTypeHeader, event.Type)
+	request.Header.Set(EventVersionHeader, strconv.Itoa(event.Version))
+
+	if p.authorization != "" {
+		request.Header.Set("Authorization", p.authorization)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("event sink answered with status %d", response.StatusCode)
+	}
+
+	return nil
+}
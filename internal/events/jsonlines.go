@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+type jsonLinesPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+// NewJSONLinesPublisher returns a publisher that writes each event to w as a
+// line of JSON.
+func NewJSONLinesPublisher(w io.Writer) Publisher {
+	return &jsonLinesPublisher{writer: w}
+}
+
+// NewStdoutPublisher returns a publisher that writes the events to the
+// standard output as JSON lines.
+func NewStdoutPublisher() Publisher {
+	return NewJSONLinesPublisher(os.Stdout)
+}
+
+// NewFilePublisher returns a publisher that appends the events to the file as
+// JSON lines, creating it if needed.
+func NewFilePublisher(path string) (Publisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonLinesPublisher{writer: file, closer: file}, nil
+}
+
+func (p *jsonLinesPublisher) Publish(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.writer.Write(append(line, '\n'))
+
+	return err
+}
+
+func (p *jsonLinesPublisher) Close() error {
+	if p.closer == nil {
+		return nil
+	}
+
+	return p.closer.Close()
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+const embeddedNATSReadyTimeout = 5 * time.Second
+
+type natsPublisher struct {
+	conn          *nats.Conn
+	subjectPrefix string
+	timeout       time.Duration
+	// server is the embedded server the publisher is connected to, if any.
+	server *server.Server
+}
+
+// NewNATSPublisher returns a publisher that sends each event to the NATS
+// server at url, on the subject NATSSubject gives it. Publishing waits for the
+// server to acknowledge it got the event, for up to timeout.
+func NewNATSPublisher(url string, subjectPrefix string, timeout time.Duration) (Publisher, error) {
+	return newNATSPublisher(url, subjectPrefix, timeout, nil)
+}
+
+func newNATSPublisher(
+	url string, subjectPrefix string, timeout time.Duration, natsServer *server.Server,
+) (*natsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("accounts-api"), nats.Timeout(timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	return &natsPublisher{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+		timeout:       timeout,
+		server:        natsServer,
+	}, nil
+}
+
+// RunEmbeddedNATSServer starts a NATS server in this process, listening on
+// the loopback interface. A port of -1 picks a random one, which tests use.
+func RunEmbeddedNATSServer(port int) (*server.Server, error) {
+	natsServer, err := server.NewServer(&server.Options{
+		Host:   "127.0.0.1",
+		Port:   port,
+		NoLog:  true,
+		NoSigs: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	natsServer.Start()
+
+	if !natsServer.ReadyForConnections(embeddedNATSReadyTimeout) {
+		natsServer.Shutdown()
+
+		return nil, errors.New("embedded NATS server isn't ready for connections")
+	}
+
+	return natsServer, nil
+}
+
+// NATSSubject is the subject an event is published on: the prefix, the event
+// type and its schema version, e.g. "accounts.transaction.created.v1". A
+// consumer subscribes to the versions it understands, or to all of them with
+// "accounts.transaction.created.>".
+func NATSSubject(subjectPrefix string, event Event) string {
+	return fmt.Sprintf("%s.%s.v%d", subjectPrefix, event.Type, event.Version)
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	message := nats.NewMsg(NATSSubject(p.subjectPrefix, event))
+	message.Data = data
+	// Lets a JetStream stream on the subject drop the events published again.
+	message.Header.Set(nats.MsgIdHdr, event.ID.String())
+	message.Header.Set(EventIDHeader, event.ID.String())
+	message.Header.Set(EventTypeHeader, event.Type)
+	message.Header.Set(EventVersionHeader, strconv.Itoa(event.Version))
+
+	if err := p.conn.PublishMsg(message); err != nil {
+		return err
+	}
+
+	return p.conn.FlushTimeout(p.timeout)
+}
+
+func (p *natsPublisher) Close() error {
+	p.conn.Close()
+
+	if p.server != nil {
+		p.server.Shutdown()
+	}
+
+	return nil
+}
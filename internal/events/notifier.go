@@ -0,0 +1,116 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// OutboxChannel is the Postgres channel the id of the account is notified on
+// whenever an event of it is written to the outbox.
+const OutboxChannel = "outbox_event"
+
+// Notifier tells the subscribers of an account when new events of it were
+// committed. It LISTENs on OutboxChannel, so it hears about the events written
+// by every instance of the API, not only this one.
+//
+// Subscribers are woken up rather than handed the events: they read what they
+// missed from the outbox, which also covers notifications lost while the
+// listener was reconnecting, as long as they look again every now and then.
+type Notifier struct {
+	db *bun.DB
+
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan struct{}]struct{}
+}
+
+func NewNotifier(db *bun.DB) *Notifier {
+	return &Notifier{
+		db:          db,
+		subscribers: map[uuid.UUID]map[chan struct{}]struct{}{},
+	}
+}
+
+// Start listens for notifications until ctx is done.
+func (n *Notifier) Start(ctx context.Context) error {
+	listener := pgdriver.NewListener(n.db)
+
+	if err := listener.Listen(ctx, OutboxChannel); err != nil {
+		_ = listener.Close()
+
+		return err
+	}
+
+	go func() {
+		defer listener.Close()
+
+		notifications := listener.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-notifications:
+				if notification.Channel != OutboxChannel {
+					continue
+				}
+
+				accountID, err := uuid.FromString(notification.Payload)
+				if err != nil {
+					log.Err(err).Str("payload", notification.Payload).Msg("invalid outbox notification")
+
+					continue
+				}
+
+				n.notify(accountID)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Subscribe returns a channel that receives a value when new events of the
+// account are committed, and a function to stop receiving them. Notifications
+// sent while the subscriber is busy are folded into one.
+func (n *Notifier) Subscribe(accountID uuid.UUID) (<-chan struct{}, func()) {
+	wakeUp := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.subscribers[accountID] == nil {
+		n.subscribers[accountID] = map[chan struct{}]struct{}{}
+	}
+
+	n.subscribers[accountID][wakeUp] = struct{}{}
+
+	unsubscribe := func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		delete(n.subscribers[accountID], wakeUp)
+
+		if len(n.subscribers[accountID]) == 0 {
+			delete(n.subscribers, accountID)
+		}
+	}
+
+	return wakeUp, unsubscribe
+}
+
+func (n *Notifier) notify(accountID uuid.UUID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for wakeUp := range n.subscribers[accountID] {
+		select {
+		case wakeUp <- struct{}{}:
+		default:
+		}
+	}
+}
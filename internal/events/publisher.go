@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Headers sent along with the events by the adapters whose transport has
+// them, so consumers can route and decode an event without parsing it first.
+const (
+	EventIDHeader      = "Event-Id"
+	EventTypeHeader    = "Event-Type"
+	EventVersionHeader = "Event-Version"
+)
+
+// Publisher delivers events to downstream systems. Delivery is at least once:
+// an event may be published again if the dispatcher fails to record it was
+// published, so consumers must deduplicate by its id.
+//
+// Publishers holding a connection or a file also implement io.Closer.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// ClosePublisher releases what the publisher holds, if anything.
+func ClosePublisher(publisher Publisher) error {
+	if closer, ok := publisher.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+type logPublisher struct{}
+
+// NewLogPublisher returns a publisher that only logs the events.
+func NewLogPublisher() Publisher {
+	return logPublisher{}
+}
+
+func (logPublisher) Publish(ctx context.Context, event Event) error {
+	log.Info().
+		Str("event_id", event.ID.String()).
+		Str("event_type", event.Type).
+		Int("version", event.Version).
+		Str("customer_account_id", event.AccountID.String()).
+		RawJSON("data", event.Data).
+		Msg("event published")
+
+	return nil
+}
+
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher returns a publisher that publishes each event to all the
+// publishers. It fails if any of them fails, so the event is published to all
+// of them again.
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return multiPublisher{publishers: publishers}
+}
+
+func (p multiPublisher) Publish(ctx context.Context, event Event) error {
+	var errs []error
+
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p multiPublisher) Close() error {
+	var errs []error
+
+	for _, publisher := range p.publishers {
+		if err := ClosePublisher(publisher); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+// balanceSnapshotGracePeriod keeps the job away from the end of the day until
+// transactions stamped right before midnight had time to commit.
+const balanceSnapshotGracePeriod = 10 * time.Minute
+
+type balanceSnapshotJob struct {
+	balanceSnapshotRepository repository.BalanceSnapshotRepository
+	interval                  time.Duration
+	location                  *time.Location
+}
+
+func NewBalanceSnapshotJob(
+	balanceSnapshotRepository repository.BalanceSnapshotRepository,
+	interval time.Duration,
+	location *time.Location,
+) Job {
+	return &balanceSnapshotJob{
+		balanceSnapshotRepository: balanceSnapshotRepository,
+		interval:                  interval,
+		location:                  location,
+	}
+}
+
+func (j *balanceSnapshotJob) Name() string {
+	return "balance_snapshot"
+}
+
+func (j *balanceSnapshotJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run writes the end-of-day snapshot of the last day that is fully closed.
+// Snapshots that already exist are left untouched, so running it more than
+// once a day (or on several instances) is harmless.
+func (j *balanceSnapshotJob) Run(ctx context.Context) error {
+	snapshotAt := EndOfLastClosedDay(time.Now().Add(-balanceSnapshotGracePeriod), j.location)
+
+	created, err := j.balanceSnapshotRepository.CreateBalanceSnapshots(ctx, snapshotAt)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Time("snapshot_at", snapshotAt).
+		Int64("snapshots_created", created).
+		Msg("balance snapshots created")
+
+	return nil
+}
+
+// EndOfLastClosedDay returns the midnight that closed the day before now in location.
+func EndOfLastClosedDay(now time.Time, location *time.Location) time.Time {
+	localNow := now.In(location)
+
+	return time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, location)
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const idempotencyKeysCleanupBatchSize = 1000
+
+type idempotencyKeysCleanupJob struct {
+	idempotencyRecordRepository repository.IdempotencyRecordRepository
+	idempotentRequestRepository repository.IdempotentRequestRepository
+	interval                    time.Duration
+	retention                   time.Duration
+}
+
+func NewIdempotencyKeysCleanupJob(
+	idempotencyRecordRepository repository.IdempotencyRecordRepository,
+	idempotentRequestRepository repository.IdempotentRequestRepository,
+	interval time.Duration,
+	retention time.Duration,
+) Job {
+	return &idempotencyKeysCleanupJob{
+		idempotencyRecordRepository: idempotencyRecordRepository,
+		idempotentRequestRepository: idempotentRequestRepository,
+		interval:                    interval,
+		retention:                   retention,
+	}
+}
+
+func (j *idempotencyKeysCleanupJob) Name() string {
+	return "idempotency_keys_cleanup"
+}
+
+func (j *idempotencyKeysCleanupJob) Interval() time.Duration {
+	return j.interval
+}
+
+// Run purges the idempotency keys older than the retention window, both the
+// transaction ones and the Idempotency-Key header ones.
+func (j *idempotencyKeysCleanupJob) Run(ctx context.Context) error {
+	createdBefore := time.Now().Add(-j.retention)
+
+	keysPurged, err := purgeInBatches(ctx, createdBefore, j.idempotencyRecordRepository.PurgeIdempotencyRecords)
+	if err != nil {
+		return err
+	}
+
+	requestsPurged, err := purgeInBatches(ctx, createdBefore, j.idempotentRequestRepository.PurgeIdempotentRequests)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Time("created_before", createdBefore).
+		Int64("idempotency_keys_purged", keysPurged).
+		Int64("idempotent_requests_purged", requestsPurged).
+		Msg("idempotency keys purged")
+
+	return nil
+}
+
+// purgeInBatches keeps purging until a batch comes back short, so a large
+// backlog doesn't hold row locks for long.
+func purgeInBatches(
+	ctx context.Context,
+	createdBefore time.Time,
+	purge func(ctx context.Context, createdBefore time.Time, limit int) (int64, error),
+) (int64, error) {
+	var purged int64
+
+	for {
+		batchPurged, err := purge(ctx, createdBefore, idempotencyKeysCleanupBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		purged += batchPurged
+
+		if batchPurged < idempotencyKeysCleanupBatchSize {
+			return purged, nil
+		}
+	}
+}
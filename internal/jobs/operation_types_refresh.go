@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+)
+
+// operationTypesRefreshJob reloads the operation types cache so changes made
+// through another instance reach this one without a restart.
+type operationTypesRefreshJob struct {
+	operationTypesService operationtypes.Servicer
+	interval              time.Duration
+}
+
+func NewOperationTypesRefreshJob(operationTypesService operationtypes.Servicer, interval time.Duration) Job {
+	return &operationTypesRefreshJob{
+		operationTypesService: operationTypesService,
+		interval:              interval,
+	}
+}
+
+func (j *operationTypesRefreshJob) Name() string {
+	return "operation_types_refresh"
+}
+
+func (j *operationTypesRefreshJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *operationTypesRefreshJob) Run(ctx context.Context) error {
+	return j.operationTypesService.Load(ctx)
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+)
+
+type outboxDispatcherJob struct {
+	dispatcher *events.Dispatcher
+	interval   time.Duration
+}
+
+func NewOutboxDispatcherJob(dispatcher *events.Dispatcher, interval time.Duration) Job {
+	return &outboxDispatcherJob{
+		dispatcher: dispatcher,
+		interval:   interval,
+	}
+}
+
+func (j *outboxDispatcherJob) Name() string {
+	return "outbox_dispatcher"
+}
+
+func (j *outboxDispatcherJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *outboxDispatcherJob) Run(ctx context.Context) error {
+	result, err := j.dispatcher.Dispatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.Dispatched > 0 || result.Retried > 0 || result.DeadLettered > 0 {
+		log.Info().
+			Int("dispatched", result.Dispatched).
+			Int("retried", result.Retried).
+			Int("dead_lettered", result.DeadLettered).
+			Msg("outbox events dispatched")
+	}
+
+	return nil
+}
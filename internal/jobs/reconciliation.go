@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+)
+
+type reconciliationJob struct {
+	reconciliationService reconciliation.Servicer
+	interval              time.Duration
+	blockAccounts         bool
+}
+
+func NewReconciliationJob(
+	reconciliationService reconciliation.Servicer,
+	interval time.Duration,
+	blockAccounts bool,
+) Job {
+	return &reconciliationJob{
+		reconciliationService: reconciliationService,
+		interval:              interval,
+		blockAccounts:         blockAccounts,
+	}
+}
+
+func (j *reconciliationJob) Name() string {
+	return "reconciliation"
+}
+
+func (j *reconciliationJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *reconciliationJob) Run(ctx context.Context) error {
+	report, err := j.reconciliationService.Reconcile(ctx, reconciliation.ReconcileRequest{
+		BlockAccounts: j.blockAccounts,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, drift := range report.Drifts {
+		log.Warn().
+			Str("reconciliation_run_id", report.RunID.String()).
+			Str("customer_account_id", drift.CustomerAccountID.String()).
+			Int64("balance", drift.Balance).
+			Int64("transactions_total", drift.TransactionsTotal).
+			Int64("drift", drift.Drift).
+			Bool("account_blocked", drift.AccountBlocked).
+			Msg("balance drift found")
+	}
+
+	log.Info().
+		Str("reconciliation_run_id", report.RunID.String()).
+		Int("accounts_checked", report.AccountsChecked).
+		Int("drifts_found", len(report.Drifts)).
+		Msg("reconciliation finished")
+
+	return nil
+}
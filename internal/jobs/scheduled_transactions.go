@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/schedules"
+)
+
+type scheduledTransactionsJob struct {
+	service  schedules.Servicer
+	interval time.Duration
+}
+
+func NewScheduledTransactionsJob(service schedules.Servicer, interval time.Duration) Job {
+	return &scheduledTransactionsJob{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (j *scheduledTransactionsJob) Name() string {
+	return "scheduled_transactions"
+}
+
+func (j *scheduledTransactionsJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *scheduledTransactionsJob) Run(ctx context.Context) error {
+	result, err := j.service.RunDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.Created > 0 || result.Skipped > 0 || result.Retried > 0 || result.Failed > 0 {
+		log.Info().
+			Int("created", result.Created).
+			Int("skipped", result.Skipped).
+			Int("retried", result.Retried).
+			Int("failed", result.Failed).
+			Msg("scheduled transactions run")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/api/webhooks"
+)
+
+type webhookDeliveryJob struct {
+	service  webhooks.Servicer
+	interval time.Duration
+}
+
+func NewWebhookDeliveryJob(service webhooks.Servicer, interval time.Duration) Job {
+	return &webhookDeliveryJob{
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (j *webhookDeliveryJob) Name() string {
+	return "webhook_delivery"
+}
+
+func (j *webhookDeliveryJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *webhookDeliveryJob) Run(ctx context.Context) error {
+	result, err := j.service.DeliverDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.Succeeded > 0 || result.Retried > 0 || result.Failed > 0 {
+		log.Info().
+			Int("succeeded", result.Succeeded).
+			Int("retried", result.Retried).
+			Int("failed", result.Failed).
+			Msg("webhooks delivered")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+// AuditLog is an entry of the append-only audit log: who changed what, from
+// where and through which request. Before and After hold snapshots of the
+// entity, when it's known.
+type AuditLog struct {
+	bun.BaseModel     `bun:"table:audit_log"`
+	ID                *uuid.UUID      `bun:"id,pk"`
+	Action            string          `bun:"action"`
+	EntityType        *string         `bun:"entity_type"`
+	EntityID          *uuid.UUID      `bun:"entity_id"`
+	CustomerAccountID *uuid.UUID      `bun:"customer_account_id"`
+	Actor             string          `bun:"actor"`
+	ClientIP          *string         `bun:"client_ip"`
+	UserAgent         *string         `bun:"user_agent"`
+	RequestID         *string         `bun:"request_id"`
+	Method            *string         `bun:"method"`
+	Route             *string         `bun:"route"`
+	Before            json.RawMessage `bun:"before,type:jsonb"`
+	After             json.RawMessage `bun:"after,type:jsonb"`
+	CreatedAt         time.Time       `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*AuditLog)(nil)
+
+func (a *AuditLog) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		a.ID = &genID
+		a.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ type Balance struct {
 	ID                *uuid.UUID `bun:"id,pk"`
 	CustomerAccountID *uuid.UUID `bun:"customer_account_id"`
 	Balance           int64      `bun:"balance"`
+	Version           int64      `bun:"version"`
 	CreatedAt         time.Time  `bun:"created_at"`
 	UpdatedAt         time.Time  `bun:"updated_at"`
 }
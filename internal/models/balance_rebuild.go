@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+// BalanceRebuild is an applied rebuild of the balances from the transactions,
+// recording who ran it and why.
+type BalanceRebuild struct {
+	bun.BaseModel    `bun:"table:balance_rebuild"`
+	ID               *uuid.UUID `bun:"id,pk"`
+	Operator         string     `bun:"operator"`
+	Reason           string     `bun:"reason"`
+	AccountsChecked  int        `bun:"accounts_checked"`
+	AdjustmentsCount int        `bun:"adjustments_count"`
+	SnapshotAt       time.Time  `bun:"snapshot_at"`
+	FinishedAt       *time.Time `bun:"finished_at"`
+	CreatedAt        time.Time  `bun:"created_at"`
+	UpdatedAt        time.Time  `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*BalanceRebuild)(nil)
+
+func (b *BalanceRebuild) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		b.ID = &genID
+		b.CreatedAt = time.Now()
+		b.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		b.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// BalanceAdjustment is a correction of the balance of an account made by a
+// rebuild. PreviousBalance is nil when the account had no balance row.
+type BalanceAdjustment struct {
+	bun.BaseModel     `bun:"table:balance_adjustment"`
+	ID                *uuid.UUID `bun:"id,pk"`
+	BalanceRebuildID  *uuid.UUID `bun:"balance_rebuild_id"`
+	CustomerAccountID *uuid.UUID `bun:"customer_account_id"`
+	PreviousBalance   *int64     `bun:"previous_balance"`
+	RebuiltBalance    int64      `bun:"rebuilt_balance"`
+	Adjustment        int64      `bun:"adjustment"`
+	CreatedAt         time.Time  `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*BalanceAdjustment)(nil)
+
+func (b *BalanceAdjustment) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		b.ID = &genID
+		b.CreatedAt = time.Now()
+	}
+	return nil
+}
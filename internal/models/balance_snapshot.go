@@ -0,0 +1,35 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+// BalanceSnapshot holds the balance of an account considering every
+// transaction created before SnapshotAt.
+type BalanceSnapshot struct {
+	bun.BaseModel     `bun:"table:balance_snapshot"`
+	ID                *uuid.UUID `bun:"id,pk"`
+	CustomerAccountID *uuid.UUID `bun:"customer_account_id"`
+	Balance           int64      `bun:"balance"`
+	SnapshotAt        time.Time  `bun:"snapshot_at"`
+	CreatedAt         time.Time  `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*BalanceSnapshot)(nil)
+
+func (b *BalanceSnapshot) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		b.ID = &genID
+		b.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ type CustomerAccount struct {
 	bun.BaseModel `bun:"table:customer_account"`
 	ID            *uuid.UUID `bun:"id,pk"`
 	CustomerID    *uuid.UUID `bun:"customer_id"`
+	BlockedAt     *time.Time `bun:"blocked_at"`
 	CreatedAt     time.Time  `bun:"created_at"`
 	UpdatedAt     time.Time  `bun:"updated_at"`
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+// IdempotencyRecord claims an idempotency key of an account. TransactionID is
+// set in the same database transaction that claims the key, so a committed
+// record always points to the transaction created for it.
+type IdempotencyRecord struct {
+	bun.BaseModel      `bun:"table:idempotency_record"`
+	CustomerAccountID  *uuid.UUID `bun:"customer_account_id,pk"`
+	IdempotencyKey     string     `bun:"idempotency_key,pk"`
+	RequestFingerprint string     `bun:"request_fingerprint"`
+	TransactionID      *uuid.UUID `bun:"transaction_id"`
+	CreatedAt          time.Time  `bun:"created_at"`
+	UpdatedAt          time.Time  `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*IdempotencyRecord)(nil)
+
+func (i *IdempotencyRecord) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		i.CreatedAt = time.Now()
+		i.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		i.UpdatedAt = time.Now()
+	}
+	return nil
+}
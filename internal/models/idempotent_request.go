@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+// IdempotentRequest holds the response of a request sent with an
+// Idempotency-Key header, so a retry gets it replayed. While the request is
+// being processed StatusCode is nil, and the request identified by ClaimID
+// holds the key as long as it keeps renewing UpdatedAt.
+type IdempotentRequest struct {
+	bun.BaseModel       `bun:"table:idempotent_request"`
+	Method              string     `bun:"method,pk"`
+	Path                string     `bun:"path,pk"`
+	IdempotencyKey      string     `bun:"idempotency_key,pk"`
+	RequestFingerprint  string     `bun:"request_fingerprint"`
+	ClaimID             *uuid.UUID `bun:"claim_id"`
+	StatusCode          *int       `bun:"status_code"`
+	ResponseContentType *string    `bun:"response_content_type"`
+	ResponseBody        []byte     `bun:"response_body"`
+	CreatedAt           time.Time  `bun:"created_at"`
+	UpdatedAt           time.Time  `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*IdempotentRequest)(nil)
+
+func (i *IdempotentRequest) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		i.CreatedAt = time.Now()
+		i.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		i.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// InProgress reports whether the request that claimed the key hasn't finished.
+func (i *IdempotentRequest) InProgress() bool {
+	return i.StatusCode == nil
+}
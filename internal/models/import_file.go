@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type ImportFormat string
+
+const (
+	CSVImportFormat ImportFormat = "csv"
+	OFXImportFormat ImportFormat = "ofx"
+)
+
+type ImportRowStatus string
+
+const (
+	ImportRowValid    ImportRowStatus = "valid"
+	ImportRowInvalid  ImportRowStatus = "invalid"
+	ImportRowCreated  ImportRowStatus = "created"
+	ImportRowReplayed ImportRowStatus = "replayed"
+	ImportRowFailed   ImportRowStatus = "failed"
+)
+
+// ImportFile is a file of transactions that was posted, with the outcome of
+// each of its rows.
+type ImportFile struct {
+	bun.BaseModel `bun:"table:import_file"`
+	ID            *uuid.UUID      `bun:"id,pk"`
+	FileHash      string          `bun:"file_hash"`
+	FileName      string          `bun:"file_name"`
+	Format        ImportFormat    `bun:"format"`
+	TotalRows     int             `bun:"total_rows"`
+	ImportedRows  int             `bun:"imported_rows"`
+	FailedRows    int             `bun:"failed_rows"`
+	Rows          []ImportFileRow `bun:"rows,type:jsonb"`
+	CreatedAt     time.Time       `bun:"created_at"`
+	UpdatedAt     time.Time       `bun:"updated_at"`
+}
+
+type ImportFileRow struct {
+	Row               int             `json:"row"`
+	CustomerAccountID *uuid.UUID      `json:"account_id,omitempty"`
+	OperationType     OperationType   `json:"operation_type,omitempty"`
+	Amount            int64           `json:"amount"`
+	Status            ImportRowStatus `json:"status"`
+	TransactionID     *uuid.UUID      `json:"transaction_id,omitempty"`
+	Errors            []string        `json:"errors,omitempty"`
+}
+
+var _ bun.BeforeAppendModelHook = (*ImportFile)(nil)
+
+func (i *ImportFile) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		i.ID = &genID
+		i.CreatedAt = time.Now()
+		i.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		i.UpdatedAt = time.Now()
+	}
+	return nil
+}
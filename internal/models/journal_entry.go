@@ -0,0 +1,58 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type JournalEntry struct {
+	bun.BaseModel `bun:"table:journal_entry"`
+	ID            *uuid.UUID `bun:"id,pk"`
+	TransactionID *uuid.UUID `bun:"transaction_id"`
+	CreatedAt     time.Time  `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*JournalEntry)(nil)
+
+func (j *JournalEntry) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		j.ID = &genID
+		j.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// JournalPosting is one leg of a JournalEntry. Exactly one of CustomerAccountID
+// or LedgerAccountID is set, and the postings of an entry always sum to zero.
+type JournalPosting struct {
+	bun.BaseModel     `bun:"table:journal_posting"`
+	ID                *uuid.UUID `bun:"id,pk"`
+	JournalEntryID    *uuid.UUID `bun:"journal_entry_id"`
+	CustomerAccountID *uuid.UUID `bun:"customer_account_id"`
+	LedgerAccountID   *uuid.UUID `bun:"ledger_account_id"`
+	Amount            int64      `bun:"amount"`
+	CreatedAt         time.Time  `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*JournalPosting)(nil)
+
+func (j *JournalPosting) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		j.ID = &genID
+		j.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type LedgerAccountCode string
+
+const (
+	SettlementLedgerAccount     LedgerAccountCode = "settlement"
+	FeesRevenueLedgerAccount    LedgerAccountCode = "fees_revenue"
+	VoucherFundingLedgerAccount LedgerAccountCode = "voucher_funding"
+)
+
+type LedgerAccount struct {
+	bun.BaseModel `bun:"table:ledger_account"`
+	ID            *uuid.UUID        `bun:"id,pk"`
+	Code          LedgerAccountCode `bun:"code"`
+	Description   string            `bun:"description"`
+	CreatedAt     time.Time         `bun:"created_at"`
+	UpdatedAt     time.Time         `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*LedgerAccount)(nil)
+
+func (l *LedgerAccount) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		l.ID = &genID
+		l.CreatedAt = time.Now()
+		l.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		l.UpdatedAt = time.Now()
+	}
+	return nil
+}
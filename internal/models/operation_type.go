@@ -0,0 +1,51 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type OperationDirection string
+
+const (
+	CreditDirection OperationDirection = "credit"
+	DebitDirection  OperationDirection = "debit"
+)
+
+// OperationTypeDefinition describes how an operation type moves money. New
+// types are added as rows of operation_types, without code changes.
+type OperationTypeDefinition struct {
+	bun.BaseModel             `bun:"table:operation_types"`
+	ID                        *uuid.UUID         `bun:"id,pk"`
+	Code                      OperationType      `bun:"code"`
+	Direction                 OperationDirection `bun:"direction"`
+	Description               string             `bun:"description"`
+	Enabled                   bool               `bun:"enabled"`
+	MinAmount                 *int64             `bun:"min_amount"`
+	MaxAmount                 *int64             `bun:"max_amount"`
+	CounterpartyLedgerAccount LedgerAccountCode  `bun:"counterparty_ledger_account"`
+	CreatedAt                 time.Time          `bun:"created_at"`
+	UpdatedAt                 time.Time          `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*OperationTypeDefinition)(nil)
+
+func (o *OperationTypeDefinition) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		o.ID = &genID
+		o.CreatedAt = time.Now()
+		o.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		o.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type OutboxEventStatus string
+
+const (
+	OutboxEventPending    OutboxEventStatus = "pending"
+	OutboxEventDispatched OutboxEventStatus = "dispatched"
+	// OutboxEventDeadLetter is an event that kept failing to be published and
+	// was given up on, so the events after it could go on.
+	OutboxEventDeadLetter OutboxEventStatus = "dead_letter"
+)
+
+type OutboxEvent struct {
+	bun.BaseModel     `bun:"table:outbox_event"`
+	ID                int64             `bun:"id,pk,autoincrement"`
+	EventID           *uuid.UUID        `bun:"event_id"`
+	EventType         string            `bun:"event_type"`
+	SchemaVersion     int               `bun:"schema_version"`
+	CustomerAccountID *uuid.UUID        `bun:"customer_account_id"`
+	Payload           json.RawMessage   `bun:"payload,type:jsonb"`
+	Status            OutboxEventStatus `bun:"status"`
+	Attempts          int               `bun:"attempts"`
+	LastError         *string           `bun:"last_error"`
+	NextAttemptAt     time.Time         `bun:"next_attempt_at"`
+	DispatchedAt      *time.Time        `bun:"dispatched_at"`
+	CreatedAt         time.Time         `bun:"created_at"`
+	UpdatedAt         time.Time         `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*OutboxEvent)(nil)
+
+func (o *OutboxEvent) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		if o.EventID == nil {
+			genID, err := uuid.NewV6()
+			if err != nil {
+				return err
+			}
+
+			o.EventID = &genID
+		}
+
+		if o.Status == "" {
+			o.Status = OutboxEventPending
+		}
+
+		o.NextAttemptAt = time.Now()
+		o.CreatedAt = time.Now()
+		o.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		o.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type ReconciliationDriftStatus string
+
+const (
+	ReconciliationDriftOpen     ReconciliationDriftStatus = "open"
+	ReconciliationDriftResolved ReconciliationDriftStatus = "resolved"
+)
+
+type ReconciliationRun struct {
+	bun.BaseModel   `bun:"table:reconciliation_run"`
+	ID              *uuid.UUID `bun:"id,pk"`
+	AccountsChecked int        `bun:"accounts_checked"`
+	DriftsFound     int        `bun:"drifts_found"`
+	BlockAccounts   bool       `bun:"block_accounts"`
+	StartedAt       time.Time  `bun:"started_at"`
+	FinishedAt      *time.Time `bun:"finished_at"`
+	CreatedAt       time.Time  `bun:"created_at"`
+	UpdatedAt       time.Time  `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*ReconciliationRun)(nil)
+
+func (r *ReconciliationRun) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		r.ID = &genID
+		r.StartedAt = time.Now()
+		r.CreatedAt = time.Now()
+		r.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		r.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// ReconciliationDrift is an account whose stored balance doesn't match the
+// sum of its transactions.
+type ReconciliationDrift struct {
+	bun.BaseModel       `bun:"table:reconciliation_drift"`
+	ID                  *uuid.UUID                `bun:"id,pk"`
+	ReconciliationRunID *uuid.UUID                `bun:"reconciliation_run_id"`
+	CustomerAccountID   *uuid.UUID                `bun:"customer_account_id"`
+	Balance             int64                     `bun:"balance"`
+	TransactionsTotal   int64                     `bun:"transactions_total"`
+	Drift               int64                     `bun:"drift"`
+	Status              ReconciliationDriftStatus `bun:"status"`
+	AccountBlocked      bool                      `bun:"account_blocked"`
+	ResolvedAt          *time.Time                `bun:"resolved_at"`
+	CreatedAt           time.Time                 `bun:"created_at"`
+	UpdatedAt           time.Time                 `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*ReconciliationDrift)(nil)
+
+func (r *ReconciliationDrift) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		r.ID = &genID
+		r.CreatedAt = time.Now()
+		r.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		r.UpdatedAt = time.Now()
+	}
+	return nil
+}
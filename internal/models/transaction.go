@@ -23,6 +23,7 @@ type Transaction struct {
 	CustomerAccountID *uuid.UUID    `bun:"customer_account_id"`
 	OperationType     OperationType `bun:"operation_type"`
 	Amount            int64         `bun:"amount"`
+	BalanceAfter      *int64        `bun:"balance_after"`
 	IdempotencyKey    *string       `bun:"idempotency_key"`
 	CreatedAt         time.Time     `bun:"created_at"`
 	UpdatedAt         time.Time     `bun:"updated_at"`
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type TransactionScheduleStatus string
+
+const (
+	TransactionScheduleActive    TransactionScheduleStatus = "active"
+	TransactionSchedulePaused    TransactionScheduleStatus = "paused"
+	TransactionScheduleCancelled TransactionScheduleStatus = "cancelled"
+	// TransactionScheduleCompleted is a schedule whose every occurrence was
+	// already run.
+	TransactionScheduleCompleted TransactionScheduleStatus = "completed"
+)
+
+// InsufficientFundsPolicy is what to do with an occurrence of a schedule when
+// the account can't afford it.
+type InsufficientFundsPolicy string
+
+const (
+	// InsufficientFundsRetry tries the occurrence again later, until it runs
+	// out of attempts.
+	InsufficientFundsRetry InsufficientFundsPolicy = "retry"
+	// InsufficientFundsSkip gives up on the occurrence right away.
+	InsufficientFundsSkip InsufficientFundsPolicy = "skip"
+)
+
+type TransactionScheduleRunStatus string
+
+const (
+	TransactionScheduleRunCreated TransactionScheduleRunStatus = "created"
+	TransactionScheduleRunSkipped TransactionScheduleRunStatus = "skipped"
+	TransactionScheduleRunFailed  TransactionScheduleRunStatus = "failed"
+)
+
+type TransactionSchedule struct {
+	bun.BaseModel           `bun:"table:transaction_schedule"`
+	ID                      *uuid.UUID                `bun:"id,pk"`
+	CustomerAccountID       *uuid.UUID                `bun:"customer_account_id"`
+	OperationType           OperationType             `bun:"operation_type"`
+	Amount                  int64                     `bun:"amount"`
+	StartAt                 time.Time                 `bun:"start_at"`
+	RRule                   *string                   `bun:"rrule"`
+	InsufficientFundsPolicy InsufficientFundsPolicy   `bun:"insufficient_funds_policy"`
+	Status                  TransactionScheduleStatus `bun:"status"`
+	NextOccurrence          int                       `bun:"next_occurrence"`
+	NextRunAt               *time.Time                `bun:"next_run_at"`
+	Attempts                int                       `bun:"attempts"`
+	LastError               *string                   `bun:"last_error"`
+	CreatedAt               time.Time                 `bun:"created_at"`
+	UpdatedAt               time.Time                 `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*TransactionSchedule)(nil)
+
+func (t *TransactionSchedule) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		t.ID = &genID
+		t.CreatedAt = time.Now()
+		t.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		t.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+type TransactionScheduleRun struct {
+	bun.BaseModel         `bun:"table:transaction_schedule_run"`
+	ID                    *uuid.UUID                   `bun:"id,pk"`
+	TransactionScheduleID *uuid.UUID                   `bun:"transaction_schedule_id"`
+	Occurrence            int                          `bun:"occurrence"`
+	ScheduledFor          time.Time                    `bun:"scheduled_for"`
+	Status                TransactionScheduleRunStatus `bun:"status"`
+	TransactionID         *uuid.UUID                   `bun:"transaction_id"`
+	Attempts              int                          `bun:"attempts"`
+	Error                 *string                      `bun:"error"`
+	CreatedAt             time.Time                    `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*TransactionScheduleRun)(nil)
+
+func (t *TransactionScheduleRun) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		t.ID = &genID
+		t.CreatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed is a delivery that was given up on after every
+	// attempt failed. It can still be redelivered by hand.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+type WebhookSubscription struct {
+	bun.BaseModel `bun:"table:webhook_subscription"`
+	ID            *uuid.UUID `bun:"id,pk"`
+	URL           string     `bun:"url"`
+	EventTypes    []string   `bun:"event_types,array"`
+	Secret        string     `bun:"secret"`
+	Enabled       bool       `bun:"enabled"`
+	CreatedAt     time.Time  `bun:"created_at"`
+	UpdatedAt     time.Time  `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*WebhookSubscription)(nil)
+
+func (w *WebhookSubscription) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		w.ID = &genID
+		w.CreatedAt = time.Now()
+		w.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		w.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	bun.BaseModel         `bun:"table:webhook_delivery"`
+	ID                    *uuid.UUID            `bun:"id,pk"`
+	WebhookSubscriptionID *uuid.UUID            `bun:"webhook_subscription_id"`
+	EventID               *uuid.UUID            `bun:"event_id"`
+	EventType             string                `bun:"event_type"`
+	Payload               json.RawMessage       `bun:"payload,type:jsonb"`
+	Status                WebhookDeliveryStatus `bun:"status"`
+	Attempts              int                   `bun:"attempts"`
+	NextAttemptAt         time.Time             `bun:"next_attempt_at"`
+	LastAttemptAt         *time.Time            `bun:"last_attempt_at"`
+	ResponseStatus        *int                  `bun:"response_status"`
+	LastError             *string               `bun:"last_error"`
+	CreatedAt             time.Time             `bun:"created_at"`
+	UpdatedAt             time.Time             `bun:"updated_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*WebhookDelivery)(nil)
+
+func (w *WebhookDelivery) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		w.ID = &genID
+		w.Status = WebhookDeliveryPending
+		w.NextAttemptAt = time.Now()
+		w.CreatedAt = time.Now()
+		w.UpdatedAt = time.Now()
+	case *bun.UpdateQuery:
+		w.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+type WebhookDeliveryAttempt struct {
+	bun.BaseModel     `bun:"table:webhook_delivery_attempt"`
+	ID                *uuid.UUID `bun:"id,pk"`
+	WebhookDeliveryID *uuid.UUID `bun:"webhook_delivery_id"`
+	Attempt           int        `bun:"attempt"`
+	ResponseStatus    *int       `bun:"response_status"`
+	ResponseBody      *string    `bun:"response_body"`
+	Error             *string    `bun:"error"`
+	DurationMs        int64      `bun:"duration_ms"`
+	CreatedAt         time.Time  `bun:"created_at"`
+}
+
+var _ bun.BeforeAppendModelHook = (*WebhookDeliveryAttempt)(nil)
+
+func (w *WebhookDeliveryAttempt) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if _, ok := query.(*bun.InsertQuery); ok {
+		genID, err := uuid.NewV6()
+		if err != nil {
+			return err
+		}
+
+		w.ID = &genID
+		w.CreatedAt = time.Now()
+	}
+	return nil
+}
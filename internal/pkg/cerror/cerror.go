@@ -9,10 +9,14 @@ type (
 	Params struct {
 		Status  int
 		Message string
+		// Code identifies the error for clients and callers, which shouldn't
+		// depend on the wording of Message.
+		Code string
 	}
 
 	Error struct {
 		Status      int          `json:"status"`
+		Code        string       `json:"code,omitempty"`
 		Message     string       `json:"message"`
 		FieldErrors []FieldError `json:"field_errors,omitempty"`
 	}
@@ -21,6 +25,7 @@ type (
 func New(params Params, validationErrors ...FieldError) *Error {
 	return &Error{
 		Status:      params.Status,
+		Code:        params.Code,
 		Message:     params.Message,
 		FieldErrors: validationErrors,
 	}
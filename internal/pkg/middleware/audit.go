@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/audit"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	// ActorHeader identifies who is making the request, as told by the
+	// gateway or the client in front of the API.
+	ActorHeader = "X-Actor"
+
+	anonymousActor = "anonymous"
+)
+
+type auditTrail struct {
+	auditLogRepository repository.AuditLogRepository
+}
+
+// Audit tags every request with an X-Request-Id, taken from the request or
+// generated, and keeps who made each mutating request and from where in its
+// context, for the services to record in the audit log with the entities they
+// change. Successful mutating requests that no service recorded an entry for
+// (and that weren't replayed) get an entry for the route itself.
+func Audit(auditLogRepository repository.AuditLogRepository) fiber.Handler {
+	middleware := &auditTrail{
+		auditLogRepository: auditLogRepository,
+	}
+
+	return middleware.handle
+}
+
+func (m *auditTrail) handle(c *fiber.Ctx) error {
+	requestID := utils.CopyString(c.Get(fiber.HeaderXRequestID))
+	if requestID == "" {
+		generatedID, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+
+		requestID = generatedID.String()
+	}
+
+	c.Set(fiber.HeaderXRequestID, requestID)
+
+	if !isMutating(c.Method()) {
+		return c.Next()
+	}
+
+	actor := utils.CopyString(c.Get(ActorHeader))
+	if actor == "" {
+		actor = anonymousActor
+	}
+
+	request := &audit.Request{
+		Actor:     actor,
+		ClientIP:  utils.CopyString(c.IP()),
+		UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+		RequestID: requestID,
+		Method:    utils.CopyString(c.Method()),
+		Route:     utils.CopyString(c.Path()),
+	}
+
+	c.Context().SetUserValue(audit.RequestKey, request)
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+
+	if c.Response().StatusCode() >= http.StatusBadRequest ||
+		c.GetRespHeader(IdempotentReplayedHeader) != "" ||
+		request.Recorded() {
+		return nil
+	}
+
+	entry, err := audit.NewEntry(c.Context(), audit.Change{Action: audit.ActionRequest})
+	if err == nil {
+		_, err = m.auditLogRepository.CreateAuditLog(c.Context(), entry)
+	}
+
+	if err != nil {
+		log.Err(err).
+			Str("route", request.Route).
+			Str("request_id", requestID).
+			Msg("failed to record audit log")
+	}
+
+	return nil
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
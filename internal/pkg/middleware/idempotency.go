@@ -0,0 +1,331 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/gofrs/uuid/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	defaultIdempotencyLease = 30 * time.Second
+)
+
+// IdempotencyOptions tunes the Idempotency middleware. Zero values fall back to
+// the defaults.
+type IdempotencyOptions struct {
+	// Lease is how long a claim is held without being renewed. The running
+	// request renews it every third of the lease, so only the claim of a
+	// request whose instance is gone (e.g. it crashed) is taken over by a
+	// retry, however long the request takes.
+	Lease time.Duration
+}
+
+type idempotency struct {
+	idempotentRequestRepository repository.IdempotentRequestRepository
+	lease                       time.Duration
+}
+
+// Idempotency replays the stored response of POST and PATCH requests sent
+// again with the same Idempotency-Key header. Keys are scoped by method and
+// path; reusing one with a different body is rejected with 422, and a retry
+// arriving while the first request is still running gets 409. Responses with a
+// 5xx status (and 409, which may be transient) aren't stored, so the request
+// can be retried. Multipart bodies are compared by their fields and file
+// contents, since their boundary changes on every retry.
+func Idempotency(
+	idempotentRequestRepository repository.IdempotentRequestRepository,
+	options IdempotencyOptions,
+) fiber.Handler {
+	if options.Lease <= 0 {
+		options.Lease = defaultIdempotencyLease
+	}
+
+	middleware := &idempotency{
+		idempotentRequestRepository: idempotentRequestRepository,
+		lease:                       options.Lease,
+	}
+
+	return middleware.handle
+}
+
+func (m *idempotency) handle(c *fiber.Ctx) error {
+	if c.Method() != fiber.MethodPost && c.Method() != fiber.MethodPatch {
+		return c.Next()
+	}
+
+	idempotencyKey := utils.CopyString(c.Get(IdempotencyKeyHeader))
+	if idempotencyKey == "" {
+		return c.Next()
+	}
+
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusBadRequest,
+			Message: "Idempotency key is too long",
+		})
+	}
+
+	ctx := c.Context()
+
+	claimID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	request := models.IdempotentRequest{
+		Method:             c.Method(),
+		Path:               utils.CopyString(c.Path()),
+		IdempotencyKey:     idempotencyKey,
+		RequestFingerprint: requestFingerprint(c),
+		ClaimID:            &claimID,
+	}
+
+	claimed, err := m.idempotentRequestRepository.ClaimIdempotentRequest(ctx, request, m.lease)
+	if err != nil {
+		log.Err(err).
+			Str("path", request.Path).
+			Str("idempotency_key", idempotencyKey).
+			Msg("failed to claim idempotent request")
+
+		return err
+	}
+
+	if !claimed {
+		return m.replay(c, request)
+	}
+
+	stopRenewing := m.renew(request)
+
+	err = c.Next()
+	if err != nil {
+		err = c.App().ErrorHandler(c, err)
+	}
+
+	stopRenewing()
+
+	if err != nil {
+		m.release(ctx, request)
+
+		return err
+	}
+
+	statusCode := c.Response().StatusCode()
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusConflict {
+		m.release(ctx, request)
+
+		return nil
+	}
+
+	contentType := string(c.Response().Header.ContentType())
+
+	request.StatusCode = &statusCode
+	request.ResponseContentType = &contentType
+	request.ResponseBody = utils.CopyBytes(c.Response().Body())
+
+	if err := m.idempotentRequestRepository.CompleteIdempotentRequest(ctx, request); err != nil {
+		log.Err(err).
+			Str("path", request.Path).
+			Str("idempotency_key", idempotencyKey).
+			Msg("failed to store idempotent response")
+
+		m.release(ctx, request)
+	}
+
+	return nil
+}
+
+func (m *idempotency) replay(c *fiber.Ctx, request models.IdempotentRequest) error {
+	stored, err := m.idempotentRequestRepository.GetIdempotentRequest(
+		c.Context(), request.Method, request.Path, request.IdempotencyKey,
+	)
+	if err != nil {
+		log.Err(err).
+			Str("path", request.Path).
+			Str("idempotency_key", request.IdempotencyKey).
+			Msg("failed to get idempotent request")
+
+		return err
+	}
+
+	if stored == nil {
+		return errIdempotentRequestInProgress()
+	}
+
+	if stored.RequestFingerprint != request.RequestFingerprint {
+		return cerror.New(cerror.Params{
+			Status:  http.StatusUnprocessableEntity,
+			Message: "Idempotency key was already used with a different payload",
+		})
+	}
+
+	if stored.InProgress() {
+		return errIdempotentRequestInProgress()
+	}
+
+	if stored.ResponseContentType != nil && *stored.ResponseContentType != "" {
+		c.Set(fiber.HeaderContentType, *stored.ResponseContentType)
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+
+	return c.Status(*stored.StatusCode).Send(stored.ResponseBody)
+}
+
+// renew keeps renewing the lease of the claim in the background until the
+// returned function is called.
+func (m *idempotency) renew(request models.IdempotentRequest) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(m.lease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			renewed, err := m.idempotentRequestRepository.RenewIdempotentRequest(ctx, request)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Err(err).
+						Str("path", request.Path).
+						Str("idempotency_key", request.IdempotencyKey).
+						Msg("failed to renew idempotent request")
+				}
+
+				continue
+			}
+
+			if !renewed {
+				log.Warn().
+					Str("path", request.Path).
+					Str("idempotency_key", request.IdempotencyKey).
+					Msg("idempotent request lost its claim")
+
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// release drops the claim so the request can be retried with the same key.
+func (m *idempotency) release(ctx context.Context, request models.IdempotentRequest) {
+	if err := m.idempotentRequestRepository.ReleaseIdempotentRequest(ctx, request); err != nil {
+		log.Err(err).
+			Str("path", request.Path).
+			Str("idempotency_key", request.IdempotencyKey).
+			Msg("failed to release idempotent request")
+	}
+}
+
+func errIdempotentRequestInProgress() error {
+	return cerror.New(cerror.Params{
+		Status:  http.StatusConflict,
+		Message: "A request with that idempotency key is in progress",
+	})
+}
+
+// requestFingerprint hashes the body of the request or, for a multipart form,
+// its fields and the contents of its files.
+func requestFingerprint(c *fiber.Ctx) string {
+	contentType := string(c.Request().Header.ContentType())
+	if !strings.HasPrefix(contentType, fiber.MIMEMultipartForm) {
+		return bodyFingerprint(c.Body())
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return bodyFingerprint(c.Body())
+	}
+
+	fingerprint, err := formFingerprint(form)
+	if err != nil {
+		return bodyFingerprint(c.Body())
+	}
+
+	return fingerprint
+}
+
+func bodyFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// formFingerprint hashes the fields and files of the form in name order, each
+// one prefixed by its name and length so no two forms hash alike.
+func formFingerprint(form *multipart.Form) (string, error) {
+	digest := sha256.New()
+
+	for _, name := range slices.Sorted(maps.Keys(form.Value)) {
+		for _, value := range form.Value[name] {
+			writeFormPart(digest, "field", name, []byte(value))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(form.File)) {
+		for _, fileHeader := range form.File[name] {
+			contentHash, err := fileFingerprint(fileHeader)
+			if err != nil {
+				return "", err
+			}
+
+			writeFormPart(digest, "file", name, contentHash)
+		}
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+func fileFingerprint(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return nil, err
+	}
+
+	return digest.Sum(nil), nil
+}
+
+func writeFormPart(h hash.Hash, kind string, name string, content []byte) {
+	h.Write(fmt.Appendf(nil, "%s %q %d\n", kind, name, len(content)))
+	h.Write(content)
+}
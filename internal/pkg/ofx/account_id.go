@@ -0,0 +1,40 @@
+package ofx
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxAccountIDLength is the longest ACCTID the OFX specification allows.
+const MaxAccountIDLength = 22
+
+// accountIDBase writes account ids with digits and both cases of letters.
+const accountIDBase = big.MaxBase
+
+// EncodeAccountID writes a UUID in base 62, zero padded to 22 characters, so
+// it fits in ACCTID, which is too short for its usual 36 character form.
+func EncodeAccountID(id uuid.UUID) string {
+	text := new(big.Int).SetBytes(id.Bytes()).Text(accountIDBase)
+
+	return strings.Repeat("0", MaxAccountIDLength-len(text)) + text
+}
+
+// DecodeAccountID reads back an ACCTID written by EncodeAccountID, reporting
+// false when it isn't one.
+func DecodeAccountID(accountID string) (uuid.UUID, bool) {
+	if len(accountID) != MaxAccountIDLength {
+		return uuid.Nil, false
+	}
+
+	value, ok := new(big.Int).SetString(accountID, accountIDBase)
+	if !ok || value.Sign() < 0 || value.BitLen() > 8*uuid.Size {
+		return uuid.Nil, false
+	}
+
+	var id uuid.UUID
+	value.FillBytes(id[:])
+
+	return id, true
+}
@@ -0,0 +1,259 @@
+package ofx
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Statement is a bank or credit card statement (STMTRS or CCSTMTRS) of an OFX
+// file. StartAt and EndAt are the range of the transaction list.
+type Statement struct {
+	BankID        string
+	AccountID     string
+	Currency      string
+	StartAt       *time.Time
+	EndAt         *time.Time
+	Transactions  []StatementTransaction
+	LedgerBalance *Balance
+}
+
+// Balance is the LEDGERBAL aggregate of a statement. Amount keeps the text of
+// BALAMT, like the amount of transactions.
+type Balance struct {
+	Amount string
+	AsOf   *time.Time
+}
+
+// StatementTransaction is a STMTTRN aggregate. Amount keeps the text of TRNAMT,
+// signed, so callers can parse it with the precision they need.
+type StatementTransaction struct {
+	Type     string
+	PostedAt *time.Time
+	Amount   string
+	FITID    string
+	Name     string
+	Memo     string
+}
+
+var ErrNoStatements = errors.New("ofx: no statement found")
+
+// Parse reads the statements of an OFX file. Both OFX 1.x (SGML, where
+// elements have no closing tag) and OFX 2.x (XML) are accepted.
+func Parse(r io.Reader) ([]Statement, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		statements  []Statement
+		statement   *Statement
+		transaction *StatementTransaction
+		balance     *Balance
+	)
+
+	for _, token := range tokenize(string(content)) {
+		switch {
+		case token.tag == "STMTRS" || token.tag == "CCSTMTRS":
+			statement = &Statement{}
+		case token.tag == "/STMTRS" || token.tag == "/CCSTMTRS":
+			if statement != nil {
+				statements = append(statements, *statement)
+			}
+
+			statement = nil
+		case statement == nil:
+			continue
+		case token.tag == "STMTTRN":
+			transaction = &StatementTransaction{}
+		case token.tag == "/STMTTRN":
+			if transaction != nil {
+				statement.Transactions = append(statement.Transactions, *transaction)
+			}
+
+			transaction = nil
+		case transaction != nil:
+			if err := transaction.set(token.tag, token.value); err != nil {
+				return nil, err
+			}
+		case token.tag == "LEDGERBAL":
+			balance = &Balance{}
+		case token.tag == "/LEDGERBAL":
+			statement.LedgerBalance = balance
+			balance = nil
+		case balance != nil:
+			if err := balance.set(token.tag, token.value); err != nil {
+				return nil, err
+			}
+		case token.tag == "DTSTART" || token.tag == "DTEND":
+			at, err := ParseDateTime(token.value)
+			if err != nil {
+				return nil, err
+			}
+
+			if token.tag == "DTSTART" {
+				statement.StartAt = &at
+			} else {
+				statement.EndAt = &at
+			}
+		case token.tag == "BANKID":
+			statement.BankID = token.value
+		case token.tag == "ACCTID":
+			statement.AccountID = token.value
+		case token.tag == "CURDEF":
+			statement.Currency = token.value
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil, ErrNoStatements
+	}
+
+	return statements, nil
+}
+
+func (t *StatementTransaction) set(tag string, value string) error {
+	switch tag {
+	case "TRNTYPE":
+		t.Type = value
+	case "DTPOSTED":
+		postedAt, err := ParseDateTime(value)
+		if err != nil {
+			return err
+		}
+
+		t.PostedAt = &postedAt
+	case "TRNAMT":
+		t.Amount = strings.ReplaceAll(value, ",", ".")
+	case "FITID":
+		t.FITID = value
+	case "NAME":
+		t.Name = value
+	case "MEMO":
+		t.Memo = value
+	}
+
+	return nil
+}
+
+func (b *Balance) set(tag string, value string) error {
+	switch tag {
+	case "BALAMT":
+		b.Amount = strings.ReplaceAll(value, ",", ".")
+	case "DTASOF":
+		asOf, err := ParseDateTime(value)
+		if err != nil {
+			return err
+		}
+
+		b.AsOf = &asOf
+	}
+
+	return nil
+}
+
+type token struct {
+	tag   string
+	value string
+}
+
+// tokenize splits the body of the file into tags and the text that follows
+// each of them, skipping the header and processing instructions.
+func tokenize(content string) []token {
+	var tokens []token
+
+	for {
+		start := strings.IndexByte(content, '<')
+		if start < 0 {
+			return tokens
+		}
+
+		end := strings.IndexByte(content[start:], '>')
+		if end < 0 {
+			return tokens
+		}
+
+		tag := strings.ToUpper(strings.TrimSpace(content[start+1 : start+end]))
+		content = content[start+end+1:]
+
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+
+		value := content
+		if next := strings.IndexByte(content, '<'); next >= 0 {
+			value = content[:next]
+		}
+
+		tokens = append(tokens, token{tag: tag, value: unescape(strings.TrimSpace(value))})
+	}
+}
+
+var entities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&")
+
+func unescape(value string) string {
+	return entities.Replace(value)
+}
+
+// ParseDateTime parses an OFX date, YYYYMMDD optionally followed by HHMMSS,
+// milliseconds and a [offset:TZ] suffix. Dates without an offset are in UTC,
+// as the specification says.
+func ParseDateTime(value string) (time.Time, error) {
+	location := time.UTC
+
+	if open := strings.IndexByte(value, '['); open >= 0 {
+		offset := strings.TrimSuffix(value[open+1:], "]")
+		value = value[:open]
+
+		if colon := strings.IndexByte(offset, ':'); colon >= 0 {
+			offset = offset[:colon]
+		}
+
+		location = offsetLocation(offset)
+	}
+
+	if dot := strings.IndexByte(value, '.'); dot >= 0 {
+		value = value[:dot]
+	}
+
+	layouts := map[int]string{
+		len("20060102"):       "20060102",
+		len("200601021504"):   "200601021504",
+		len("20060102150405"): "20060102150405",
+	}
+
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, errors.New("ofx: invalid date " + value)
+	}
+
+	return time.ParseInLocation(layout, value, location)
+}
+
+func offsetLocation(offset string) *time.Location {
+	offsetTime, err := time.Parse("-07", formatOffset(offset))
+	if err != nil {
+		return time.UTC
+	}
+
+	_, seconds := offsetTime.Zone()
+
+	return time.FixedZone("", seconds)
+}
+
+// formatOffset turns the hour offset of an OFX date ("-3", "+5", "0") into
+// the "-07" layout.
+func formatOffset(offset string) string {
+	sign := "+"
+	if strings.HasPrefix(offset, "-") || strings.HasPrefix(offset, "+") {
+		sign, offset = offset[:1], offset[1:]
+	}
+
+	if len(offset) == 1 {
+		offset = "0" + offset
+	}
+
+	return sign + offset
+}
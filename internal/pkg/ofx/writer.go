@@ -0,0 +1,163 @@
+package ofx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// header is the XML declaration and OFX processing instruction of OFX 2.2
+// files.
+const header = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+	`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+type document struct {
+	XMLName    xml.Name            `xml:"OFX"`
+	SignOn     signOnResponse      `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statements []statementResponse `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type status struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+var statusOK = status{Code: 0, Severity: "INFO"}
+
+type signOnResponse struct {
+	Status     status `xml:"STATUS"`
+	ServerTime string `xml:"DTSERVER"`
+	Language   string `xml:"LANGUAGE"`
+}
+
+type statementResponse struct {
+	TransactionUID string        `xml:"TRNUID"`
+	Status         status        `xml:"STATUS"`
+	Statement      statementBody `xml:"STMTRS"`
+}
+
+type statementBody struct {
+	Currency        string            `xml:"CURDEF"`
+	Account         bankAccount       `xml:"BANKACCTFROM"`
+	TransactionList transactionList   `xml:"BANKTRANLIST"`
+	LedgerBalance   *balanceAggregate `xml:"LEDGERBAL"`
+}
+
+type bankAccount struct {
+	BankID      string `xml:"BANKID"`
+	AccountID   string `xml:"ACCTID"`
+	AccountType string `xml:"ACCTTYPE"`
+}
+
+type transactionList struct {
+	Start        string                 `xml:"DTSTART,omitempty"`
+	End          string                 `xml:"DTEND,omitempty"`
+	Transactions []transactionAggregate `xml:"STMTTRN"`
+}
+
+type transactionAggregate struct {
+	Type     string `xml:"TRNTYPE"`
+	PostedAt string `xml:"DTPOSTED,omitempty"`
+	Amount   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+type balanceAggregate struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF,omitempty"`
+}
+
+// Write writes the statements as an OFX 2.2 file of checking account
+// statements (STMTRS), dated serverTime. Amounts are written as they are, so
+// they must already use a dot as the decimal separator.
+func Write(w io.Writer, serverTime time.Time, statements ...Statement) error {
+	ofx := document{
+		SignOn: signOnResponse{
+			Status:     statusOK,
+			ServerTime: FormatDateTime(serverTime),
+			Language:   "ENG",
+		},
+	}
+
+	for _, statement := range statements {
+		ofx.Statements = append(ofx.Statements, newStatementResponse(statement))
+	}
+
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(ofx); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func newStatementResponse(statement Statement) statementResponse {
+	body := statementBody{
+		Currency: statement.Currency,
+		Account: bankAccount{
+			BankID:      statement.BankID,
+			AccountID:   statement.AccountID,
+			AccountType: "CHECKING",
+		},
+		TransactionList: transactionList{
+			Start: formatOptionalDateTime(statement.StartAt),
+			End:   formatOptionalDateTime(statement.EndAt),
+		},
+	}
+
+	for _, transaction := range statement.Transactions {
+		body.TransactionList.Transactions = append(body.TransactionList.Transactions, transactionAggregate{
+			Type:     transaction.Type,
+			PostedAt: formatOptionalDateTime(transaction.PostedAt),
+			Amount:   transaction.Amount,
+			FITID:    transaction.FITID,
+			Name:     transaction.Name,
+			Memo:     transaction.Memo,
+		})
+	}
+
+	if statement.LedgerBalance != nil {
+		body.LedgerBalance = &balanceAggregate{
+			Amount: statement.LedgerBalance.Amount,
+			AsOf:   formatOptionalDateTime(statement.LedgerBalance.AsOf),
+		}
+	}
+
+	return statementResponse{
+		TransactionUID: "0",
+		Status:         statusOK,
+		Statement:      body,
+	}
+}
+
+// FormatDateTime formats t as an OFX date with milliseconds and its offset,
+// e.g. 20260901103000.000[-3]. Times whose offset isn't a whole number of
+// hours are written in UTC, which ParseDateTime reads back as the same
+// instant.
+func FormatDateTime(t time.Time) string {
+	_, offset := t.Zone()
+	if offset%3600 != 0 {
+		t, offset = t.UTC(), 0
+	}
+
+	return fmt.Sprintf("%s[%d]", t.Format("20060102150405.000"), offset/3600)
+}
+
+func formatOptionalDateTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return FormatDateTime(*t)
+}
@@ -0,0 +1,162 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A4 page size, in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is one of the standard Courier fonts, which every reader has, so
+// nothing needs to be embedded. Being monospaced, text can be measured
+// without font metrics.
+type Font string
+
+const (
+	Regular Font = "F1"
+	Bold    Font = "F2"
+)
+
+// courierAdvance is the width of every Courier glyph, in units of the font
+// size.
+const courierAdvance = 0.6
+
+// Document is a PDF document of text and lines, written with WriteTo.
+// Coordinates are in points from the bottom left corner of the page.
+type Document struct {
+	title string
+	pages []*Page
+}
+
+type Page struct {
+	content bytes.Buffer
+}
+
+func New(title string) *Document {
+	return &Document{title: title}
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+
+	return page
+}
+
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// TextWidth is the width of text written at size, in points.
+func TextWidth(size float64, text string) float64 {
+	return float64(len([]rune(text))) * size * courierAdvance
+}
+
+// Text writes text with its baseline starting at x, y. Characters outside of
+// the Windows-1252 charset are replaced with a question mark.
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, number(size), number(x), number(y), encodeText(text))
+}
+
+// TextRight writes text so that it ends at x.
+func (p *Page) TextRight(x, y float64, font Font, size float64, text string) {
+	p.Text(x-TextWidth(size, text), y, font, size, text)
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(y1), number(x2), number(y2))
+}
+
+// WriteTo writes the document, with a blank page when none was added.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	var (
+		buffer  bytes.Buffer
+		offsets []int
+	)
+
+	writeObject := func(body string) {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(&buffer, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 5 are the catalog, the page tree, the fonts and the
+	// information dictionary; each page is then followed by its content.
+	const firstPageObject = 6
+
+	kids := make([]string, 0, len(pages))
+	for index := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPageObject+2*index))
+	}
+
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	writeObject(fmt.Sprintf("<< /Title (%s) /Producer (accounts-api) >>", encodeText(d.title)))
+
+	for index, page := range pages {
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+				"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			number(PageWidth), number(PageHeight), Regular, Bold, firstPageObject+2*index+1,
+		))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xrefOffset := buffer.Len()
+
+	fmt.Fprintf(&buffer, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buffer, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&buffer, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xrefOffset)
+
+	return buffer.WriteTo(w)
+}
+
+func number(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// encodeText converts text to Windows-1252, the encoding of the fonts, and
+// escapes it as a PDF literal string.
+func encodeText(text string) string {
+	var encoded strings.Builder
+
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			encoded.WriteByte('\\')
+			encoded.WriteRune(r)
+		case r >= 0x20 && r <= 0x7e:
+			encoded.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 and Windows-1252 agree on this range; it's written as an
+			// octal escape so the content stays ASCII.
+			fmt.Fprintf(&encoded, "\\%03o", r)
+		default:
+			encoded.WriteByte('?')
+		}
+	}
+
+	return encoded.String()
+}
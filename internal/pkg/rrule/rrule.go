@@ -0,0 +1,200 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// Rule is the subset of an RFC 5545 recurrence rule needed for standing
+// orders: FREQ, INTERVAL, COUNT, UNTIL and, for monthly rules, BYMONTHDAY.
+//
+// Occurrences are counted from the first one on or after the start, which is
+// the start itself unless BYMONTHDAY moves it, and keep the time of day of the
+// start in its location.
+type Rule struct {
+	Frequency Frequency
+	Interval  int
+	// Count bounds the number of occurrences when positive.
+	Count int
+	// Until bounds the occurrences when set; a date without a time includes
+	// the whole day.
+	Until       *time.Time
+	untilIsDate bool
+	// MonthDay is the day of the month of monthly occurrences, counted from
+	// the end of the month when negative (-1 is the last day). Months without
+	// that day use their last day. Zero keeps the day of the start.
+	MonthDay int
+}
+
+const (
+	untilDateLayout     = "20060102"
+	untilDateTimeLayout = "20060102T150405Z"
+)
+
+// Parse reads a rule such as "FREQ=MONTHLY;BYMONTHDAY=5;COUNT=12". A leading
+// "RRULE:" is accepted.
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Rule{}, errors.New("rrule: empty rule")
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		name, partValue, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("rrule: invalid part %q", part)
+		}
+
+		var err error
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Frequency = Frequency(strings.ToUpper(partValue))
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(name, partValue)
+		case "COUNT":
+			rule.Count, err = parsePositive(name, partValue)
+		case "UNTIL":
+			err = rule.parseUntil(partValue)
+		case "BYMONTHDAY":
+			rule.MonthDay, err = strconv.Atoi(partValue)
+			if err == nil && (rule.MonthDay == 0 || rule.MonthDay < -31 || rule.MonthDay > 31) {
+				err = errors.New("rrule: BYMONTHDAY must be between 1 and 31, or -31 and -1")
+			}
+		default:
+			err = fmt.Errorf("rrule: %s is not supported", name)
+		}
+
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	switch rule.Frequency {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return Rule{}, errors.New("rrule: FREQ is required")
+	default:
+		return Rule{}, fmt.Errorf("rrule: FREQ=%s is not supported", rule.Frequency)
+	}
+
+	if rule.MonthDay != 0 && rule.Frequency != Monthly {
+		return Rule{}, errors.New("rrule: BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return Rule{}, errors.New("rrule: COUNT and UNTIL can't be used together")
+	}
+
+	return rule, nil
+}
+
+func parsePositive(name string, value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("rrule: %s must be a positive integer", name)
+	}
+
+	return number, nil
+}
+
+func (r *Rule) parseUntil(value string) error {
+	if until, err := time.Parse(untilDateTimeLayout, value); err == nil {
+		r.Until = &until
+
+		return nil
+	}
+
+	until, err := time.Parse(untilDateLayout, value)
+	if err != nil {
+		return errors.New("rrule: UNTIL must be a date (YYYYMMDD) or a UTC date-time (YYYYMMDDTHHMMSSZ)")
+	}
+
+	r.Until = &until
+	r.untilIsDate = true
+
+	return nil
+}
+
+// Occurrence returns the occurrence of index n (0 is the first), and whether
+// the rule still has it.
+func (r Rule) Occurrence(start time.Time, n int) (time.Time, bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+
+	step := n * r.Interval
+
+	var occurrence time.Time
+
+	switch r.Frequency {
+	case Daily:
+		occurrence = start.AddDate(0, 0, step)
+	case Weekly:
+		occurrence = start.AddDate(0, 0, 7*step)
+	case Monthly:
+		monthDay := r.MonthDay
+		if monthDay == 0 {
+			monthDay = start.Day()
+		}
+
+		// The first occurrence is the next month when that day has already
+		// passed in the month of the start.
+		if dateInMonth(start, start.Year(), start.Month(), monthDay).Before(start) {
+			step++
+		}
+
+		occurrence = dateInMonth(start, start.Year(), start.Month()+time.Month(step), monthDay)
+	case Yearly:
+		occurrence = dateInMonth(start, start.Year()+step, start.Month(), start.Day())
+	}
+
+	if r.Until != nil && r.after(occurrence) {
+		return time.Time{}, false
+	}
+
+	return occurrence, true
+}
+
+func (r Rule) after(occurrence time.Time) bool {
+	if !r.untilIsDate {
+		return occurrence.After(*r.Until)
+	}
+
+	year, month, day := occurrence.Date()
+	occurrenceDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	return occurrenceDate.After(*r.Until)
+}
+
+// dateInMonth is the day of the month (from the end when negative) at the
+// time of day of start, clamped to the days the month has.
+func dateInMonth(start time.Time, year int, month time.Month, day int) time.Time {
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, start.Location())
+	daysInMonth := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	if day < 0 {
+		day = daysInMonth + day + 1
+	}
+
+	day = max(1, min(day, daysInMonth))
+
+	return time.Date(
+		firstOfMonth.Year(), firstOfMonth.Month(), day,
+		start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location(),
+	)
+}
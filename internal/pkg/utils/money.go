@@ -16,15 +16,13 @@ func negativeAmount(amount int64) int64 {
 	return amount * -1
 }
 
-func ApplyMoneyDirection(amount int64, operation models.OperationType) (int64, error) {
-	formatterMap := map[models.OperationType]func(amount int64) int64{
-		models.CreditVoucher:             positiveAmount,
-		models.NormalPurchase:            negativeAmount,
-		models.PurcharseWithInstallments: negativeAmount,
-		models.Withdrawal:                negativeAmount,
+func ApplyMoneyDirection(amount int64, direction models.OperationDirection) (int64, error) {
+	formatterMap := map[models.OperationDirection]func(amount int64) int64{
+		models.CreditDirection: positiveAmount,
+		models.DebitDirection:  negativeAmount,
 	}
 
-	moneyFormatter, ok := formatterMap[operation]
+	moneyFormatter, ok := formatterMap[direction]
 	if !ok {
 		return 0, cerror.New(cerror.Params{
 			Status:  http.StatusInternalServerError,
@@ -43,3 +41,29 @@ func FromCents(cents int64) float64 {
 	result, _ := decimal.NewFromInt(cents).Div(decimal.NewFromInt(100)).Float64()
 	return result
 }
+
+// FormatCents writes an amount in cents as a decimal number with two places,
+// e.g. -1050 as "-10.50".
+func FormatCents(cents int64) string {
+	return decimal.New(cents, -2).StringFixed(2)
+}
+
+func FromCentsPointer(cents *int64) *float64 {
+	if cents == nil {
+		return nil
+	}
+
+	amount := FromCents(*cents)
+
+	return &amount
+}
+
+func ToCentsPointer(amount *float64) *int64 {
+	if amount == nil {
+		return nil
+	}
+
+	cents := ToCents(*amount)
+
+	return &cents
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable is an optional JSON field that tells a missing field apart from an
+// explicit null, so partial updates can clear a value. Set is true whenever
+// the field is present, and Value is nil when it's null.
+type Nullable[T any] struct {
+	Value *T
+	Set   bool
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Value = nil
+
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	n.Value = &value
+
+	return nil
+}
+
+// Validated returns the value for validation, or nil when it's missing or
+// null, so omitempty skips it.
+func (n Nullable[T]) Validated() any {
+	if n.Value == nil {
+		return nil
+	}
+
+	return *n.Value
+}
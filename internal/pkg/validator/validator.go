@@ -1,8 +1,11 @@
 package validator
 
 import (
+	"reflect"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/tiagovaldrich/accounts-api/internal/pkg/cerror"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/utils"
 )
 
 var validate *validator.Validate
@@ -10,7 +13,17 @@ var validate *validator.Validate
 func init() {
 	if validate == nil {
 		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.RegisterCustomTypeFunc(nullableValue, utils.Nullable[float64]{})
+	}
+}
+
+// nullableValue validates a utils.Nullable field by its value.
+func nullableValue(field reflect.Value) any {
+	if nullable, ok := field.Interface().(interface{ Validated() any }); ok {
+		return nullable.Validated()
 	}
+
+	return nil
 }
 
 func ValidateStruct(value any) *cerror.Error {
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+// AuditLogFilter narrows the entries listed; zero fields don't filter.
+type AuditLogFilter struct {
+	EntityType        *string
+	EntityID          *uuid.UUID
+	CustomerAccountID *uuid.UUID
+	From              *time.Time
+	To                *time.Time
+	Limit             int
+}
+
+type AuditLogRepository interface {
+	Base
+	CreateAuditLog(ctx context.Context, entry models.AuditLog) (*models.AuditLog, error)
+	ListAuditLogs(ctx context.Context, filter AuditLogFilter) ([]models.AuditLog, error)
+}
+
+type auditLogRepository struct {
+	BaseRepo
+}
+
+func NewAuditLogRepository(db bun.IDB) AuditLogRepository {
+	repo := &auditLogRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+func (ar *auditLogRepository) CreateAuditLog(ctx context.Context, entry models.AuditLog) (*models.AuditLog, error) {
+	_, err := ar.GetDB(ctx).
+		NewInsert().
+		Model(&entry).
+		Exec(ctx)
+
+	return &entry, err
+}
+
+// ListAuditLogs returns the newest entries first. From is inclusive and To is
+// exclusive, so consecutive windows don't overlap.
+func (ar *auditLogRepository) ListAuditLogs(ctx context.Context, filter AuditLogFilter) ([]models.AuditLog, error) {
+	result := []models.AuditLog{}
+
+	query := ar.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Order("created_at DESC", "id DESC").
+		Limit(filter.Limit)
+
+	if filter.EntityType != nil {
+		query = query.Where("entity_type = ?", *filter.EntityType)
+	}
+
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+
+	if filter.CustomerAccountID != nil {
+		query = query.Where("customer_account_id = ?", filter.CustomerAccountID)
+	}
+
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	if err := query.Scan(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
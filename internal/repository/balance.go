@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
@@ -14,9 +15,11 @@ type BalanceRepository interface {
 	Base
 	CreateCustomerBalance(ctx context.Context, customerAccountBalance models.Balance) (*models.Balance, error)
 	GetCustomerAccountBalance(ctx context.Context, customerAccountID *uuid.UUID) (*models.Balance, error)
+	GetCustomerAccountBalanceUnlocked(ctx context.Context, customerAccountID *uuid.UUID) (*models.Balance, error)
 	UpdateCustomerAccountBalance(
 		ctx context.Context, newBalance models.Balance,
 	) (models.Balance, error)
+	UpdateCustomerAccountBalanceIfVersion(ctx context.Context, newBalance models.Balance, version int64) (bool, error)
 }
 
 type balanceRepository struct {
@@ -39,15 +42,33 @@ func (br *balanceRepository) CreateCustomerBalance(ctx context.Context, customer
 	return &customerAccountBalance, err
 }
 
+// GetCustomerAccountBalance reads the balance holding its row lock until the
+// end of the transaction (pessimistic strategy).
 func (br *balanceRepository) GetCustomerAccountBalance(ctx context.Context, customerAccountID *uuid.UUID) (*models.Balance, error) {
+	return br.getCustomerAccountBalance(ctx, customerAccountID, true)
+}
+
+// GetCustomerAccountBalanceUnlocked reads the balance without locking it; the
+// version read must be checked when updating it (optimistic strategy).
+func (br *balanceRepository) GetCustomerAccountBalanceUnlocked(ctx context.Context, customerAccountID *uuid.UUID) (*models.Balance, error) {
+	return br.getCustomerAccountBalance(ctx, customerAccountID, false)
+}
+
+func (br *balanceRepository) getCustomerAccountBalance(
+	ctx context.Context, customerAccountID *uuid.UUID, forUpdate bool,
+) (*models.Balance, error) {
 	var result models.Balance
 
-	err := br.GetDB(ctx).
+	query := br.GetDB(ctx).
 		NewSelect().
 		Model(&result).
-		Where("customer_account_id = ?", customerAccountID).
-		For("UPDATE").
-		Scan(ctx, &result)
+		Where("customer_account_id = ?", customerAccountID)
+
+	if forUpdate {
+		query = query.For("UPDATE")
+	}
+
+	err := query.Scan(ctx, &result)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -62,11 +83,39 @@ func (br *balanceRepository) GetCustomerAccountBalance(ctx context.Context, cust
 func (br *balanceRepository) UpdateCustomerAccountBalance(
 	ctx context.Context, newBalance models.Balance,
 ) (models.Balance, error) {
-	_, err := br.GetDB(ctx).
-		NewUpdate().
-		Model(&newBalance).
-		Where("customer_account_id = ?", newBalance.CustomerAccountID).
-		Exec(ctx)
+	_, err := br.updateCustomerAccountBalanceQuery(ctx, newBalance).Exec(ctx)
 
 	return newBalance, err
 }
+
+// UpdateCustomerAccountBalanceIfVersion updates the balance only if it's still
+// at version, reporting whether it was updated.
+func (br *balanceRepository) UpdateCustomerAccountBalanceIfVersion(
+	ctx context.Context, newBalance models.Balance, version int64,
+) (bool, error) {
+	result, err := br.updateCustomerAccountBalanceQuery(ctx, newBalance).
+		Where("version = ?", version).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return updated == 1, nil
+}
+
+// updateCustomerAccountBalanceQuery bumps the version on every update, so
+// optimistic writers also notice updates made under the pessimistic strategy.
+func (br *balanceRepository) updateCustomerAccountBalanceQuery(ctx context.Context, newBalance models.Balance) *bun.UpdateQuery {
+	return br.GetDB(ctx).
+		NewUpdate().
+		Model((*models.Balance)(nil)).
+		Set("balance = ?", newBalance.Balance).
+		Set("version = version + 1").
+		Set("updated_at = ?", time.Now()).
+		Where("customer_account_id = ?", newBalance.CustomerAccountID)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/uptrace/bun"
+)
+
+type BalanceSnapshotRepository interface {
+	Base
+	CreateBalanceSnapshots(ctx context.Context, snapshotAt time.Time) (int64, error)
+	GetCustomerAccountBalanceAt(ctx context.Context, customerAccountID *uuid.UUID, at time.Time) (int64, error)
+	GetCustomerAccountBalanceBefore(ctx context.Context, customerAccountID *uuid.UUID, before time.Time) (int64, error)
+}
+
+type balanceSnapshotRepository struct {
+	BaseRepo
+}
+
+func NewBalanceSnapshotRepository(db bun.IDB) BalanceSnapshotRepository {
+	repo := &balanceSnapshotRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+// CreateBalanceSnapshots writes a snapshot at snapshotAt for every account that
+// doesn't have one yet, starting from each account's previous snapshot.
+func (br *balanceSnapshotRepository) CreateBalanceSnapshots(ctx context.Context, snapshotAt time.Time) (int64, error) {
+	result, err := br.GetDB(ctx).
+		NewRaw(`
+			INSERT INTO balance_snapshot (id, customer_account_id, balance, snapshot_at)
+			SELECT
+				gen_random_uuid(),
+				ca.id,
+				COALESCE(previous.balance, 0) + COALESCE((
+					SELECT SUM(t.amount)
+					FROM transactions t
+					WHERE t.customer_account_id = ca.id
+						AND t.created_at >= COALESCE(previous.snapshot_at, '-infinity')
+						AND t.created_at < ?0
+				), 0),
+				?0
+			FROM customer_account ca
+			LEFT JOIN LATERAL (
+				SELECT bs.balance, bs.snapshot_at
+				FROM balance_snapshot bs
+				WHERE bs.customer_account_id = ca.id
+					AND bs.snapshot_at < ?0
+				ORDER BY bs.snapshot_at DESC
+				LIMIT 1
+			) previous ON TRUE
+			WHERE ca.created_at < ?0
+			ON CONFLICT (customer_account_id, snapshot_at) DO NOTHING
+		`, snapshotAt).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetCustomerAccountBalanceAt sums the transactions created after the latest
+// snapshot taken up to at, so old accounts don't need a full history scan.
+func (br *balanceSnapshotRepository) GetCustomerAccountBalanceAt(
+	ctx context.Context, customerAccountID *uuid.UUID, at time.Time,
+) (int64, error) {
+	var balance int64
+
+	err := br.GetDB(ctx).
+		NewRaw(`
+			SELECT COALESCE(latest.balance, 0) + COALESCE((
+				SELECT SUM(t.amount)
+				FROM transactions t
+				WHERE t.customer_account_id = ?0
+					AND t.created_at >= COALESCE(latest.snapshot_at, '-infinity')
+					AND t.created_at <= ?1
+			), 0)
+			FROM (SELECT 1) AS base
+			LEFT JOIN LATERAL (
+				SELECT bs.balance, bs.snapshot_at
+				FROM balance_snapshot bs
+				WHERE bs.customer_account_id = ?0
+					AND bs.snapshot_at <= ?1
+				ORDER BY bs.snapshot_at DESC
+				LIMIT 1
+			) latest ON TRUE
+		`, customerAccountID, at).
+		Scan(ctx, &balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+// GetCustomerAccountBalanceBefore is the balance of the account right before
+// before, i.e. without the transactions created at that instant. A snapshot
+// taken at before holds exactly that, so it can be used as is.
+func (br *balanceSnapshotRepository) GetCustomerAccountBalanceBefore(
+	ctx context.Context, customerAccountID *uuid.UUID, before time.Time,
+) (int64, error) {
+	var balance int64
+
+	err := br.GetDB(ctx).
+		NewRaw(`
+			SELECT COALESCE(latest.balance, 0) + COALESCE((
+				SELECT SUM(t.amount)
+				FROM transactions t
+				WHERE t.customer_account_id = ?0
+					AND t.created_at >= COALESCE(latest.snapshot_at, '-infinity')
+					AND t.created_at < ?1
+			), 0)
+			FROM (SELECT 1) AS base
+			LEFT JOIN LATERAL (
+				SELECT bs.balance, bs.snapshot_at
+				FROM balance_snapshot bs
+				WHERE bs.customer_account_id = ?0
+					AND bs.snapshot_at <= ?1
+				ORDER BY bs.snapshot_at DESC
+				LIMIT 1
+			) latest ON TRUE
+		`, customerAccountID, before).
+		Scan(ctx, &balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
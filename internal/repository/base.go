@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 
 	"github.com/uptrace/bun"
 )
@@ -10,11 +9,12 @@ import (
 type ContextKey string
 
 const (
-	TxKey ContextKey = "database_tx"
+	TxKey      ContextKey = "database_tx"
+	txHooksKey ContextKey = "database_tx_hooks"
 )
 
 type Base interface {
-	WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error
+	WithTransaction(ctx context.Context, fn func(txCtx context.Context) error, opts ...TxOption) error
 	GetDB(ctx context.Context) bun.IDB
 	SetDB(db bun.IDB)
 }
@@ -23,12 +23,47 @@ type BaseRepo struct {
 	db bun.IDB
 }
 
-func (br *BaseRepo) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
-	return br.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		txCtx := context.WithValue(ctx, TxKey, tx)
+// WithTransaction runs fn in a database transaction, retrying it with a
+// jittered backoff when it fails with a serialization failure or a deadlock,
+// so fn must be safe to run more than once.
+//
+// When ctx already carries a transaction, fn joins it inside a SAVEPOINT
+// instead: an error rolls back only what fn did, and the options and retries
+// of the outer transaction apply.
+func (br *BaseRepo) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error, opts ...TxOption) error {
+	if tx, ok := ctx.Value(TxKey).(bun.Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
 
-		return fn(txCtx)
-	})
+	config := newTxConfig(opts)
+
+	for attempt := 0; ; attempt++ {
+		hooks := &txHooks{}
+
+		err := br.db.RunInTx(ctx, &config.options, func(ctx context.Context, tx bun.Tx) error {
+			return fn(withTx(ctx, tx, hooks))
+		})
+		if err == nil {
+			hooks.run(ctx)
+
+			return nil
+		}
+
+		sqlState, retryable := retryableTxError(err)
+		if !retryable {
+			return err
+		}
+
+		if attempt >= config.maxRetries {
+			TransactionMetrics.Add("retries_exhausted", 1)
+
+			return err
+		}
+
+		if err := waitTxRetry(ctx, attempt, sqlState); err != nil {
+			return err
+		}
+	}
 }
 
 func (br *BaseRepo) GetDB(ctx context.Context) bun.IDB {
@@ -42,3 +77,47 @@ func (br *BaseRepo) GetDB(ctx context.Context) bun.IDB {
 func (br *BaseRepo) SetDB(db bun.IDB) {
 	br.db = db
 }
+
+// InTransaction tells whether ctx carries a database transaction.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(TxKey).(bun.Tx)
+
+	return ok
+}
+
+// AfterCommit runs hook once the outermost transaction carried by ctx commits;
+// it's dropped if the transaction, or the savepoint it was registered in, rolls
+// back. Outside a transaction hook runs right away.
+func AfterCommit(ctx context.Context, hook func(ctx context.Context)) {
+	hooks, ok := ctx.Value(txHooksKey).(*txHooks)
+	if !ok {
+		hook(ctx)
+
+		return
+	}
+
+	hooks.add(hook)
+}
+
+func withSavepoint(ctx context.Context, tx bun.Tx, fn func(txCtx context.Context) error) error {
+	hooks := &txHooks{}
+
+	err := tx.RunInTx(ctx, nil, func(ctx context.Context, savepoint bun.Tx) error {
+		return fn(withTx(ctx, savepoint, hooks))
+	})
+	if err != nil {
+		return err
+	}
+
+	if parentHooks, ok := ctx.Value(txHooksKey).(*txHooks); ok {
+		parentHooks.add(hooks.afterCommit...)
+	} else {
+		hooks.run(ctx)
+	}
+
+	return nil
+}
+
+func withTx(ctx context.Context, tx bun.Tx, hooks *txHooks) context.Context {
+	return context.WithValue(context.WithValue(ctx, TxKey, tx), txHooksKey, hooks)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
@@ -19,6 +20,7 @@ type CustomerAccountRepository interface {
 	SearchCustomerAccountByID(
 		ctx context.Context, customerAccountID *uuid.UUID,
 	) (*CustomerAccountByIDResult, error)
+	SetCustomerAccountBlockedAt(ctx context.Context, customerAccountID *uuid.UUID, blockedAt *time.Time) error
 }
 
 type customerAccountRepository struct {
@@ -54,6 +56,7 @@ func (r *customerAccountRepository) SearchCustomerAccountByID(
 		Model(&result).
 		ColumnExpr("c.document AS document").
 		ColumnExpr("ca.id AS id").
+		ColumnExpr("ca.blocked_at AS blocked_at").
 		ColumnExpr("ca.created_at AS created_at").
 		TableExpr("customer_account ca").
 		Join("JOIN customer c ON c.id = ca.customer_id").
@@ -69,3 +72,17 @@ func (r *customerAccountRepository) SearchCustomerAccountByID(
 
 	return &result, nil
 }
+
+func (r *customerAccountRepository) SetCustomerAccountBlockedAt(
+	ctx context.Context, customerAccountID *uuid.UUID, blockedAt *time.Time,
+) error {
+	_, err := r.GetDB(ctx).
+		NewUpdate().
+		Model((*models.CustomerAccount)(nil)).
+		Set("blocked_at = ?", blockedAt).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", customerAccountID).
+		Exec(ctx)
+
+	return err
+}
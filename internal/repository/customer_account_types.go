@@ -11,5 +11,6 @@ type CustomerAccountByIDResult struct {
 	bun.BaseModel `bun:"table:customer_account"`
 	ID            *uuid.UUID `bun:"id"`
 	Document      string     `bun:"document"`
+	BlockedAt     *time.Time `bun:"blocked_at"`
 	CreatedAt     time.Time  `bun:"created_at"`
 }
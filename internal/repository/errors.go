@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+const (
+	lockNotAvailableCode     = "55P03"
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+// IsLockNotAvailable reports whether err was raised because a lock couldn't be
+// acquired within the lock_timeout of the transaction.
+func IsLockNotAvailable(err error) bool {
+	var pgErr pgdriver.Error
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Field('C') == lockNotAvailableCode
+}
+
+// retryableTxError returns the SQLSTATE of err when it's a serialization
+// failure or a deadlock, which are solved by running the transaction again.
+func retryableTxError(err error) (string, bool) {
+	var pgErr pgdriver.Error
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+
+	sqlState := pgErr.Field('C')
+
+	return sqlState, sqlState == serializationFailureCode || sqlState == deadlockDetectedCode
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type IdempotencyRecordRepository interface {
+	Base
+	ClaimIdempotencyKey(ctx context.Context, record models.IdempotencyRecord, lockTimeout time.Duration) (bool, error)
+	GetIdempotencyRecord(ctx context.Context, customerAccountID *uuid.UUID, idempotencyKey string) (*models.IdempotencyRecord, error)
+	SetIdempotencyRecordTransaction(ctx context.Context, record models.IdempotencyRecord) error
+	PurgeIdempotencyRecords(ctx context.Context, createdBefore time.Time, limit int) (int64, error)
+}
+
+type idempotencyRecordRepository struct {
+	BaseRepo
+}
+
+func NewIdempotencyRecordRepository(db bun.IDB) IdempotencyRecordRepository {
+	repo := &idempotencyRecordRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+// ClaimIdempotencyKey inserts the record unless the key is already taken, and
+// must run inside a transaction. If another transaction holds an uncommitted
+// claim on the same key, it waits up to lockTimeout for it to finish; when it
+// commits the key is reported as not claimed, when it rolls back the claim
+// goes through.
+func (ir *idempotencyRecordRepository) ClaimIdempotencyKey(
+	ctx context.Context,
+	record models.IdempotencyRecord,
+	lockTimeout time.Duration,
+) (bool, error) {
+	db := ir.GetDB(ctx)
+
+	_, err := db.NewRaw(
+		"SELECT set_config('lock_timeout', ?, true)", fmt.Sprintf("%dms", lockTimeout.Milliseconds()),
+	).Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.NewInsert().
+		Model(&record).
+		On("CONFLICT (customer_account_id, idempotency_key) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := db.NewRaw("SET LOCAL lock_timeout TO DEFAULT").Exec(ctx); err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return claimed == 1, nil
+}
+
+func (ir *idempotencyRecordRepository) GetIdempotencyRecord(
+	ctx context.Context,
+	customerAccountID *uuid.UUID,
+	idempotencyKey string,
+) (*models.IdempotencyRecord, error) {
+	var result models.IdempotencyRecord
+
+	err := ir.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("customer_account_id = ?", customerAccountID).
+		Where("idempotency_key = ?", idempotencyKey).
+		Scan(ctx, &result)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (ir *idempotencyRecordRepository) SetIdempotencyRecordTransaction(ctx context.Context, record models.IdempotencyRecord) error {
+	_, err := ir.GetDB(ctx).
+		NewUpdate().
+		Model(&record).
+		Column("transaction_id", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// PurgeIdempotencyRecords releases up to limit idempotency keys claimed before
+// createdBefore, so they can be used again. The transactions are kept; only
+// their key is cleared.
+func (ir *idempotencyRecordRepository) PurgeIdempotencyRecords(ctx context.Context, createdBefore time.Time, limit int) (int64, error) {
+	var purged int64
+
+	err := ir.GetDB(ctx).
+		NewRaw(`
+			WITH purged AS (
+				DELETE FROM idempotency_record
+				WHERE (customer_account_id, idempotency_key) IN (
+					SELECT customer_account_id, idempotency_key
+					FROM idempotency_record
+					WHERE created_at < ?
+					ORDER BY created_at
+					LIMIT ?
+					FOR UPDATE SKIP LOCKED
+				)
+				RETURNING customer_account_id, idempotency_key
+			), released AS (
+				UPDATE transactions t
+				SET idempotency_key = NULL, updated_at = now()
+				FROM purged p
+				WHERE t.customer_account_id = p.customer_account_id
+					AND t.idempotency_key = p.idempotency_key
+			)
+			SELECT count(*) FROM purged
+		`, createdBefore, limit).
+		Scan(ctx, &purged)
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
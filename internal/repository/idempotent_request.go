@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type IdempotentRequestRepository interface {
+	Base
+	ClaimIdempotentRequest(ctx context.Context, request models.IdempotentRequest, lease time.Duration) (bool, error)
+	RenewIdempotentRequest(ctx context.Context, request models.IdempotentRequest) (bool, error)
+	GetIdempotentRequest(ctx context.Context, method string, path string, idempotencyKey string) (*models.IdempotentRequest, error)
+	CompleteIdempotentRequest(ctx context.Context, request models.IdempotentRequest) error
+	ReleaseIdempotentRequest(ctx context.Context, request models.IdempotentRequest) error
+	PurgeIdempotentRequests(ctx context.Context, createdBefore time.Time, limit int) (int64, error)
+}
+
+type idempotentRequestRepository struct {
+	BaseRepo
+}
+
+func NewIdempotentRequestRepository(db bun.IDB) IdempotentRequestRepository {
+	repo := &idempotentRequestRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+// ClaimIdempotentRequest inserts the request as in progress unless the key is
+// already taken. The claim is a lease: one whose holder hasn't renewed it
+// within lease (e.g. because its instance crashed) is taken over.
+func (ir *idempotentRequestRepository) ClaimIdempotentRequest(
+	ctx context.Context,
+	request models.IdempotentRequest,
+	lease time.Duration,
+) (bool, error) {
+	result, err := ir.GetDB(ctx).
+		NewInsert().
+		Model(&request).
+		On("CONFLICT (method, path, idempotency_key) DO UPDATE").
+		Set("request_fingerprint = EXCLUDED.request_fingerprint").
+		Set("claim_id = EXCLUDED.claim_id").
+		Set("created_at = EXCLUDED.created_at").
+		Set("updated_at = EXCLUDED.updated_at").
+		Where("idempotent_request.status_code IS NULL").
+		Where("idempotent_request.updated_at < ?", time.Now().Add(-lease)).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return claimed == 1, nil
+}
+
+// RenewIdempotentRequest extends the lease of the claim, reporting false when
+// it's no longer held by the request.
+func (ir *idempotentRequestRepository) RenewIdempotentRequest(
+	ctx context.Context, request models.IdempotentRequest,
+) (bool, error) {
+	result, err := ir.GetDB(ctx).
+		NewUpdate().
+		Model(&request).
+		Column("updated_at").
+		WherePK().
+		Where("claim_id = ?", request.ClaimID).
+		Where("status_code IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	renewed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return renewed == 1, nil
+}
+
+func (ir *idempotentRequestRepository) GetIdempotentRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	idempotencyKey string,
+) (*models.IdempotentRequest, error) {
+	var result models.IdempotentRequest
+
+	err := ir.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("method = ?", method).
+		Where("path = ?", path).
+		Where("idempotency_key = ?", idempotencyKey).
+		Scan(ctx, &result)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (ir *idempotentRequestRepository) CompleteIdempotentRequest(ctx context.Context, request models.IdempotentRequest) error {
+	_, err := ir.GetDB(ctx).
+		NewUpdate().
+		Model(&request).
+		Column("status_code", "response_content_type", "response_body", "updated_at").
+		WherePK().
+		Where("claim_id = ?", request.ClaimID).
+		Exec(ctx)
+
+	return err
+}
+
+func (ir *idempotentRequestRepository) ReleaseIdempotentRequest(ctx context.Context, request models.IdempotentRequest) error {
+	_, err := ir.GetDB(ctx).
+		NewDelete().
+		Model(&request).
+		WherePK().
+		Where("claim_id = ?", request.ClaimID).
+		Exec(ctx)
+
+	return err
+}
+
+func (ir *idempotentRequestRepository) PurgeIdempotentRequests(ctx context.Context, createdBefore time.Time, limit int) (int64, error) {
+	result, err := ir.GetDB(ctx).
+		NewRaw(`
+			DELETE FROM idempotent_request
+			WHERE (method, path, idempotency_key) IN (
+				SELECT method, path, idempotency_key
+				FROM idempotent_request
+				WHERE created_at < ?
+				ORDER BY created_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+		`, createdBefore, limit).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type ImportFileRepository interface {
+	Base
+	CreateImportFile(ctx context.Context, importFile models.ImportFile) (*models.ImportFile, error)
+	GetImportFileByID(ctx context.Context, importFileID *uuid.UUID) (*models.ImportFile, error)
+	GetImportFileByHash(ctx context.Context, fileHash string) (*models.ImportFile, error)
+}
+
+type importFileRepository struct {
+	BaseRepo
+}
+
+func NewImportFileRepository(db bun.IDB) ImportFileRepository {
+	repo := &importFileRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+// CreateImportFile stores the report of a file unless one with the same hash
+// was stored concurrently, in which case that one is returned.
+func (ir *importFileRepository) CreateImportFile(
+	ctx context.Context, importFile models.ImportFile,
+) (*models.ImportFile, error) {
+	result, err := ir.GetDB(ctx).
+		NewInsert().
+		Model(&importFile).
+		On("CONFLICT (file_hash) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if created == 0 {
+		return ir.GetImportFileByHash(ctx, importFile.FileHash)
+	}
+
+	return &importFile, nil
+}
+
+func (ir *importFileRepository) GetImportFileByID(
+	ctx context.Context, importFileID *uuid.UUID,
+) (*models.ImportFile, error) {
+	var result models.ImportFile
+
+	err := ir.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("id = ?", importFileID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (ir *importFileRepository) GetImportFileByHash(
+	ctx context.Context, fileHash string,
+) (*models.ImportFile, error) {
+	var result models.ImportFile
+
+	err := ir.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("file_hash = ?", fileHash).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
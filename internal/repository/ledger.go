@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type LedgerRepository interface {
+	Base
+	GetLedgerAccountByCode(ctx context.Context, code models.LedgerAccountCode) (*models.LedgerAccount, error)
+	CreateJournalEntry(
+		ctx context.Context, journalEntry models.JournalEntry, postings []models.JournalPosting,
+	) (*models.JournalEntry, error)
+	GetTrialBalance(ctx context.Context) ([]TrialBalanceResult, error)
+}
+
+type ledgerRepository struct {
+	BaseRepo
+}
+
+func NewLedgerRepository(db bun.IDB) LedgerRepository {
+	repo := &ledgerRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+func (lr *ledgerRepository) GetLedgerAccountByCode(
+	ctx context.Context, code models.LedgerAccountCode,
+) (*models.LedgerAccount, error) {
+	var result models.LedgerAccount
+
+	err := lr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("code = ?", code).
+		Scan(ctx, &result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (lr *ledgerRepository) CreateJournalEntry(
+	ctx context.Context, journalEntry models.JournalEntry, postings []models.JournalPosting,
+) (*models.JournalEntry, error) {
+	_, err := lr.GetDB(ctx).
+		NewInsert().
+		Model(&journalEntry).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range postings {
+		postings[i].JournalEntryID = journalEntry.ID
+	}
+
+	_, err = lr.GetDB(ctx).
+		NewInsert().
+		Model(&postings).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &journalEntry, nil
+}
+
+func (lr *ledgerRepository) GetTrialBalance(ctx context.Context) ([]TrialBalanceResult, error) {
+	var result []TrialBalanceResult
+
+	err := lr.GetDB(ctx).
+		NewRaw(`
+			SELECT
+				'system' AS account_type,
+				la.code AS account,
+				COALESCE(SUM(-jp.amount) FILTER (WHERE jp.amount < 0), 0) AS debits,
+				COALESCE(SUM(jp.amount) FILTER (WHERE jp.amount > 0), 0) AS credits
+			FROM ledger_account la
+			LEFT JOIN journal_posting jp ON jp.ledger_account_id = la.id
+			GROUP BY la.code
+			UNION ALL
+			SELECT
+				'customer' AS account_type,
+				jp.customer_account_id::text AS account,
+				COALESCE(SUM(-jp.amount) FILTER (WHERE jp.amount < 0), 0) AS debits,
+				COALESCE(SUM(jp.amount) FILTER (WHERE jp.amount > 0), 0) AS credits
+			FROM journal_posting jp
+			WHERE jp.customer_account_id IS NOT NULL
+			GROUP BY jp.customer_account_id
+			ORDER BY account_type DESC, account
+		`).
+		Scan(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,8 @@
+package repository
+
+type TrialBalanceResult struct {
+	AccountType string `bun:"account_type"`
+	Account     string `bun:"account"`
+	Debits      int64  `bun:"debits"`
+	Credits     int64  `bun:"credits"`
+}
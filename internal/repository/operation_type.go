@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type OperationTypeRepository interface {
+	Base
+	ListOperationTypes(ctx context.Context) ([]models.OperationTypeDefinition, error)
+	GetOperationTypeByCode(ctx context.Context, code models.OperationType) (*models.OperationTypeDefinition, error)
+	CreateOperationType(
+		ctx context.Context, operationType models.OperationTypeDefinition,
+	) (*models.OperationTypeDefinition, error)
+	UpdateOperationType(
+		ctx context.Context, operationType models.OperationTypeDefinition,
+	) (*models.OperationTypeDefinition, error)
+}
+
+type operationTypeRepository struct {
+	BaseRepo
+}
+
+func NewOperationTypeRepository(db bun.IDB) OperationTypeRepository {
+	repo := &operationTypeRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+func (r *operationTypeRepository) ListOperationTypes(ctx context.Context) ([]models.OperationTypeDefinition, error) {
+	var result []models.OperationTypeDefinition
+
+	err := r.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Order("code").
+		Scan(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *operationTypeRepository) GetOperationTypeByCode(
+	ctx context.Context, code models.OperationType,
+) (*models.OperationTypeDefinition, error) {
+	var result models.OperationTypeDefinition
+
+	err := r.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("code = ?", code).
+		Scan(ctx, &result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *operationTypeRepository) CreateOperationType(
+	ctx context.Context, operationType models.OperationTypeDefinition,
+) (*models.OperationTypeDefinition, error) {
+	_, err := r.GetDB(ctx).
+		NewInsert().
+		Model(&operationType).
+		Exec(ctx)
+
+	return &operationType, err
+}
+
+func (r *operationTypeRepository) UpdateOperationType(
+	ctx context.Context, operationType models.OperationTypeDefinition,
+) (*models.OperationTypeDefinition, error) {
+	_, err := r.GetDB(ctx).
+		NewUpdate().
+		Model(&operationType).
+		WherePK().
+		Exec(ctx)
+
+	return &operationType, err
+}
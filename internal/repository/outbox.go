@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type OutboxRepository interface {
+	Base
+	CreateOutboxEvents(ctx context.Context, events ...models.OutboxEvent) error
+	ClaimDueOutboxEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	UpdateOutboxEventDelivery(ctx context.Context, event models.OutboxEvent) error
+	ListAccountOutboxEvents(
+		ctx context.Context, accountID *uuid.UUID, afterID int64, eventTypes []string, limit int,
+	) ([]models.OutboxEvent, error)
+	GetLatestAccountOutboxEventID(ctx context.Context, accountID *uuid.UUID) (int64, error)
+}
+
+type outboxRepository struct {
+	BaseRepo
+}
+
+func NewOutboxRepository(db bun.IDB) OutboxRepository {
+	repo := &outboxRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+func (or *outboxRepository) CreateOutboxEvents(ctx context.Context, events ...models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	_, err := or.GetDB(ctx).
+		NewInsert().
+		Model(&events).
+		ExcludeColumn("id").
+		Exec(ctx)
+
+	return err
+}
+
+// ClaimDueOutboxEvents locks the oldest pending event of each account, when
+// it's due. An account whose oldest event is waiting for a retry has none
+// claimed, which keeps its events in order; events locked by another
+// dispatcher are skipped. It must run inside a transaction.
+func (or *outboxRepository) ClaimDueOutboxEvents(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+
+	err := or.GetDB(ctx).
+		NewSelect().
+		Model(&events).
+		Where("status = ?", models.OutboxEventPending).
+		Where("next_attempt_at <= NOW()").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_event previous
+			WHERE previous.customer_account_id = outbox_event.customer_account_id
+			AND previous.status = ?
+			AND previous.id < outbox_event.id
+		)`, models.OutboxEventPending).
+		OrderExpr("id").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (or *outboxRepository) UpdateOutboxEventDelivery(ctx context.Context, event models.OutboxEvent) error {
+	_, err := or.GetDB(ctx).
+		NewUpdate().
+		Model(&event).
+		Column("status", "attempts", "last_error", "next_attempt_at", "dispatched_at", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// ListAccountOutboxEvents returns the events of the types given written for
+// the account after the event afterID, whatever their publishing status.
+func (or *outboxRepository) ListAccountOutboxEvents(
+	ctx context.Context, accountID *uuid.UUID, afterID int64, eventTypes []string, limit int,
+) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+
+	err := or.GetDB(ctx).
+		NewSelect().
+		Model(&events).
+		Where("customer_account_id = ?", accountID).
+		Where("id > ?", afterID).
+		Where("event_type IN (?)", bun.In(eventTypes)).
+		OrderExpr("id").
+		Limit(limit).
+		Scan(ctx)
+
+	return events, err
+}
+
+// GetLatestAccountOutboxEventID returns the id of the last event written for
+// the account, or 0 when it has none.
+func (or *outboxRepository) GetLatestAccountOutboxEventID(ctx context.Context, accountID *uuid.UUID) (int64, error) {
+	var id int64
+
+	err := or.GetDB(ctx).
+		NewSelect().
+		Model((*models.OutboxEvent)(nil)).
+		ColumnExpr("COALESCE(MAX(id), 0)").
+		Where("customer_account_id = ?", accountID).
+		Scan(ctx, &id)
+
+	return id, err
+}
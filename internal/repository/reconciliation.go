@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type ReconciliationRepository interface {
+	Base
+	CompareBalances(
+		ctx context.Context, afterCustomerAccountID *uuid.UUID, limit int,
+	) ([]BalanceComparisonResult, error)
+	CreateReconciliationRun(ctx context.Context, run models.ReconciliationRun) (*models.ReconciliationRun, error)
+	UpdateReconciliationRun(ctx context.Context, run models.ReconciliationRun) (*models.ReconciliationRun, error)
+	CreateReconciliationDrift(
+		ctx context.Context, drift models.ReconciliationDrift,
+	) (*models.ReconciliationDrift, error)
+	GetReconciliationDriftByID(ctx context.Context, driftID *uuid.UUID) (*models.ReconciliationDrift, error)
+	ListReconciliationDrifts(
+		ctx context.Context, status *models.ReconciliationDriftStatus,
+	) ([]models.ReconciliationDrift, error)
+	ResolveOpenReconciliationDrifts(
+		ctx context.Context, customerAccountID *uuid.UUID, resolvedAt time.Time,
+	) error
+	CompareAccountBalances(
+		ctx context.Context, afterCustomerAccountID *uuid.UUID, limit int,
+	) ([]AccountBalanceComparisonResult, error)
+	GetTransactionsTotal(ctx context.Context, customerAccountID *uuid.UUID) (int64, error)
+	CreateBalanceRebuild(ctx context.Context, rebuild models.BalanceRebuild) (*models.BalanceRebuild, error)
+	UpdateBalanceRebuild(ctx context.Context, rebuild models.BalanceRebuild) (*models.BalanceRebuild, error)
+	CreateBalanceAdjustment(
+		ctx context.Context, adjustment models.BalanceAdjustment,
+	) (*models.BalanceAdjustment, error)
+}
+
+type reconciliationRepository struct {
+	BaseRepo
+}
+
+func NewReconciliationRepository(db bun.IDB) ReconciliationRepository {
+	repo := &reconciliationRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+// CompareBalances returns, for a page of accounts ordered by id, the stored
+// balance next to the sum of the account transactions. Both values come from
+// the same statement, so each page is a consistent snapshot.
+func (rr *reconciliationRepository) CompareBalances(
+	ctx context.Context, afterCustomerAccountID *uuid.UUID, limit int,
+) ([]BalanceComparisonResult, error) {
+	var result []BalanceComparisonResult
+
+	query := rr.GetDB(ctx).
+		NewSelect().
+		ColumnExpr("b.customer_account_id AS customer_account_id").
+		ColumnExpr("b.balance AS balance").
+		ColumnExpr(`COALESCE((
+			SELECT SUM(t.amount) FROM transactions t WHERE t.customer_account_id = b.customer_account_id
+		), 0) AS transactions_total`).
+		TableExpr("balance b").
+		OrderExpr("b.customer_account_id").
+		Limit(limit)
+
+	if afterCustomerAccountID != nil {
+		query = query.Where("b.customer_account_id > ?", afterCustomerAccountID)
+	}
+
+	if err := query.Scan(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rr *reconciliationRepository) CreateReconciliationRun(
+	ctx context.Context, run models.ReconciliationRun,
+) (*models.ReconciliationRun, error) {
+	_, err := rr.GetDB(ctx).
+		NewInsert().
+		Model(&run).
+		Exec(ctx)
+
+	return &run, err
+}
+
+func (rr *reconciliationRepository) UpdateReconciliationRun(
+	ctx context.Context, run models.ReconciliationRun,
+) (*models.ReconciliationRun, error) {
+	_, err := rr.GetDB(ctx).
+		NewUpdate().
+		Model(&run).
+		WherePK().
+		Exec(ctx)
+
+	return &run, err
+}
+
+// CreateReconciliationDrift records the drift, or refreshes the open drift of
+// the account with the values of the latest run when there's one already.
+// The account stays blocked once a run has blocked it.
+func (rr *reconciliationRepository) CreateReconciliationDrift(
+	ctx context.Context, drift models.ReconciliationDrift,
+) (*models.ReconciliationDrift, error) {
+	_, err := rr.GetDB(ctx).
+		NewInsert().
+		Model(&drift).
+		On("CONFLICT (customer_account_id) WHERE status = 'open' DO UPDATE").
+		Set("reconciliation_run_id = EXCLUDED.reconciliation_run_id").
+		Set("balance = EXCLUDED.balance").
+		Set("transactions_total = EXCLUDED.transactions_total").
+		Set("drift = EXCLUDED.drift").
+		Set("account_blocked = reconciliation_drift.account_blocked OR EXCLUDED.account_blocked").
+		Set("updated_at = EXCLUDED.updated_at").
+		Returning("*").
+		Exec(ctx)
+
+	return &drift, err
+}
+
+func (rr *reconciliationRepository) GetReconciliationDriftByID(
+	ctx context.Context, driftID *uuid.UUID,
+) (*models.ReconciliationDrift, error) {
+	var result models.ReconciliationDrift
+
+	err := rr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("id = ?", driftID).
+		For("UPDATE").
+		Scan(ctx, &result)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rr *reconciliationRepository) ListReconciliationDrifts(
+	ctx context.Context, status *models.ReconciliationDriftStatus,
+) ([]models.ReconciliationDrift, error) {
+	result := []models.ReconciliationDrift{}
+
+	query := rr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Order("created_at DESC")
+
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	if err := query.Scan(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rr *reconciliationRepository) ResolveOpenReconciliationDrifts(
+	ctx context.Context, customerAccountID *uuid.UUID, resolvedAt time.Time,
+) error {
+	_, err := rr.GetDB(ctx).
+		NewUpdate().
+		Model((*models.ReconciliationDrift)(nil)).
+		Set("status = ?", models.ReconciliationDriftResolved).
+		Set("resolved_at = ?", resolvedAt).
+		Set("updated_at = ?", resolvedAt).
+		Where("customer_account_id = ?", customerAccountID).
+		Where("status = ?", models.ReconciliationDriftOpen).
+		Exec(ctx)
+
+	return err
+}
+
+// CompareAccountBalances is like CompareBalances, but starts from the accounts
+// so the ones missing their balance row are returned too.
+func (rr *reconciliationRepository) CompareAccountBalances(
+	ctx context.Context, afterCustomerAccountID *uuid.UUID, limit int,
+) ([]AccountBalanceComparisonResult, error) {
+	var result []AccountBalanceComparisonResult
+
+	query := rr.GetDB(ctx).
+		NewSelect().
+		ColumnExpr("ca.id AS customer_account_id").
+		ColumnExpr("b.balance AS balance").
+		ColumnExpr(`COALESCE((
+			SELECT SUM(t.amount) FROM transactions t WHERE t.customer_account_id = ca.id
+		), 0) AS transactions_total`).
+		TableExpr("customer_account ca").
+		Join("LEFT JOIN balance b ON b.customer_account_id = ca.id").
+		OrderExpr("ca.id").
+		Limit(limit)
+
+	if afterCustomerAccountID != nil {
+		query = query.Where("ca.id > ?", afterCustomerAccountID)
+	}
+
+	if err := query.Scan(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rr *reconciliationRepository) GetTransactionsTotal(ctx context.Context, customerAccountID *uuid.UUID) (int64, error) {
+	var total int64
+
+	err := rr.GetDB(ctx).
+		NewSelect().
+		ColumnExpr("COALESCE(SUM(amount), 0)").
+		TableExpr("transactions").
+		Where("customer_account_id = ?", customerAccountID).
+		Scan(ctx, &total)
+
+	return total, err
+}
+
+func (rr *reconciliationRepository) CreateBalanceRebuild(
+	ctx context.Context, rebuild models.BalanceRebuild,
+) (*models.BalanceRebuild, error) {
+	_, err := rr.GetDB(ctx).
+		NewInsert().
+		Model(&rebuild).
+		Exec(ctx)
+
+	return &rebuild, err
+}
+
+func (rr *reconciliationRepository) UpdateBalanceRebuild(
+	ctx context.Context, rebuild models.BalanceRebuild,
+) (*models.BalanceRebuild, error) {
+	_, err := rr.GetDB(ctx).
+		NewUpdate().
+		Model(&rebuild).
+		WherePK().
+		Exec(ctx)
+
+	return &rebuild, err
+}
+
+func (rr *reconciliationRepository) CreateBalanceAdjustment(
+	ctx context.Context, adjustment models.BalanceAdjustment,
+) (*models.BalanceAdjustment, error) {
+	_, err := rr.GetDB(ctx).
+		NewInsert().
+		Model(&adjustment).
+		Exec(ctx)
+
+	return &adjustment, err
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/gofrs/uuid/v5"
+
+type BalanceComparisonResult struct {
+	CustomerAccountID *uuid.UUID `bun:"customer_account_id"`
+	Balance           int64      `bun:"balance"`
+	TransactionsTotal int64      `bun:"transactions_total"`
+}
+
+// AccountBalanceComparisonResult is like BalanceComparisonResult, but covers
+// every account: Balance is nil when it has no balance row.
+type AccountBalanceComparisonResult struct {
+	CustomerAccountID *uuid.UUID `bun:"customer_account_id"`
+	Balance           *int64     `bun:"balance"`
+	TransactionsTotal int64      `bun:"transactions_total"`
+}
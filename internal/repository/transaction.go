@@ -4,15 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/gofrs/uuid/v5"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
 	"github.com/uptrace/bun"
 )
 
 type TransactionRepository interface {
 	Base
-	GetTransactionByIdempotencyKey(ctx context.Context, idempotencyKey string) (*models.Transaction, error)
 	CreateTransaction(context.Context, models.Transaction) (*models.Transaction, error)
+	GetTransactionByID(ctx context.Context, transactionID *uuid.UUID) (*models.Transaction, error)
+	ListCustomerAccountTransactions(
+		ctx context.Context, customerAccountID *uuid.UUID, from time.Time, to time.Time,
+	) ([]models.Transaction, error)
+	ListCustomerAccountsMissingBalanceAfter(ctx context.Context, limit int) ([]uuid.UUID, error)
+	BackfillBalanceAfter(ctx context.Context, customerAccountID *uuid.UUID) (int64, error)
 }
 
 type transactionRepository struct {
@@ -26,13 +33,22 @@ func NewTransactionRepository(db bun.IDB) TransactionRepository {
 	return repo
 }
 
-func (tr *transactionRepository) GetTransactionByIdempotencyKey(ctx context.Context, idempotencyKey string) (*models.Transaction, error) {
+func (tr *transactionRepository) CreateTransaction(ctx context.Context, transaction models.Transaction) (*models.Transaction, error) {
+	_, err := tr.GetDB(ctx).
+		NewInsert().
+		Model(&transaction).
+		Exec(ctx)
+
+	return &transaction, err
+}
+
+func (tr *transactionRepository) GetTransactionByID(ctx context.Context, transactionID *uuid.UUID) (*models.Transaction, error) {
 	var result models.Transaction
 
 	err := tr.GetDB(ctx).
 		NewSelect().
 		Model(&result).
-		Where("idempotency_key = ?", idempotencyKey).
+		Where("id = ?", transactionID).
 		Scan(ctx, &result)
 
 	if err != nil {
@@ -46,11 +62,66 @@ func (tr *transactionRepository) GetTransactionByIdempotencyKey(ctx context.Cont
 	return &result, nil
 }
 
-func (tr *transactionRepository) CreateTransaction(ctx context.Context, transaction models.Transaction) (*models.Transaction, error) {
-	_, err := tr.GetDB(ctx).
-		NewInsert().
-		Model(&transaction).
+// ListCustomerAccountTransactions returns the transactions of the account
+// created from from (inclusive) to to (exclusive), oldest first.
+func (tr *transactionRepository) ListCustomerAccountTransactions(
+	ctx context.Context, customerAccountID *uuid.UUID, from time.Time, to time.Time,
+) ([]models.Transaction, error) {
+	var result []models.Transaction
+
+	err := tr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("customer_account_id = ?", customerAccountID).
+		Where("created_at >= ?", from).
+		Where("created_at < ?", to).
+		Order("created_at", "id").
+		Scan(ctx)
+
+	return result, err
+}
+
+func (tr *transactionRepository) ListCustomerAccountsMissingBalanceAfter(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+
+	err := tr.GetDB(ctx).
+		NewSelect().
+		Model((*models.Transaction)(nil)).
+		Distinct().
+		Column("customer_account_id").
+		Where("balance_after IS NULL").
+		Order("customer_account_id").
+		Limit(limit).
+		Scan(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BackfillBalanceAfter recomputes the running balance of every transaction of
+// the account, following the order they were created in.
+func (tr *transactionRepository) BackfillBalanceAfter(ctx context.Context, customerAccountID *uuid.UUID) (int64, error) {
+	result, err := tr.GetDB(ctx).
+		NewRaw(`
+			UPDATE transactions t
+			SET balance_after = running.balance_after
+			FROM (
+				SELECT
+					id,
+					SUM(amount) OVER (ORDER BY created_at, id) AS balance_after
+				FROM transactions
+				WHERE customer_account_id = ?0
+			) running
+			WHERE t.id = running.id
+				AND t.customer_account_id = ?0
+				AND t.balance_after IS DISTINCT FROM running.balance_after
+		`, customerAccountID).
 		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
 
-	return &transaction, err
+	return result.RowsAffected()
 }
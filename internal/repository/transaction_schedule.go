@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type TransactionScheduleFilter struct {
+	CustomerAccountID *uuid.UUID
+	Status            *models.TransactionScheduleStatus
+}
+
+type TransactionScheduleRepository interface {
+	Base
+	CreateTransactionSchedule(ctx context.Context, schedule models.TransactionSchedule) (*models.TransactionSchedule, error)
+	GetTransactionSchedule(ctx context.Context, scheduleID *uuid.UUID) (*models.TransactionSchedule, error)
+	GetTransactionScheduleForUpdate(ctx context.Context, scheduleID *uuid.UUID) (*models.TransactionSchedule, error)
+	ListTransactionSchedules(ctx context.Context, filter TransactionScheduleFilter) ([]models.TransactionSchedule, error)
+	ClaimDueTransactionSchedules(ctx context.Context, limit int, lease time.Duration) ([]models.TransactionSchedule, error)
+	UpdateTransactionSchedule(ctx context.Context, schedule models.TransactionSchedule) error
+	CreateTransactionScheduleRun(ctx context.Context, run models.TransactionScheduleRun) (bool, error)
+	ListTransactionScheduleRuns(ctx context.Context, scheduleID *uuid.UUID) ([]models.TransactionScheduleRun, error)
+}
+
+type transactionScheduleRepository struct {
+	BaseRepo
+}
+
+func NewTransactionScheduleRepository(db bun.IDB) TransactionScheduleRepository {
+	repo := &transactionScheduleRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+func (tr *transactionScheduleRepository) CreateTransactionSchedule(
+	ctx context.Context, schedule models.TransactionSchedule,
+) (*models.TransactionSchedule, error) {
+	_, err := tr.GetDB(ctx).
+		NewInsert().
+		Model(&schedule).
+		Exec(ctx)
+
+	return &schedule, err
+}
+
+func (tr *transactionScheduleRepository) GetTransactionSchedule(
+	ctx context.Context, scheduleID *uuid.UUID,
+) (*models.TransactionSchedule, error) {
+	return tr.getTransactionSchedule(ctx, scheduleID, false)
+}
+
+// GetTransactionScheduleForUpdate locks the schedule until the end of the
+// database transaction, so its state can be changed without racing the worker.
+func (tr *transactionScheduleRepository) GetTransactionScheduleForUpdate(
+	ctx context.Context, scheduleID *uuid.UUID,
+) (*models.TransactionSchedule, error) {
+	return tr.getTransactionSchedule(ctx, scheduleID, true)
+}
+
+func (tr *transactionScheduleRepository) getTransactionSchedule(
+	ctx context.Context, scheduleID *uuid.UUID, forUpdate bool,
+) (*models.TransactionSchedule, error) {
+	var result models.TransactionSchedule
+
+	query := tr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("id = ?", scheduleID)
+
+	if forUpdate {
+		query = query.For("UPDATE")
+	}
+
+	err := query.Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (tr *transactionScheduleRepository) ListTransactionSchedules(
+	ctx context.Context, filter TransactionScheduleFilter,
+) ([]models.TransactionSchedule, error) {
+	var result []models.TransactionSchedule
+
+	query := tr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Order("created_at DESC")
+
+	if filter.CustomerAccountID != nil {
+		query = query.Where("customer_account_id = ?", filter.CustomerAccountID)
+	}
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	err := query.Scan(ctx)
+
+	return result, err
+}
+
+// ClaimDueTransactionSchedules leases the active schedules that are due by
+// pushing their next run into the future. A schedule whose worker dies mid-way
+// is picked up again once the lease ends.
+func (tr *transactionScheduleRepository) ClaimDueTransactionSchedules(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]models.TransactionSchedule, error) {
+	var result []models.TransactionSchedule
+
+	err := tr.GetDB(ctx).
+		NewRaw(`
+			UPDATE transaction_schedule
+			SET next_run_at = ?, updated_at = NOW()
+			WHERE id IN (
+				SELECT id
+				FROM transaction_schedule
+				WHERE status = ?
+				AND next_run_at <= NOW()
+				ORDER BY next_run_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		`, time.Now().Add(lease), models.TransactionScheduleActive, limit).
+		Scan(ctx, &result)
+
+	return result, err
+}
+
+func (tr *transactionScheduleRepository) UpdateTransactionSchedule(
+	ctx context.Context, schedule models.TransactionSchedule,
+) error {
+	_, err := tr.GetDB(ctx).
+		NewUpdate().
+		Model(&schedule).
+		Column("status", "next_occurrence", "next_run_at", "attempts", "last_error", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+// CreateTransactionScheduleRun records the outcome of an occurrence, unless
+// one was already recorded for it. It reports whether the run was created.
+func (tr *transactionScheduleRepository) CreateTransactionScheduleRun(
+	ctx context.Context, run models.TransactionScheduleRun,
+) (bool, error) {
+	result, err := tr.GetDB(ctx).
+		NewInsert().
+		Model(&run).
+		On("CONFLICT (transaction_schedule_id, occurrence) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return created == 1, nil
+}
+
+func (tr *transactionScheduleRepository) ListTransactionScheduleRuns(
+	ctx context.Context, scheduleID *uuid.UUID,
+) ([]models.TransactionScheduleRun, error) {
+	var result []models.TransactionScheduleRun
+
+	err := tr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("transaction_schedule_id = ?", scheduleID).
+		Order("occurrence").
+		Scan(ctx)
+
+	return result, err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// txHooks collects the after-commit hooks registered in one transaction or
+// savepoint.
+type txHooks struct {
+	mu          sync.Mutex
+	afterCommit []func(ctx context.Context)
+}
+
+func (h *txHooks) add(hooks ...func(ctx context.Context)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.afterCommit = append(h.afterCommit, hooks...)
+}
+
+// run calls every hook in registration order. A panicking hook is logged and
+// doesn't keep the others from running, since the transaction is already
+// committed.
+func (h *txHooks) run(ctx context.Context) {
+	h.mu.Lock()
+	hooks := h.afterCommit
+	h.afterCommit = nil
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Error().Interface("panic", r).Msg("after commit hook panicked")
+				}
+			}()
+
+			hook(ctx)
+		}()
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"math/rand/v2"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultTxMaxRetries = 3
+	txRetryBaseBackoff  = 10 * time.Millisecond
+	txRetryMaxBackoff   = time.Second
+)
+
+// TransactionMetrics counts the database transactions retried by
+// WithTransaction, published on /debug/vars as database_transactions.
+var TransactionMetrics = expvar.NewMap("database_transactions")
+
+type txConfig struct {
+	options    sql.TxOptions
+	maxRetries int
+}
+
+type TxOption func(*txConfig)
+
+// WithIsolation runs the transaction with the given isolation level, e.g.
+// sql.LevelRepeatableRead or sql.LevelSerializable. Read committed is used
+// otherwise.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(c *txConfig) {
+		c.options.Isolation = level
+	}
+}
+
+// ReadOnly runs the transaction in read-only mode.
+func ReadOnly() TxOption {
+	return func(c *txConfig) {
+		c.options.ReadOnly = true
+	}
+}
+
+// WithMaxRetries sets how many times the transaction is retried after a
+// serialization failure or a deadlock. Zero disables the retries.
+func WithMaxRetries(maxRetries int) TxOption {
+	return func(c *txConfig) {
+		c.maxRetries = maxRetries
+	}
+}
+
+func newTxConfig(opts []TxOption) txConfig {
+	config := txConfig{
+		maxRetries: defaultTxMaxRetries,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
+// waitTxRetry waits before retrying a transaction that failed with sqlState.
+func waitTxRetry(ctx context.Context, attempt int, sqlState string) error {
+	TransactionMetrics.Add("retries", 1)
+	TransactionMetrics.Add(txRetryMetric(sqlState), 1)
+
+	log.Warn().
+		Str("sqlstate", sqlState).
+		Int("attempt", attempt+1).
+		Msg("retrying database transaction")
+
+	return WaitRetryBackoff(ctx, attempt)
+}
+
+// WaitRetryBackoff sleeps a random duration up to an exponential backoff (full
+// jitter) for the attempt, counted from zero, so transactions that conflicted
+// don't retry in lockstep. It returns early with the error of ctx when it's
+// done.
+func WaitRetryBackoff(ctx context.Context, attempt int) error {
+	backoff := min(txRetryBaseBackoff<<attempt, txRetryMaxBackoff)
+	delay := rand.N(backoff) + 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func txRetryMetric(sqlState string) string {
+	if sqlState == deadlockDetectedCode {
+		return "deadlock_retries"
+	}
+
+	return "serialization_failure_retries"
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+type WebhookRepository interface {
+	Base
+	CreateWebhookSubscription(ctx context.Context, subscription models.WebhookSubscription) (*models.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, subscriptionID *uuid.UUID) (*models.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	ListWebhookSubscriptionsForEvent(ctx context.Context, eventType string) ([]models.WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, subscription models.WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID *uuid.UUID) (bool, error)
+	CreateWebhookDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error
+	ClaimDueWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, subscriptionID *uuid.UUID, deliveryID *uuid.UUID) (*models.WebhookDelivery, error)
+	ListWebhookDeliveries(
+		ctx context.Context, subscriptionID *uuid.UUID, status *models.WebhookDeliveryStatus,
+	) ([]models.WebhookDelivery, error)
+	UpdateWebhookDeliveryResult(ctx context.Context, delivery models.WebhookDelivery) error
+	CreateWebhookDeliveryAttempt(ctx context.Context, attempt models.WebhookDeliveryAttempt) error
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID *uuid.UUID) ([]models.WebhookDeliveryAttempt, error)
+}
+
+type webhookRepository struct {
+	BaseRepo
+}
+
+func NewWebhookRepository(db bun.IDB) WebhookRepository {
+	repo := &webhookRepository{}
+	repo.SetDB(db)
+
+	return repo
+}
+
+func (wr *webhookRepository) CreateWebhookSubscription(
+	ctx context.Context, subscription models.WebhookSubscription,
+) (*models.WebhookSubscription, error) {
+	_, err := wr.GetDB(ctx).
+		NewInsert().
+		Model(&subscription).
+		Exec(ctx)
+
+	return &subscription, err
+}
+
+func (wr *webhookRepository) GetWebhookSubscription(
+	ctx context.Context, subscriptionID *uuid.UUID,
+) (*models.WebhookSubscription, error) {
+	var result models.WebhookSubscription
+
+	err := wr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("id = ?", subscriptionID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (wr *webhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var result []models.WebhookSubscription
+
+	err := wr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Order("created_at").
+		Scan(ctx)
+
+	return result, err
+}
+
+func (wr *webhookRepository) ListWebhookSubscriptionsForEvent(
+	ctx context.Context, eventType string,
+) ([]models.WebhookSubscription, error) {
+	var result []models.WebhookSubscription
+
+	err := wr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("enabled").
+		Where("? = ANY(event_types)", eventType).
+		Scan(ctx)
+
+	return result, err
+}
+
+func (wr *webhookRepository) UpdateWebhookSubscription(ctx context.Context, subscription models.WebhookSubscription) error {
+	_, err := wr.GetDB(ctx).
+		NewUpdate().
+		Model(&subscription).
+		Column("url", "event_types", "secret", "enabled", "updated_at").
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+func (wr *webhookRepository) DeleteWebhookSubscription(ctx context.Context, subscriptionID *uuid.UUID) (bool, error) {
+	result, err := wr.GetDB(ctx).
+		NewDelete().
+		Model((*models.WebhookSubscription)(nil)).
+		Where("id = ?", subscriptionID).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted == 1, nil
+}
+
+// CreateWebhookDeliveries enqueues the deliveries, skipping those of an event
+// already enqueued for the same subscription.
+func (wr *webhookRepository) CreateWebhookDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	_, err := wr.GetDB(ctx).
+		NewInsert().
+		Model(&deliveries).
+		On("CONFLICT (webhook_subscription_id, event_id) DO NOTHING").
+		Exec(ctx)
+
+	return err
+}
+
+// ClaimDueWebhookDeliveries leases the pending deliveries that are due, of
+// enabled subscriptions, by pushing their next attempt lease into the future.
+// A delivery whose sender dies mid-way is picked up again once the lease ends.
+func (wr *webhookRepository) ClaimDueWebhookDeliveries(
+	ctx context.Context, limit int, lease time.Duration,
+) ([]models.WebhookDelivery, error) {
+	var result []models.WebhookDelivery
+
+	err := wr.GetDB(ctx).
+		NewRaw(`
+			UPDATE webhook_delivery
+			SET next_attempt_at = ?, updated_at = NOW()
+			WHERE id IN (
+				SELECT d.id
+				FROM webhook_delivery d
+				JOIN webhook_subscription s ON s.id = d.webhook_subscription_id
+				WHERE d.status = ?
+				AND d.next_attempt_at <= NOW()
+				AND s.enabled
+				ORDER BY d.next_attempt_at
+				LIMIT ?
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING *
+		`, time.Now().Add(lease), models.WebhookDeliveryPending, limit).
+		Scan(ctx, &result)
+
+	return result, err
+}
+
+func (wr *webhookRepository) GetWebhookDelivery(
+	ctx context.Context, subscriptionID *uuid.UUID, deliveryID *uuid.UUID,
+) (*models.WebhookDelivery, error) {
+	var result models.WebhookDelivery
+
+	err := wr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("id = ?", deliveryID).
+		Where("webhook_subscription_id = ?", subscriptionID).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (wr *webhookRepository) ListWebhookDeliveries(
+	ctx context.Context, subscriptionID *uuid.UUID, status *models.WebhookDeliveryStatus,
+) ([]models.WebhookDelivery, error) {
+	var result []models.WebhookDelivery
+
+	query := wr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("webhook_subscription_id = ?", subscriptionID).
+		Order("created_at DESC")
+
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	err := query.Scan(ctx)
+
+	return result, err
+}
+
+func (wr *webhookRepository) UpdateWebhookDeliveryResult(ctx context.Context, delivery models.WebhookDelivery) error {
+	_, err := wr.GetDB(ctx).
+		NewUpdate().
+		Model(&delivery).
+		Column(
+			"status",
+			"attempts",
+			"next_attempt_at",
+			"last_attempt_at",
+			"response_status",
+			"last_error",
+			"updated_at",
+		).
+		WherePK().
+		Exec(ctx)
+
+	return err
+}
+
+func (wr *webhookRepository) CreateWebhookDeliveryAttempt(ctx context.Context, attempt models.WebhookDeliveryAttempt) error {
+	_, err := wr.GetDB(ctx).
+		NewInsert().
+		Model(&attempt).
+		Exec(ctx)
+
+	return err
+}
+
+func (wr *webhookRepository) ListWebhookDeliveryAttempts(
+	ctx context.Context, deliveryID *uuid.UUID,
+) ([]models.WebhookDeliveryAttempt, error) {
+	var result []models.WebhookDeliveryAttempt
+
+	err := wr.GetDB(ctx).
+		NewSelect().
+		Model(&result).
+		Where("webhook_delivery_id = ?", deliveryID).
+		Order("attempt").
+		Scan(ctx)
+
+	return result, err
+}
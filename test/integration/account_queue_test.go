@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestAccountQueue(t *testing.T) {
+	t.Run("concurrent transactions on the same account should all be applied", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		app := newTransactionsApp(transactions.Options{AccountQueueSize: 100, AccountQueueMaxBatch: 10})
+
+		const requests = 50
+
+		statuses := postConcurrently(t, requests, func() int {
+			return postTransaction(t, app, map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         1.00,
+			})
+		})
+
+		for _, status := range statuses {
+			assert.Equal(t, http.StatusOK, status)
+		}
+
+		assert.Equal(t, requests, CountTransactionsForAccount(t, accountID))
+		AssertBalanceEquals(t, accountID, requests*100)
+
+		version := GetBalanceVersion(t, accountID)
+		assert.Positive(t, version)
+		assert.LessOrEqual(t, version, int64(requests))
+	})
+
+	t.Run("transactions refused in a micro-batch should fail alone", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		app := newTransactionsApp(transactions.Options{AccountQueueSize: 100})
+
+		require.Equal(t, http.StatusOK, postTransaction(t, app, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+		}))
+
+		statuses := postConcurrently(t, 5, func() int {
+			return postTransaction(t, app, map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.Withdrawal,
+				"amount":         30.00,
+			})
+		})
+
+		counts := map[int]int{}
+		for _, status := range statuses {
+			counts[status]++
+		}
+
+		assert.Equal(t, map[int]int{http.StatusOK: 3, http.StatusBadRequest: 2}, counts)
+		assert.Equal(t, 4, CountTransactionsForAccount(t, accountID))
+		AssertBalanceEquals(t, accountID, 1000)
+	})
+
+	t.Run("full queue should return service unavailable", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		const (
+			queueSize = 2
+			maxBatch  = 1
+			requests  = 10
+		)
+
+		app := newTransactionsApp(transactions.Options{AccountQueueSize: queueSize, AccountQueueMaxBatch: maxBatch})
+
+		lock, err := DB.BeginTx(context.Background(), nil)
+		require.NoError(t, err)
+		defer lock.Rollback() //nolint: errcheck
+
+		_, err = lock.ExecContext(context.Background(), "SELECT 1 FROM balance WHERE customer_account_id = ? FOR UPDATE", accountID)
+		require.NoError(t, err)
+
+		results := make(chan int, requests)
+
+		for range requests {
+			go func() {
+				results <- postTransaction(t, app, map[string]any{
+					"account_id":     accountID,
+					"operation_type": models.CreditVoucher,
+					"amount":         1.00,
+				})
+			}()
+		}
+
+		// While the balance is locked the worker holds at most maxBatch
+		// transactions and the queue queueSize, so the others are rejected.
+		counts := map[int]int{}
+		for range requests - queueSize - maxBatch {
+			select {
+			case status := <-results:
+				counts[status]++
+			case <-time.After(10 * time.Second):
+				t.Fatal("expected requests to be rejected while the queue is full")
+			}
+		}
+
+		require.NoError(t, lock.Rollback())
+
+		for range queueSize + maxBatch {
+			counts[<-results]++
+		}
+
+		assert.GreaterOrEqual(t, counts[http.StatusServiceUnavailable], requests-queueSize-maxBatch)
+		assert.Equal(t, requests, counts[http.StatusOK]+counts[http.StatusServiceUnavailable])
+
+		assert.Equal(t, counts[http.StatusOK], CountTransactionsForAccount(t, accountID))
+		AssertBalanceEquals(t, accountID, int64(counts[http.StatusOK]*100))
+	})
+
+	t.Run("caller giving up should abandon a queued transaction but wait for a started one", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		customerAccountID := uuid.Must(uuid.FromString(accountID))
+		service := newTransactionsService(DB, transactions.Options{AccountQueueSize: 10, AccountQueueMaxBatch: 1})
+
+		lock, err := DB.BeginTx(context.Background(), nil)
+		require.NoError(t, err)
+		defer lock.Rollback() //nolint: errcheck
+
+		_, err = lock.ExecContext(context.Background(), "SELECT 1 FROM balance WHERE customer_account_id = ? FOR UPDATE", accountID)
+		require.NoError(t, err)
+
+		create := func(ctx context.Context) <-chan error {
+			result := make(chan error, 1)
+
+			go func() {
+				_, err := service.CreateTransaction(ctx, transactions.CreateTransactionRequest{
+					CustomerAccountID: &customerAccountID,
+					OperationType:     models.CreditVoucher,
+					Amount:            1.00,
+				})
+				result <- err
+			}()
+
+			return result
+		}
+
+		startedCtx, cancelStarted := context.WithCancel(context.Background())
+		started := create(startedCtx)
+
+		// Gives the worker time to take the first transaction, which then
+		// waits on the balance lock while the second one stays queued.
+		time.Sleep(200 * time.Millisecond)
+
+		queuedCtx, cancelQueued := context.WithCancel(context.Background())
+		queued := create(queuedCtx)
+
+		time.Sleep(200 * time.Millisecond)
+		cancelQueued()
+		cancelStarted()
+
+		select {
+		case err := <-queued:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the queued transaction to be abandoned")
+		}
+
+		select {
+		case <-started:
+			t.Fatal("expected the started transaction to wait for its outcome")
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		require.NoError(t, lock.Rollback())
+
+		select {
+		case err := <-started:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the started transaction to finish")
+		}
+
+		assert.Equal(t, 1, CountTransactionsForAccount(t, accountID))
+		AssertBalanceEquals(t, accountID, 100)
+	})
+
+	t.Run("transactions joining a database transaction should bypass the queue", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		app := newTransactionsApp(transactions.Options{AccountQueueSize: 100})
+
+		jsonBody := map[string]any{
+			"mode": "atomic",
+			"items": []map[string]any{
+				{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 10.00},
+				{"account_id": accountID, "operation_type": models.NormalPurchase, "amount": 4.00},
+			},
+		}
+
+		status := postJSON(t, app, "/transactions/batch", jsonBody)
+		assert.Equal(t, http.StatusOK, status)
+
+		AssertBalanceEquals(t, accountID, 600)
+	})
+}
+
+func postConcurrently(t *testing.T, requests int, post func() int) []int {
+	t.Helper()
+
+	statuses := make([]int, requests)
+
+	var wg sync.WaitGroup
+	for index := range requests {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			statuses[index] = post()
+		}()
+	}
+
+	wg.Wait()
+
+	return statuses
+}
@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/activity"
+	"github.com/tiagovaldrich/accounts-api/internal/config"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+const streamEventTimeout = 5 * time.Second
+
+func newActivityService(bunDB *bun.DB) activity.Servicer {
+	notifier := events.NewNotifier(bunDB)
+	if err := notifier.Start(context.Background()); err != nil {
+		panic(fmt.Sprintf("failed to start the notifier: %v", err))
+	}
+
+	return activity.NewService(
+		repository.NewCustomerAccountRepository(bunDB),
+		repository.NewOutboxRepository(bunDB),
+		notifier,
+	)
+}
+
+// newActivityServer starts an instance of the API serving only the account
+// event streams, with its own notifier, and returns its base URL. Streams
+// can't be read through App.Test, which waits for the response to end.
+func newActivityServer(t *testing.T) string {
+	t.Helper()
+
+	router := config.NewRouter(repository.NewIdempotentRequestRepository(DB), repository.NewAuditLogRepository(DB), config.HTTPConfig{})
+	activity.NewHTTPHandler(router.GetApp(), newActivityService(DB))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = router.GetApp().Listener(listener)
+	}()
+
+	t.Cleanup(func() {
+		_ = router.GetApp().ShutdownWithTimeout(time.Second)
+	})
+
+	return "http://" + listener.Addr().String()
+}
+
+type streamedEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+type eventStream struct {
+	events chan streamedEvent
+}
+
+// openEventStream connects to the event stream of the account, resuming after
+// lastEventID when it's set. The connection is closed when the test ends.
+func openEventStream(t *testing.T, baseURL string, accountID string, lastEventID string) *eventStream {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/accounts/%s/events", baseURL, accountID), nil)
+	require.NoError(t, err)
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	stream := &eventStream{events: make(chan streamedEvent, 100)}
+
+	go func() {
+		defer resp.Body.Close()
+		defer close(stream.events)
+
+		var event streamedEvent
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case line == "":
+				if event.ID != "" {
+					stream.events <- event
+				}
+
+				event = streamedEvent{}
+			case strings.HasPrefix(line, "id: "):
+				event.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	return stream
+}
+
+func (s *eventStream) Next(t *testing.T) streamedEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-s.events:
+		require.True(t, ok, "stream closed")
+
+		return event
+	case <-time.After(streamEventTimeout):
+		t.Fatal("timed out waiting for a stream event")
+
+		return streamedEvent{}
+	}
+}
+
+// AssertNoEvent checks nothing is streamed for a while.
+func (s *eventStream) AssertNoEvent(t *testing.T, wait time.Duration) {
+	t.Helper()
+
+	select {
+	case event := <-s.events:
+		t.Fatalf("unexpected stream event %s %s", event.ID, event.Event)
+	case <-time.After(wait):
+	}
+}
+
+func parseStreamEventID(t *testing.T, event streamedEvent) int64 {
+	t.Helper()
+
+	id, err := strconv.ParseInt(event.ID, 10, 64)
+	require.NoError(t, err)
+
+	return id
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestAccountEventStream(t *testing.T) {
+	t.Run("should refuse streams of invalid or unknown accounts", func(t *testing.T) {
+		CleanupTables(t)
+
+		resp, _ := GET(t, "/accounts/invalid/events")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = GET(t, "/accounts/00000000-0000-0000-0000-000000000000/events")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		req := httptest.NewRequest("GET", fmt.Sprintf("/accounts/%s/events", accountID), nil)
+		req.Header.Set("Last-Event-ID", "abc")
+
+		resp, err := App.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should stream transaction and balance events of the account as they commit", func(t *testing.T) {
+		CleanupTables(t)
+
+		baseURL := newActivityServer(t)
+		accountID := createTestAccount(t, TestDocument)
+		otherAccountID := createTestAccount(t, TestSecondDocument)
+
+		stream := openEventStream(t, baseURL, accountID, "")
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     otherAccountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         50.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, body := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var transaction map[string]any
+		ParseJSON(t, body, &transaction)
+
+		transactionCreated := stream.Next(t)
+		assert.Equal(t, events.TransactionCreated, transactionCreated.Event)
+
+		var envelope events.Event
+		require.NoError(t, json.Unmarshal([]byte(transactionCreated.Data), &envelope))
+		assert.Equal(t, accountID, envelope.AccountID.String())
+		assert.Equal(t, 1, envelope.Version)
+
+		var payload events.TransactionCreatedPayload
+		require.NoError(t, json.Unmarshal(envelope.Data, &payload))
+		assert.Equal(t, transaction["id"], payload.TransactionID.String())
+
+		balanceChanged := stream.Next(t)
+		assert.Equal(t, events.BalanceChanged, balanceChanged.Event)
+
+		require.NoError(t, json.Unmarshal([]byte(balanceChanged.Data), &envelope))
+
+		var balance events.BalanceChangedPayload
+		require.NoError(t, json.Unmarshal(envelope.Data, &balance))
+		assert.Equal(t, 100.00, balance.Balance)
+
+		assert.Greater(t, parseStreamEventID(t, balanceChanged), parseStreamEventID(t, transactionCreated))
+
+		stream.AssertNoEvent(t, 200*time.Millisecond)
+	})
+
+	t.Run("should only stream events committed after connecting without Last-Event-ID", func(t *testing.T) {
+		CleanupTables(t)
+
+		baseURL := newActivityServer(t)
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         10.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		stream := openEventStream(t, baseURL, accountID, "")
+		stream.AssertNoEvent(t, 200*time.Millisecond)
+
+		resp, _ = POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         20.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, events.TransactionCreated, stream.Next(t).Event)
+	})
+
+	t.Run("should resume after Last-Event-ID", func(t *testing.T) {
+		CleanupTables(t)
+
+		baseURL := newActivityServer(t)
+		accountID := createTestAccount(t, TestDocument)
+
+		for _, amount := range []float64{10.00, 20.00} {
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         amount,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		outboxEvents := GetOutboxEvents(t, accountID)
+		require.Len(t, outboxEvents, 5)
+
+		// Resume after the transaction.created of the first transaction.
+		stream := openEventStream(t, baseURL, accountID, strconv.FormatInt(outboxEvents[1].ID, 10))
+
+		for _, expected := range outboxEvents[2:] {
+			event := stream.Next(t)
+			assert.Equal(t, strconv.FormatInt(expected.ID, 10), event.ID)
+			assert.Equal(t, expected.EventType, event.Event)
+		}
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         30.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, events.TransactionCreated, stream.Next(t).Event)
+		assert.Equal(t, events.BalanceChanged, stream.Next(t).Event)
+	})
+
+	t.Run("should fan events out to streams on every instance", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		streams := []*eventStream{
+			openEventStream(t, newActivityServer(t), accountID, ""),
+			openEventStream(t, newActivityServer(t), accountID, ""),
+			openEventStream(t, newActivityServer(t), accountID, ""),
+		}
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         10.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var eventIDs []string
+		for _, stream := range streams {
+			event := stream.Next(t)
+			assert.Equal(t, events.TransactionCreated, event.Event)
+
+			eventIDs = append(eventIDs, event.ID)
+		}
+
+		assert.Equal(t, eventIDs[0], eventIDs[1])
+		assert.Equal(t, eventIDs[0], eventIDs[2])
+	})
+}
@@ -0,0 +1,37 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func GetAuditLogs(t *testing.T, entityType string) []models.AuditLog {
+	t.Helper()
+
+	var auditLogs []models.AuditLog
+	err := DB.NewSelect().
+		Model(&auditLogs).
+		Where("entity_type = ?", entityType).
+		Order("created_at").
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return auditLogs
+}
+
+func GetRequestAuditLogs(t *testing.T) []models.AuditLog {
+	t.Helper()
+
+	var auditLogs []models.AuditLog
+	err := DB.NewSelect().
+		Model(&auditLogs).
+		Where("entity_type IS NULL").
+		Order("created_at").
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return auditLogs
+}
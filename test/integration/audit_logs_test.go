@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/audit"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
+)
+
+func TestAuditLogs(t *testing.T) {
+	t.Run("POST /accounts should record who created the account", func(t *testing.T) {
+		CleanupTables(t)
+
+		resp, body := sendJSON(t, "POST", "/accounts", map[string]any{"document_number": TestDocument}, map[string]string{
+			middleware.ActorHeader: "backoffice:jane",
+			fiber.HeaderUserAgent:  "backoffice/1.0",
+			fiber.HeaderXRequestID: "request-1",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "request-1", resp.Header.Get(fiber.HeaderXRequestID))
+
+		var account map[string]any
+		ParseJSON(t, body, &account)
+
+		auditLogs := GetAuditLogs(t, audit.EntityAccount)
+		require.Len(t, auditLogs, 1)
+
+		entry := auditLogs[0]
+		assert.Equal(t, audit.ActionCreate, entry.Action)
+		assert.Equal(t, account["account_id"], entry.EntityID.String())
+		assert.Equal(t, account["account_id"], entry.CustomerAccountID.String())
+		assert.Equal(t, "backoffice:jane", entry.Actor)
+		assert.Equal(t, "backoffice/1.0", *entry.UserAgent)
+		assert.Equal(t, "request-1", *entry.RequestID)
+		assert.Equal(t, "POST", *entry.Method)
+		assert.Equal(t, "/accounts", *entry.Route)
+		assert.NotNil(t, entry.ClientIP)
+		assert.Nil(t, entry.Before)
+
+		var after map[string]any
+		require.NoError(t, json.Unmarshal(entry.After, &after))
+		assert.Equal(t, TestDocument, after["document_number"])
+	})
+
+	t.Run("POST /transactions should record the transaction with its account", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, body := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get(fiber.HeaderXRequestID))
+
+		var transaction map[string]any
+		ParseJSON(t, body, &transaction)
+
+		auditLogs := GetAuditLogs(t, audit.EntityTransaction)
+		require.Len(t, auditLogs, 1)
+
+		entry := auditLogs[0]
+		assert.Equal(t, transaction["id"], entry.EntityID.String())
+		assert.Equal(t, accountID, entry.CustomerAccountID.String())
+		assert.Equal(t, "anonymous", entry.Actor)
+		assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), *entry.RequestID)
+
+		var after map[string]any
+		require.NoError(t, json.Unmarshal(entry.After, &after))
+		assert.Equal(t, 100.0, after["amount"])
+		assert.Equal(t, 100.0, after["balance_after"])
+
+		assert.Empty(t, GetRequestAuditLogs(t))
+	})
+
+	t.Run("rolled back atomic batch should record nothing", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, _ := POST(t, "/transactions/batch", map[string]any{
+			"mode": "atomic",
+			"items": []map[string]any{
+				{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00},
+				{"account_id": accountID, "operation_type": models.Withdrawal, "amount": 500.00},
+			},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		assert.Empty(t, GetAuditLogs(t, audit.EntityTransaction))
+		assert.Empty(t, GetRequestAuditLogs(t))
+	})
+
+	t.Run("mutating route without entity audit should record the request", func(t *testing.T) {
+		CleanupTables(t)
+
+		createTestWebhook(t, "https://example.com/webhooks", []string{"transaction.created"}, true)
+
+		auditLogs := GetRequestAuditLogs(t)
+		require.Len(t, auditLogs, 1)
+		assert.Equal(t, audit.ActionRequest, auditLogs[0].Action)
+		assert.Equal(t, "/webhooks", *auditLogs[0].Route)
+		assert.Nil(t, auditLogs[0].EntityID)
+	})
+
+	t.Run("replayed request should not be recorded again", func(t *testing.T) {
+		CleanupTables(t)
+
+		payload := map[string]any{"document_number": TestDocument}
+
+		resp, _ := POSTWithIdempotencyKey(t, "/accounts", payload, "audit-replay")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		replayResp, _ := POSTWithIdempotencyKey(t, "/accounts", payload, "audit-replay")
+		require.Equal(t, http.StatusOK, replayResp.StatusCode)
+		require.Equal(t, "true", replayResp.Header.Get(middleware.IdempotentReplayedHeader))
+
+		assert.Len(t, GetAuditLogs(t, audit.EntityAccount), 1)
+		assert.Empty(t, GetRequestAuditLogs(t))
+	})
+
+	t.Run("entries should not be updated or deleted", func(t *testing.T) {
+		CleanupTables(t)
+
+		createTestAccount(t, TestDocument)
+
+		_, err := DB.NewUpdate().
+			Model((*models.AuditLog)(nil)).
+			Set("actor = ?", "someone else").
+			Where("TRUE").
+			Exec(context.Background())
+		assert.ErrorContains(t, err, "append-only")
+
+		_, err = DB.NewDelete().
+			Model((*models.AuditLog)(nil)).
+			Where("TRUE").
+			Exec(context.Background())
+		assert.ErrorContains(t, err, "append-only")
+
+		assert.Len(t, GetAuditLogs(t, audit.EntityAccount), 1)
+	})
+
+	t.Run("GET /audit-logs", func(t *testing.T) {
+		t.Run("should filter by entity", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			createTestAccount(t, TestSecondDocument)
+
+			resp, body := GET(t, "/audit-logs?entity_type=account&entity_id="+accountID)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response []map[string]any
+			ParseJSON(t, body, &response)
+			require.Len(t, response, 1)
+			assert.Equal(t, accountID, response[0]["entity_id"])
+			assert.Equal(t, "account", response[0]["entity_type"])
+			assert.Equal(t, TestDocument, response[0]["after"].(map[string]any)["document_number"])
+		})
+
+		t.Run("should filter by account and time", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         10.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			resp, body := GET(t, "/audit-logs?account_id="+accountID)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response []map[string]any
+			ParseJSON(t, body, &response)
+			require.Len(t, response, 2)
+			assert.Equal(t, "transaction", response[0]["entity_type"])
+			assert.Equal(t, "account", response[1]["entity_type"])
+
+			future := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+			resp, body = GET(t, "/audit-logs?account_id="+accountID+"&from="+future)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var futureResponse []map[string]any
+			ParseJSON(t, body, &futureResponse)
+			assert.Empty(t, futureResponse)
+
+			resp, body = GET(t, "/audit-logs?account_id="+accountID+"&limit=1")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var limitedResponse []map[string]any
+			ParseJSON(t, body, &limitedResponse)
+			assert.Len(t, limitedResponse, 1)
+		})
+
+		t.Run("with invalid query parameters should return bad request", func(t *testing.T) {
+			for _, query := range []string{"entity_id=invalid", "from=yesterday", "limit=0", "limit=1000"} {
+				resp, _ := GET(t, "/audit-logs?"+query)
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+			}
+		})
+	})
+}
@@ -3,6 +3,7 @@ package integration
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/stretchr/testify/assert"
@@ -50,3 +51,67 @@ func GetBalance(t *testing.T, accountID string) int64 {
 	require.NoError(t, err, "balance for account %s should exist", accountID)
 	return balance.Balance
 }
+
+func SetTransactionsCreatedAt(t *testing.T, accountID string, operationType models.OperationType, createdAt time.Time) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Model((*models.Transaction)(nil)).
+		Set("created_at = ?", createdAt).
+		Where("customer_account_id = ?", accountID).
+		Where("operation_type = ?", operationType).
+		Exec(context.Background())
+
+	require.NoError(t, err)
+}
+
+func GetBalanceSnapshot(t *testing.T, accountID string, snapshotAt time.Time) models.BalanceSnapshot {
+	t.Helper()
+
+	var snapshot models.BalanceSnapshot
+	err := DB.NewSelect().
+		Model(&snapshot).
+		Where("customer_account_id = ?", accountID).
+		Where("snapshot_at = ?", snapshotAt).
+		Scan(context.Background())
+
+	require.NoError(t, err, "balance snapshot for account %s at %s should exist", accountID, snapshotAt)
+	return snapshot
+}
+
+func SetBalance(t *testing.T, accountID string, balance int64) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Model((*models.Balance)(nil)).
+		Set("balance = ?", balance).
+		Where("customer_account_id = ?", accountID).
+		Exec(context.Background())
+
+	require.NoError(t, err)
+}
+
+func DeleteBalance(t *testing.T, accountID string) {
+	t.Helper()
+
+	_, err := DB.NewDelete().
+		Model((*models.Balance)(nil)).
+		Where("customer_account_id = ?", accountID).
+		Exec(context.Background())
+
+	require.NoError(t, err)
+}
+
+func GetBalanceAdjustments(t *testing.T, accountID string) []models.BalanceAdjustment {
+	t.Helper()
+
+	var adjustments []models.BalanceAdjustment
+	err := DB.NewSelect().
+		Model(&adjustments).
+		Where("customer_account_id = ?", accountID).
+		Order("created_at").
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return adjustments
+}
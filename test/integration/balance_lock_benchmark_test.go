@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+// BenchmarkBalanceLockStrategies compares the throughput of both balance lock
+// strategies with every goroutine posting to the same hot account:
+//
+//	go test ./test/integration -run '^$' -bench BalanceLockStrategies -cpu 1,8,32
+func BenchmarkBalanceLockStrategies(b *testing.B) {
+	strategies := []transactions.Options{
+		{BalanceLockStrategy: transactions.PessimisticBalanceLock},
+		{BalanceLockStrategy: transactions.OptimisticBalanceLock, OptimisticMaxRetries: 5},
+	}
+
+	for _, options := range strategies {
+		b.Run(string(options.BalanceLockStrategy), func(b *testing.B) {
+			CleanupTables(b)
+
+			accountID := createTestAccount(b, TestDocument)
+			app := newTransactionsApp(options)
+
+			payload := map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         1.00,
+			}
+
+			var conflicts atomic.Int64
+
+			b.ResetTimer()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					status := postTransaction(b, app, payload)
+
+					switch status {
+					case http.StatusOK:
+					case http.StatusConflict:
+						conflicts.Add(1)
+					default:
+						b.Errorf("unexpected status %d", status)
+					}
+				}
+			})
+
+			b.ReportMetric(float64(conflicts.Load())/float64(b.N), "conflicts/op")
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/config"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+// newTransactionsApp serves only the transactions routes, backed by a service
+// built with options.
+func newTransactionsApp(options transactions.Options) *fiber.App {
+	router := config.NewRouter(repository.NewIdempotentRequestRepository(DB), repository.NewAuditLogRepository(DB), config.HTTPConfig{})
+	transactions.NewHTTPHandler(router.GetApp(), newTransactionsService(DB, options))
+
+	return router.GetApp()
+}
+
+func postTransaction(tb testing.TB, app *fiber.App, body any) int {
+	tb.Helper()
+
+	return postJSON(tb, app, "/transactions", body)
+}
+
+func postJSON(tb testing.TB, app *fiber.App, path string, body any) int {
+	tb.Helper()
+
+	jsonBody, err := json.Marshal(body)
+	require.NoError(tb, err)
+
+	req := httptest.NewRequest("POST", path, bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	require.NoError(tb, err)
+	resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func GetBalanceVersion(t *testing.T, accountID string) int64 {
+	t.Helper()
+
+	var balance models.Balance
+	err := DB.NewSelect().
+		Model(&balance).
+		Where("customer_account_id = ?", accountID).
+		Scan(context.Background())
+
+	require.NoError(t, err, "balance for account %s should exist", accountID)
+	return balance.Version
+}
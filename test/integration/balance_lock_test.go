@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestBalanceLockStrategies(t *testing.T) {
+	t.Run("POST /transactions", func(t *testing.T) {
+		t.Run("with pessimistic strategy should bump the balance version", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         10.00,
+			})
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			assert.Equal(t, int64(1), GetBalanceVersion(t, accountID))
+		})
+
+		t.Run("with optimistic strategy concurrent transactions should all be applied", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			app := newTransactionsApp(transactions.Options{
+				BalanceLockStrategy:  transactions.OptimisticBalanceLock,
+				OptimisticMaxRetries: 100,
+			})
+
+			const requests = 20
+
+			var wg sync.WaitGroup
+			statuses := make([]int, requests)
+
+			for i := range requests {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					statuses[i] = postTransaction(t, app, map[string]any{
+						"account_id":     accountID,
+						"operation_type": models.CreditVoucher,
+						"amount":         10.00,
+					})
+				}()
+			}
+
+			wg.Wait()
+
+			for _, status := range statuses {
+				assert.Equal(t, http.StatusOK, status)
+			}
+
+			AssertBalanceEquals(t, accountID, requests*1000)
+			assert.Equal(t, int64(requests), GetBalanceVersion(t, accountID))
+			assert.Equal(t, requests, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("with optimistic strategy and no retries conflicts should return conflict without partial writes", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			app := newTransactionsApp(transactions.Options{
+				BalanceLockStrategy:  transactions.OptimisticBalanceLock,
+				OptimisticMaxRetries: 0,
+			})
+
+			const requests = 20
+
+			var wg sync.WaitGroup
+			statuses := make([]int, requests)
+
+			for i := range requests {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					statuses[i] = postTransaction(t, app, map[string]any{
+						"account_id":     accountID,
+						"operation_type": models.CreditVoucher,
+						"amount":         10.00,
+					})
+				}()
+			}
+
+			wg.Wait()
+
+			succeeded := 0
+			for _, status := range statuses {
+				assert.Contains(t, []int{http.StatusOK, http.StatusConflict}, status)
+
+				if status == http.StatusOK {
+					succeeded++
+				}
+			}
+
+			AssertBalanceEquals(t, accountID, int64(succeeded)*1000)
+			assert.Equal(t, succeeded, CountTransactionsForAccount(t, accountID))
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestBalanceRebuild(t *testing.T) {
+	createCredit := func(t *testing.T, accountID string, amount float64) {
+		t.Helper()
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         amount,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	t.Run("with balances matching the transactions should report no diffs", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		createCredit(t, accountID, 100.00)
+
+		result, err := newReconciliationService().RebuildBalances(context.Background(), reconciliation.RebuildBalancesRequest{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, result.AccountsChecked)
+		assert.Empty(t, result.Diffs)
+		assert.False(t, result.Applied)
+	})
+
+	t.Run("dry run should report the diff without changing the balance", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		createCredit(t, accountID, 100.00)
+		SetBalance(t, accountID, 15000)
+
+		result, err := newReconciliationService().RebuildBalances(context.Background(), reconciliation.RebuildBalancesRequest{})
+		require.NoError(t, err)
+
+		require.Len(t, result.Diffs, 1)
+		diff := result.Diffs[0]
+		assert.Equal(t, accountID, diff.CustomerAccountID.String())
+		require.NotNil(t, diff.Balance)
+		assert.Equal(t, int64(15000), *diff.Balance)
+		assert.Equal(t, int64(10000), diff.RebuiltBalance)
+		assert.Equal(t, int64(-5000), diff.Difference)
+		assert.Equal(t, reconciliation.BalanceDiffPending, diff.Status)
+		assert.Nil(t, result.RebuildID)
+
+		AssertBalanceEquals(t, accountID, 15000)
+		assert.Empty(t, GetBalanceAdjustments(t, accountID))
+	})
+
+	t.Run("apply should correct the balance with an audited adjustment", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		createCredit(t, accountID, 100.00)
+		SetBalance(t, accountID, 15000)
+
+		secondAccountID := createTestAccount(t, TestSecondDocument)
+		createCredit(t, secondAccountID, 20.00)
+
+		result, err := newReconciliationService().RebuildBalances(context.Background(), reconciliation.RebuildBalancesRequest{
+			Apply:    true,
+			Operator: "ops",
+			Reason:   "corrupted balance",
+		})
+		require.NoError(t, err)
+
+		assert.True(t, result.Applied)
+		require.NotNil(t, result.RebuildID)
+		assert.Equal(t, 2, result.AccountsChecked)
+		assert.Equal(t, 1, result.Adjusted())
+		require.Len(t, result.Diffs, 1)
+		assert.Equal(t, reconciliation.BalanceDiffApplied, result.Diffs[0].Status)
+
+		AssertBalanceEquals(t, accountID, 10000)
+		AssertBalanceEquals(t, secondAccountID, 2000)
+
+		adjustments := GetBalanceAdjustments(t, accountID)
+		require.Len(t, adjustments, 1)
+		assert.Equal(t, result.RebuildID, adjustments[0].BalanceRebuildID)
+		assert.Equal(t, result.Diffs[0].AdjustmentID, adjustments[0].ID)
+		require.NotNil(t, adjustments[0].PreviousBalance)
+		assert.Equal(t, int64(15000), *adjustments[0].PreviousBalance)
+		assert.Equal(t, int64(10000), adjustments[0].RebuiltBalance)
+		assert.Equal(t, int64(-5000), adjustments[0].Adjustment)
+
+		var rebuild models.BalanceRebuild
+		err = DB.NewSelect().Model(&rebuild).Where("id = ?", result.RebuildID).Scan(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "ops", rebuild.Operator)
+		assert.Equal(t, "corrupted balance", rebuild.Reason)
+		assert.Equal(t, 1, rebuild.AdjustmentsCount)
+		assert.NotNil(t, rebuild.FinishedAt)
+
+		outboxEvents := GetOutboxEvents(t, accountID)
+		balanceChanged := outboxEvents[len(outboxEvents)-1]
+		assert.Equal(t, events.BalanceChanged, balanceChanged.EventType)
+
+		payload := ParseOutboxPayload(t, balanceChanged)
+		assert.Equal(t, 150.0, payload["previous_balance"])
+		assert.Equal(t, 100.0, payload["balance"])
+		assert.Nil(t, payload["transaction_id"])
+
+		rerun, err := newReconciliationService().RebuildBalances(context.Background(), reconciliation.RebuildBalancesRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, rerun.Diffs)
+	})
+
+	t.Run("apply should recreate a missing balance row", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		createCredit(t, accountID, 42.50)
+		DeleteBalance(t, accountID)
+
+		result, err := newReconciliationService().RebuildBalances(context.Background(), reconciliation.RebuildBalancesRequest{
+			Apply:    true,
+			Operator: "ops",
+			Reason:   "lost balance row",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, result.Diffs, 1)
+		assert.Nil(t, result.Diffs[0].Balance)
+		assert.Equal(t, int64(4250), result.Diffs[0].Difference)
+		assert.Equal(t, reconciliation.BalanceDiffApplied, result.Diffs[0].Status)
+
+		AssertBalanceEquals(t, accountID, 4250)
+
+		adjustments := GetBalanceAdjustments(t, accountID)
+		require.Len(t, adjustments, 1)
+		assert.Nil(t, adjustments[0].PreviousBalance)
+	})
+}
@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+func TestGetBalanceAt(t *testing.T) {
+	t.Run("GET /accounts/:id/balance", func(t *testing.T) {
+		setupHistory := func(t *testing.T) string {
+			t.Helper()
+
+			accountID := createTestAccount(t, TestDocument)
+			_, err := DB.NewUpdate().
+				Model((*models.CustomerAccount)(nil)).
+				Set("created_at = ?", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)).
+				Where("id = ?", accountID).
+				Exec(context.Background())
+			require.NoError(t, err)
+
+			creditResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, creditResp.StatusCode)
+			SetTransactionsCreatedAt(t, accountID, models.CreditVoucher, time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC))
+
+			purchaseResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.NormalPurchase,
+				"amount":         30.00,
+			})
+			require.Equal(t, http.StatusOK, purchaseResp.StatusCode)
+			SetTransactionsCreatedAt(t, accountID, models.NormalPurchase, time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC))
+
+			return accountID
+		}
+
+		getBalanceAt := func(t *testing.T, accountID string, at string) float64 {
+			t.Helper()
+
+			resp, body := GET(t, "/accounts/"+accountID+"/balance?at="+at)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Equal(t, accountID, response["account_id"])
+
+			return response["balance"].(float64)
+		}
+
+		t.Run("without snapshots should compute the balance from the transactions", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := setupHistory(t)
+
+			assert.Equal(t, 0.0, getBalanceAt(t, accountID, "2026-03-02T12:00:00Z"))
+			assert.Equal(t, 100.0, getBalanceAt(t, accountID, "2026-03-03T18:00:00Z"))
+			assert.Equal(t, 70.0, getBalanceAt(t, accountID, "2026-03-05T00:00:00Z"))
+		})
+
+		t.Run("with snapshots should combine the snapshot with later transactions", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := setupHistory(t)
+
+			snapshotRepository := repository.NewBalanceSnapshotRepository(DB)
+			for _, snapshotAt := range []time.Time{
+				time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+			} {
+				_, err := snapshotRepository.CreateBalanceSnapshots(context.Background(), snapshotAt)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, int64(0), GetBalanceSnapshot(t, accountID, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)).Balance)
+			assert.Equal(t, int64(10000), GetBalanceSnapshot(t, accountID, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)).Balance)
+
+			assert.Equal(t, 100.0, getBalanceAt(t, accountID, "2026-03-04T00:00:00Z"))
+			assert.Equal(t, 100.0, getBalanceAt(t, accountID, "2026-03-04T09:59:59Z"))
+			assert.Equal(t, 70.0, getBalanceAt(t, accountID, "2026-03-04T10:00:00Z"))
+		})
+
+		t.Run("creating snapshots twice should not duplicate them", func(t *testing.T) {
+			CleanupTables(t)
+
+			setupHistory(t)
+
+			snapshotRepository := repository.NewBalanceSnapshotRepository(DB)
+			snapshotAt := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+			created, err := snapshotRepository.CreateBalanceSnapshots(context.Background(), snapshotAt)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), created)
+
+			created, err = snapshotRepository.CreateBalanceSnapshots(context.Background(), snapshotAt)
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), created)
+		})
+
+		t.Run("without at should return the current balance", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := setupHistory(t)
+
+			resp, body := GET(t, "/accounts/"+accountID+"/balance")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Equal(t, 70.0, response["balance"])
+		})
+
+		t.Run("with invalid at should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := GET(t, "/accounts/"+accountID+"/balance?at=yesterday")
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("with non-existent account should return not found", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := GET(t, "/accounts/00000000-0000-0000-0000-000000000000/balance")
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
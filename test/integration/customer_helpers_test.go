@@ -54,3 +54,15 @@ func AssertCustomerAccountExistsByID(t *testing.T, accountID string) models.Cust
 
 	return account
 }
+
+func CountCustomersWithDocument(t *testing.T, document string) int {
+	t.Helper()
+
+	count, err := DB.NewSelect().
+		Model((*models.Customer)(nil)).
+		Where("document = ?", document).
+		Count(context.Background())
+
+	require.NoError(t, err)
+	return count
+}
@@ -0,0 +1,28 @@
+package integration
+
+import (
+	"context"
+	"expvar"
+
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+func transactionMetric(name string) int64 {
+	metric, ok := repository.TransactionMetrics.Get(name).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+
+	return metric.Value()
+}
+
+func setBalanceInTx(ctx context.Context, repo repository.Base, accountID string, balance int64) error {
+	_, err := repo.GetDB(ctx).NewUpdate().
+		Model((*models.Balance)(nil)).
+		Set("balance = ?", balance).
+		Where("customer_account_id = ?", accountID).
+		Exec(ctx)
+
+	return err
+}
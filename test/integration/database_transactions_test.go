@@ -0,0 +1,313 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/config"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+func TestWithTransaction(t *testing.T) {
+	t.Run("WithTransaction", func(t *testing.T) {
+		t.Run("with isolation option should run with that isolation level", func(t *testing.T) {
+			repo := repository.NewBalanceRepository(DB)
+
+			var isolation string
+			err := repo.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				return repo.GetDB(txCtx).NewRaw("SHOW transaction_isolation").Scan(txCtx, &isolation)
+			}, repository.WithIsolation(sql.LevelSerializable))
+
+			require.NoError(t, err)
+			assert.Equal(t, "serializable", isolation)
+		})
+
+		t.Run("with read only option should reject writes", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			repo := repository.NewBalanceRepository(DB)
+
+			attempts := 0
+			err := repo.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				attempts++
+
+				_, err := repo.GetDB(txCtx).NewUpdate().
+					Model((*models.Balance)(nil)).
+					Set("balance = 100").
+					Where("customer_account_id = ?", accountID).
+					Exec(txCtx)
+
+				return err
+			}, repository.ReadOnly())
+
+			require.Error(t, err)
+			assert.Equal(t, 1, attempts)
+			AssertBalanceEquals(t, accountID, 0)
+		})
+
+		t.Run("serialization failure should be retried and counted", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			repo := repository.NewBalanceRepository(DB)
+			retriesBefore := transactionMetric("serialization_failure_retries")
+
+			attempts := 0
+			err := repo.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				attempts++
+
+				var balance models.Balance
+				if err := repo.GetDB(txCtx).NewSelect().
+					Model(&balance).
+					Where("customer_account_id = ?", accountID).
+					Scan(txCtx); err != nil {
+					return err
+				}
+
+				if attempts == 1 {
+					SetBalance(t, accountID, 500)
+				}
+
+				_, err := repo.GetDB(txCtx).NewUpdate().
+					Model((*models.Balance)(nil)).
+					Set("balance = ?", balance.Balance+100).
+					Where("customer_account_id = ?", accountID).
+					Exec(txCtx)
+
+				return err
+			}, repository.WithIsolation(sql.LevelRepeatableRead))
+
+			require.NoError(t, err)
+			assert.Equal(t, 2, attempts)
+			assert.Equal(t, retriesBefore+1, transactionMetric("serialization_failure_retries"))
+			AssertBalanceEquals(t, accountID, 600)
+		})
+
+		t.Run("serialization failure should not be retried beyond the maximum", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			repo := repository.NewBalanceRepository(DB)
+			exhaustedBefore := transactionMetric("retries_exhausted")
+
+			attempts := 0
+			err := repo.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				attempts++
+
+				if _, err := repo.GetDB(txCtx).NewSelect().
+					Model((*models.Balance)(nil)).
+					Where("customer_account_id = ?", accountID).
+					Count(txCtx); err != nil {
+					return err
+				}
+
+				SetBalance(t, accountID, int64(attempts))
+
+				_, err := repo.GetDB(txCtx).NewUpdate().
+					Model((*models.Balance)(nil)).
+					Set("balance = 0").
+					Where("customer_account_id = ?", accountID).
+					Exec(txCtx)
+
+				return err
+			}, repository.WithIsolation(sql.LevelRepeatableRead), repository.WithMaxRetries(1))
+
+			require.Error(t, err)
+			assert.Equal(t, 2, attempts)
+			assert.Equal(t, exhaustedBefore+1, transactionMetric("retries_exhausted"))
+		})
+	})
+
+	t.Run("nested WithTransaction", func(t *testing.T) {
+		t.Run("inner failure should roll back only the inner writes", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			balanceRepository := repository.NewBalanceRepository(DB)
+			transactionRepository := repository.NewTransactionRepository(DB)
+
+			err := balanceRepository.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				if err := setBalanceInTx(txCtx, balanceRepository, accountID, 100); err != nil {
+					return err
+				}
+
+				innerErr := transactionRepository.WithTransaction(txCtx, func(innerCtx context.Context) error {
+					if err := setBalanceInTx(innerCtx, transactionRepository, accountID, 200); err != nil {
+						return err
+					}
+
+					return errors.New("inner failure")
+				})
+				assert.EqualError(t, innerErr, "inner failure")
+
+				return nil
+			})
+
+			require.NoError(t, err)
+			AssertBalanceEquals(t, accountID, 100)
+		})
+
+		t.Run("outer failure should roll back the inner writes", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			balanceRepository := repository.NewBalanceRepository(DB)
+			transactionRepository := repository.NewTransactionRepository(DB)
+
+			err := balanceRepository.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				innerErr := transactionRepository.WithTransaction(txCtx, func(innerCtx context.Context) error {
+					return setBalanceInTx(innerCtx, transactionRepository, accountID, 200)
+				})
+				require.NoError(t, innerErr)
+
+				return errors.New("outer failure")
+			})
+
+			require.EqualError(t, err, "outer failure")
+			AssertBalanceEquals(t, accountID, 0)
+		})
+
+		t.Run("inner database error should leave the outer transaction usable", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			balanceRepository := repository.NewBalanceRepository(DB)
+
+			err := balanceRepository.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				innerErr := balanceRepository.WithTransaction(txCtx, func(innerCtx context.Context) error {
+					_, err := balanceRepository.GetDB(innerCtx).NewRaw("SELECT 1/0").Exec(innerCtx)
+
+					return err
+				})
+				require.Error(t, innerErr)
+
+				return setBalanceInTx(txCtx, balanceRepository, accountID, 300)
+			})
+
+			require.NoError(t, err)
+			AssertBalanceEquals(t, accountID, 300)
+		})
+	})
+
+	t.Run("AfterCommit", func(t *testing.T) {
+		t.Run("hooks should run after commit and see the committed data", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			balanceRepository := repository.NewBalanceRepository(DB)
+
+			var seenBalances []int64
+
+			err := balanceRepository.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				if err := setBalanceInTx(txCtx, balanceRepository, accountID, 100); err != nil {
+					return err
+				}
+
+				repository.AfterCommit(txCtx, func(ctx context.Context) {
+					seenBalances = append(seenBalances, GetBalance(t, accountID))
+				})
+
+				return balanceRepository.WithTransaction(txCtx, func(innerCtx context.Context) error {
+					repository.AfterCommit(innerCtx, func(ctx context.Context) {
+						seenBalances = append(seenBalances, GetBalance(t, accountID))
+					})
+
+					assert.Empty(t, seenBalances)
+
+					return nil
+				})
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, []int64{100, 100}, seenBalances)
+		})
+
+		t.Run("hooks should be dropped when the transaction rolls back", func(t *testing.T) {
+			CleanupTables(t)
+
+			balanceRepository := repository.NewBalanceRepository(DB)
+			hookCalls := 0
+
+			err := balanceRepository.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				repository.AfterCommit(txCtx, func(ctx context.Context) {
+					hookCalls++
+				})
+
+				return errors.New("rollback")
+			})
+
+			require.Error(t, err)
+			assert.Equal(t, 0, hookCalls)
+		})
+
+		t.Run("hooks of a rolled back savepoint should be dropped", func(t *testing.T) {
+			CleanupTables(t)
+
+			balanceRepository := repository.NewBalanceRepository(DB)
+			var hooksCalled []string
+
+			err := balanceRepository.WithTransaction(context.Background(), func(txCtx context.Context) error {
+				repository.AfterCommit(txCtx, func(ctx context.Context) {
+					hooksCalled = append(hooksCalled, "outer")
+				})
+
+				_ = balanceRepository.WithTransaction(txCtx, func(innerCtx context.Context) error {
+					repository.AfterCommit(innerCtx, func(ctx context.Context) {
+						hooksCalled = append(hooksCalled, "inner")
+					})
+
+					return errors.New("inner failure")
+				})
+
+				return nil
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"outer"}, hooksCalled)
+		})
+
+		t.Run("outside a transaction hooks should run right away", func(t *testing.T) {
+			hookCalls := 0
+
+			repository.AfterCommit(context.Background(), func(ctx context.Context) {
+				hookCalls++
+			})
+
+			assert.Equal(t, 1, hookCalls)
+		})
+	})
+
+	t.Run("GET /debug/vars", func(t *testing.T) {
+		t.Run("should expose the database transaction metrics on the metrics router", func(t *testing.T) {
+			app := config.NewMetricsRouter(config.HTTPConfig{}).GetApp()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/debug/vars", nil), -1)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+
+			assert.Contains(t, response, "database_transactions")
+		})
+
+		t.Run("should not be served by the API", func(t *testing.T) {
+			resp, _ := GET(t, "/debug/vars")
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
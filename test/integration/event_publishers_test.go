@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/config"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+func TestEventPublishers(t *testing.T) {
+	t.Run("file adapter should append the events as JSON lines", func(t *testing.T) {
+		CleanupTables(t)
+
+		filePath := filepath.Join(t.TempDir(), "events.jsonl")
+
+		publisher, err := events.NewPublisherFromConfig(config.EventsConfig{
+			Publishers: []string{events.FileAdapter},
+			FilePath:   filePath,
+		})
+		require.NoError(t, err)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		dispatchOutbox(t, publisher)
+		require.NoError(t, events.ClosePublisher(publisher))
+
+		file, err := os.Open(filePath)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var published []events.Event
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var event events.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+
+			published = append(published, event)
+		}
+
+		require.Len(t, published, 3)
+		assert.Equal(t, events.AccountCreated, published[0].Type)
+		assert.Equal(t, events.TransactionCreated, published[1].Type)
+		assert.Equal(t, events.BalanceChanged, published[2].Type)
+
+		for _, event := range published {
+			assert.Equal(t, events.SchemaVersions[event.Type], event.Version)
+			assert.Equal(t, accountID, event.AccountID.String())
+		}
+	})
+
+	t.Run("nats adapter should publish on versioned subjects of an embedded server", func(t *testing.T) {
+		CleanupTables(t)
+
+		natsServer, err := events.RunEmbeddedNATSServer(-1)
+		require.NoError(t, err)
+		defer natsServer.Shutdown()
+
+		subscriber, err := nats.Connect(natsServer.ClientURL())
+		require.NoError(t, err)
+		defer subscriber.Close()
+
+		subscription, err := subscriber.SubscribeSync("accounts.>")
+		require.NoError(t, err)
+		require.NoError(t, subscriber.Flush())
+
+		publisher, err := events.NewNATSPublisher(natsServer.ClientURL(), "accounts", 5*time.Second)
+		require.NoError(t, err)
+		defer events.ClosePublisher(publisher)
+
+		accountID := createTestAccount(t, TestDocument)
+		outboxEvents := GetOutboxEvents(t, accountID)
+		require.Len(t, outboxEvents, 1)
+
+		dispatchOutbox(t, publisher)
+
+		message, err := subscription.NextMsg(5 * time.Second)
+		require.NoError(t, err)
+
+		assert.Equal(t, "accounts.account.created.v1", message.Subject)
+		assert.Equal(t, outboxEvents[0].EventID.String(), message.Header.Get(nats.MsgIdHdr))
+		assert.Equal(t, events.AccountCreated, message.Header.Get(events.EventTypeHeader))
+		assert.Equal(t, "1", message.Header.Get(events.EventVersionHeader))
+
+		var event events.Event
+		require.NoError(t, json.Unmarshal(message.Data, &event))
+		assert.Equal(t, outboxEvents[0].EventID, event.ID)
+	})
+
+	t.Run("http adapter should post the events and fail on error answers", func(t *testing.T) {
+		CleanupTables(t)
+
+		var (
+			mu       sync.Mutex
+			received []*http.Request
+			status   = http.StatusInternalServerError
+		)
+
+		sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			received = append(received, r)
+			w.WriteHeader(status)
+		}))
+		defer sink.Close()
+
+		publisher, err := events.NewPublisherFromConfig(config.EventsConfig{
+			Publishers:            []string{events.HTTPAdapter},
+			HTTPSinkURL:           sink.URL,
+			HTTPSinkAuthorization: "Bearer sink-token",
+			HTTPSinkTimeout:       5 * time.Second,
+		})
+		require.NoError(t, err)
+
+		createTestAccount(t, TestDocument)
+
+		dispatcher := events.NewDispatcher(repository.NewOutboxRepository(DB), publisher, 100, 3)
+
+		result, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Retried)
+
+		mu.Lock()
+		status = http.StatusAccepted
+		mu.Unlock()
+
+		MakeOutboxEventsDue(t)
+
+		result, err = dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Dispatched)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Len(t, received, 2)
+		assert.Equal(t, "Bearer sink-token", received[1].Header.Get("Authorization"))
+		assert.Equal(t, events.AccountCreated, received[1].Header.Get(events.EventTypeHeader))
+		assert.Equal(t, "1", received[1].Header.Get(events.EventVersionHeader))
+	})
+
+	t.Run("should publish to every configured adapter", func(t *testing.T) {
+		CleanupTables(t)
+
+		filePath := filepath.Join(t.TempDir(), "events.jsonl")
+		var sinkCalls atomic.Int32
+
+		sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sinkCalls.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer sink.Close()
+
+		publisher, err := events.NewPublisherFromConfig(config.EventsConfig{
+			Publishers:      []string{events.LogAdapter, events.FileAdapter, events.HTTPAdapter},
+			FilePath:        filePath,
+			HTTPSinkURL:     sink.URL,
+			HTTPSinkTimeout: 5 * time.Second,
+		})
+		require.NoError(t, err)
+
+		createTestAccount(t, TestDocument)
+
+		dispatchOutbox(t, publisher)
+		require.NoError(t, events.ClosePublisher(publisher))
+
+		content, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		assert.Len(t, splitLines(content), 1)
+		assert.Equal(t, int32(1), sinkCalls.Load())
+	})
+
+	t.Run("should refuse an invalid configuration", func(t *testing.T) {
+		_, err := events.NewPublisherFromConfig(config.EventsConfig{Publishers: []string{"kafka"}})
+		assert.ErrorContains(t, err, "unknown adapter")
+
+		_, err = events.NewPublisherFromConfig(config.EventsConfig{Publishers: []string{events.HTTPAdapter}})
+		assert.ErrorContains(t, err, "EVENT_HTTP_SINK_URL")
+
+		_, err = events.NewPublisherFromConfig(config.EventsConfig{})
+		assert.Error(t, err)
+	})
+}
@@ -2,6 +2,8 @@ package integration
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -9,21 +11,51 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
 )
 
 const (
-	TestDocument string = "41184478007"
+	TestDocument       string = "41184478007"
+	TestSecondDocument string = "52998224725"
 )
 
-func POST(t *testing.T, path string, body any) (*http.Response, []byte) {
+func POST(t testing.TB, path string, body any) (*http.Response, []byte) {
+	t.Helper()
+
+	return sendJSON(t, "POST", path, body, nil)
+}
+
+func POSTWithIdempotencyKey(t *testing.T, path string, body any, idempotencyKey string) (*http.Response, []byte) {
+	t.Helper()
+
+	return sendJSON(t, "POST", path, body, map[string]string{middleware.IdempotencyKeyHeader: idempotencyKey})
+}
+
+func PATCH(t *testing.T, path string, body any) (*http.Response, []byte) {
+	t.Helper()
+
+	return sendJSON(t, "PATCH", path, body, nil)
+}
+
+func PATCHWithIdempotencyKey(t *testing.T, path string, body any, idempotencyKey string) (*http.Response, []byte) {
+	t.Helper()
+
+	return sendJSON(t, "PATCH", path, body, map[string]string{middleware.IdempotencyKeyHeader: idempotencyKey})
+}
+
+func sendJSON(t testing.TB, method string, path string, body any, headers map[string]string) (*http.Response, []byte) {
 	t.Helper()
 
 	jsonBody, err := json.Marshal(body)
 	require.NoError(t, err)
 
-	req := httptest.NewRequest("POST", path, bytes.NewReader(jsonBody))
+	req := httptest.NewRequest(method, path, bytes.NewReader(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
 
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
 	resp, err := App.Test(req, -1)
 	require.NoError(t, err)
 
@@ -49,7 +81,35 @@ func GET(t *testing.T, path string) (*http.Response, []byte) {
 	return resp, respBody
 }
 
-func ParseJSON(t *testing.T, body []byte, dest any) {
+func DELETE(t *testing.T, path string) (*http.Response, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest("DELETE", path, nil)
+
+	resp, err := App.Test(req, -1)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	return resp, respBody
+}
+
+func ParseJSON(t testing.TB, body []byte, dest any) {
 	t.Helper()
 	require.NoError(t, json.Unmarshal(body, dest))
 }
+
+// fingerprintOf returns the fingerprint the idempotency middleware computes
+// for body when it's sent as JSON.
+func fingerprintOf(t *testing.T, body any) string {
+	t.Helper()
+
+	jsonBody, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(jsonBody)
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Run("POST /accounts", func(t *testing.T) {
+		t.Run("retry with same Idempotency-Key should replay the original response", func(t *testing.T) {
+			CleanupTables(t)
+
+			payload := map[string]any{"document_number": TestDocument}
+
+			firstResp, firstBody := POSTWithIdempotencyKey(t, "/accounts", payload, "create-account-1")
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+			assert.Empty(t, firstResp.Header.Get(middleware.IdempotentReplayedHeader))
+
+			secondResp, secondBody := POSTWithIdempotencyKey(t, "/accounts", payload, "create-account-1")
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+			assert.Equal(t, "true", secondResp.Header.Get(middleware.IdempotentReplayedHeader))
+			assert.Equal(t, firstResp.Header.Get("Content-Type"), secondResp.Header.Get("Content-Type"))
+			assert.JSONEq(t, string(firstBody), string(secondBody))
+
+			assert.Equal(t, 1, CountCustomersWithDocument(t, TestDocument))
+		})
+
+		t.Run("retry of a client error should replay it", func(t *testing.T) {
+			CleanupTables(t)
+
+			payload := map[string]any{"document_number": "11122233344"}
+
+			firstResp, firstBody := POSTWithIdempotencyKey(t, "/accounts", payload, "invalid-account")
+			require.Equal(t, http.StatusBadRequest, firstResp.StatusCode)
+
+			secondResp, secondBody := POSTWithIdempotencyKey(t, "/accounts", payload, "invalid-account")
+			assert.Equal(t, http.StatusBadRequest, secondResp.StatusCode)
+			assert.Equal(t, "true", secondResp.Header.Get(middleware.IdempotentReplayedHeader))
+			assert.JSONEq(t, string(firstBody), string(secondBody))
+		})
+
+		t.Run("same Idempotency-Key with a different payload should return unprocessable entity", func(t *testing.T) {
+			CleanupTables(t)
+
+			firstResp, _ := POSTWithIdempotencyKey(t, "/accounts", map[string]any{"document_number": TestDocument}, "create-account-1")
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, _ := POSTWithIdempotencyKey(t, "/accounts", map[string]any{"document_number": TestSecondDocument}, "create-account-1")
+			assert.Equal(t, http.StatusUnprocessableEntity, secondResp.StatusCode)
+
+			assert.Equal(t, 0, CountCustomersWithDocument(t, TestSecondDocument))
+		})
+
+		t.Run("retry while the first request is in progress should return conflict", func(t *testing.T) {
+			CleanupTables(t)
+
+			payload := map[string]any{"document_number": TestDocument}
+
+			_, err := DB.NewInsert().Model(&models.IdempotentRequest{
+				Method:             http.MethodPost,
+				Path:               "/accounts",
+				IdempotencyKey:     "create-account-1",
+				RequestFingerprint: fingerprintOf(t, payload),
+			}).Exec(context.Background())
+			require.NoError(t, err)
+
+			resp, _ := POSTWithIdempotencyKey(t, "/accounts", payload, "create-account-1")
+			assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+			assert.Equal(t, 0, CountCustomersWithDocument(t, TestDocument))
+		})
+
+		t.Run("retry after the claim of the first request expired should take it over", func(t *testing.T) {
+			CleanupTables(t)
+
+			payload := map[string]any{"document_number": TestDocument}
+
+			_, err := DB.NewInsert().Model(&models.IdempotentRequest{
+				Method:             http.MethodPost,
+				Path:               "/accounts",
+				IdempotencyKey:     "create-account-1",
+				RequestFingerprint: fingerprintOf(t, payload),
+			}).Exec(context.Background())
+			require.NoError(t, err)
+
+			_, err = DB.NewUpdate().
+				Table("idempotent_request").
+				Set("updated_at = ?", time.Now().Add(-time.Hour)).
+				Where("idempotency_key = ?", "create-account-1").
+				Exec(context.Background())
+			require.NoError(t, err)
+
+			resp, _ := POSTWithIdempotencyKey(t, "/accounts", payload, "create-account-1")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			assert.Equal(t, 1, CountCustomersWithDocument(t, TestDocument))
+		})
+
+		t.Run("with a too long Idempotency-Key should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POSTWithIdempotencyKey(t, "/accounts", map[string]any{"document_number": TestDocument}, strings.Repeat("k", 256))
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("same Idempotency-Key on another route should not be replayed", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountResp, _ := POSTWithIdempotencyKey(t, "/accounts", map[string]any{"document_number": TestDocument}, "shared-key")
+			require.Equal(t, http.StatusOK, accountResp.StatusCode)
+
+			operationTypeResp, _ := POSTWithIdempotencyKey(t, "/operation-types", map[string]any{
+				"code":        testOperationType,
+				"direction":   models.DebitDirection,
+				"description": "Monthly account fee",
+			}, "shared-key")
+			require.Equal(t, http.StatusOK, operationTypeResp.StatusCode)
+			assert.Empty(t, operationTypeResp.Header.Get(middleware.IdempotentReplayedHeader))
+		})
+	})
+
+	t.Run("POST /imports", func(t *testing.T) {
+		t.Run("retry of an upload with same Idempotency-Key should replay the original response", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf("account_id,operation_type,amount\n%s,credit_voucher,100.00\n", accountID)
+
+			firstResp, firstBody := POSTFileWithIdempotencyKey(t, "/imports", "partner.csv", csv, nil, "import-1")
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, secondBody := POSTFileWithIdempotencyKey(t, "/imports", "partner.csv", csv, nil, "import-1")
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+			assert.Equal(t, "true", secondResp.Header.Get(middleware.IdempotentReplayedHeader))
+			assert.JSONEq(t, string(firstBody), string(secondBody))
+
+			assert.Equal(t, 1, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("same Idempotency-Key with a different file should return unprocessable entity", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			firstCSV := fmt.Sprintf("account_id,operation_type,amount\n%s,credit_voucher,100.00\n", accountID)
+			secondCSV := fmt.Sprintf("account_id,operation_type,amount\n%s,credit_voucher,200.00\n", accountID)
+
+			firstResp, _ := POSTFileWithIdempotencyKey(t, "/imports", "partner.csv", firstCSV, nil, "import-1")
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, _ := POSTFileWithIdempotencyKey(t, "/imports", "partner.csv", secondCSV, nil, "import-1")
+			assert.Equal(t, http.StatusUnprocessableEntity, secondResp.StatusCode)
+
+			AssertBalanceEquals(t, accountID, 10000)
+		})
+	})
+
+	t.Run("PATCH /operation-types/:code", func(t *testing.T) {
+		t.Run("retry with same Idempotency-Key should replay the original response", func(t *testing.T) {
+			CleanupTables(t)
+
+			path := "/operation-types/" + string(models.CreditVoucher)
+			payload := map[string]any{"description": "Voucher"}
+
+			firstResp, firstBody := PATCHWithIdempotencyKey(t, path, payload, "rename-voucher")
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, secondBody := PATCHWithIdempotencyKey(t, path, payload, "rename-voucher")
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+			assert.Equal(t, "true", secondResp.Header.Get(middleware.IdempotentReplayedHeader))
+			assert.JSONEq(t, string(firstBody), string(secondBody))
+		})
+	})
+}
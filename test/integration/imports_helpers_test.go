@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
+)
+
+type ImportResponse struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	DryRun          bool   `json:"dry_run"`
+	AlreadyImported bool   `json:"already_imported"`
+	TotalRows       int    `json:"total_rows"`
+	ImportedRows    int    `json:"imported_rows"`
+	FailedRows      int    `json:"failed_rows"`
+	Rows            []struct {
+		Row           int      `json:"row"`
+		AccountID     string   `json:"account_id"`
+		OperationType string   `json:"operation_type"`
+		Amount        float64  `json:"amount"`
+		Status        string   `json:"status"`
+		TransactionID string   `json:"transaction_id"`
+		Errors        []string `json:"errors"`
+	} `json:"rows"`
+}
+
+// POSTFile uploads content as the "file" field of a multipart form, along
+// with the other fields.
+func POSTFile(t *testing.T, path string, fileName string, content string, fields map[string]string) (*http.Response, []byte) {
+	t.Helper()
+
+	return sendFile(t, path, fileName, content, fields, nil)
+}
+
+func POSTFileWithIdempotencyKey(
+	t *testing.T, path string, fileName string, content string, fields map[string]string, idempotencyKey string,
+) (*http.Response, []byte) {
+	t.Helper()
+
+	return sendFile(t, path, fileName, content, fields, map[string]string{middleware.IdempotencyKeyHeader: idempotencyKey})
+}
+
+func sendFile(
+	t *testing.T, path string, fileName string, content string, fields map[string]string, headers map[string]string,
+) (*http.Response, []byte) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+
+	_, err = io.Copy(part, strings.NewReader(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := App.Test(req, -1)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	return resp, respBody
+}
+
+func importOFXStatement(accountID string, transactions ...string) string {
+	return fmt.Sprintf(`OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM><BANKID>001<ACCTID>%s<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>%s</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`, accountID, strings.Join(transactions, ""))
+}
+
+func importOFXTransaction(transactionType string, amount string, fitID string) string {
+	return fmt.Sprintf(
+		"<STMTTRN><TRNTYPE>%s<DTPOSTED>20261001120000[-3:BRT]<TRNAMT>%s<FITID>%s</STMTTRN>",
+		transactionType, amount, fitID,
+	)
+}
+
+func CountImportFiles(t *testing.T) int {
+	t.Helper()
+
+	count, err := DB.NewSelect().Table("import_file").Count(context.Background())
+	require.NoError(t, err)
+
+	return count
+}
@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestImportTransactions(t *testing.T) {
+	t.Run("POST /imports", func(t *testing.T) {
+		t.Run("CSV file should post every row", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf(
+				"account_id,operation_type,amount\n%s,credit_voucher,100.00\n%s,normal_purchase,25.50\n",
+				accountID, accountID,
+			)
+
+			resp, body := POSTFile(t, "/imports", "partner.csv", csv, nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, "imported", response.Status)
+			assert.NotEmpty(t, response.ID)
+			assert.Equal(t, 2, response.TotalRows)
+			assert.Equal(t, 2, response.ImportedRows)
+			assert.Equal(t, 0, response.FailedRows)
+
+			for _, row := range response.Rows {
+				assert.Equal(t, "created", row.Status)
+				assert.NotEmpty(t, row.TransactionID)
+			}
+
+			assert.Equal(t, 2, CountTransactionsForAccount(t, accountID))
+			AssertBalanceEquals(t, accountID, 7450)
+		})
+
+		t.Run("CSV file should follow the column mapping", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf("valor;conta;tipo\n10.00;%s;credit_voucher\n", accountID)
+
+			resp, body := POSTFile(t, "/imports", "partner.csv", csv, map[string]string{
+				"account_id_column":     "conta",
+				"operation_type_column": "tipo",
+				"amount_column":         "valor",
+				"delimiter":             ";",
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, 1, response.ImportedRows)
+
+			AssertBalanceEquals(t, accountID, 1000)
+		})
+
+		t.Run("dry run should report the rows without posting them", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf(
+				"account_id,operation_type,amount\n%s,credit_voucher,100.00\n%s,unknown_type,10.00\n00000000-0000-0000-0000-000000000000,credit_voucher,-1\n",
+				accountID, accountID,
+			)
+
+			resp, body := POSTFile(t, "/imports", "partner.csv", csv, map[string]string{"dry_run": "true"})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.True(t, response.DryRun)
+			assert.Equal(t, "rejected", response.Status)
+			assert.Equal(t, 3, response.TotalRows)
+			assert.Equal(t, 2, response.FailedRows)
+
+			require.Len(t, response.Rows, 3)
+			assert.Equal(t, "valid", response.Rows[0].Status)
+			assert.Equal(t, "invalid", response.Rows[1].Status)
+			assert.Contains(t, response.Rows[1].Errors, "Operation type not found")
+			assert.Equal(t, "invalid", response.Rows[2].Status)
+			assert.Contains(t, response.Rows[2].Errors, "Customer account not found")
+			assert.Contains(t, response.Rows[2].Errors, "amount must be greater than 0")
+
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+			assert.Equal(t, 0, CountImportFiles(t))
+		})
+
+		t.Run("file with invalid rows should be rejected without posting any row", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf(
+				"account_id,operation_type,amount\n%s,credit_voucher,100.00\nnot-an-uuid,credit_voucher,10.00\n",
+				accountID,
+			)
+
+			resp, body := POSTFile(t, "/imports", "partner.csv", csv, nil)
+			require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, "rejected", response.Status)
+			assert.Contains(t, response.Rows[1].Errors, "account_id must be a valid UUID")
+
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("uploading the same file again should be a no-op", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf("account_id,operation_type,amount\n%s,credit_voucher,100.00\n", accountID)
+
+			firstResp, firstBody := POSTFile(t, "/imports", "partner.csv", csv, nil)
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, secondBody := POSTFile(t, "/imports", "partner-copy.csv", csv, nil)
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+
+			var first, second ImportResponse
+			ParseJSON(t, firstBody, &first)
+			ParseJSON(t, secondBody, &second)
+
+			assert.False(t, first.AlreadyImported)
+			assert.True(t, second.AlreadyImported)
+			assert.Equal(t, first.ID, second.ID)
+
+			assert.Equal(t, 1, CountTransactionsForAccount(t, accountID))
+			assert.Equal(t, 1, CountImportFiles(t))
+			AssertBalanceEquals(t, accountID, 10000)
+		})
+
+		t.Run("rows refused by the transactions service should be reported as failed", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf(
+				"account_id,operation_type,amount\n%s,withdrawal,50.00\n%s,credit_voucher,20.00\n",
+				accountID, accountID,
+			)
+
+			resp, body := POSTFile(t, "/imports", "partner.csv", csv, nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, 1, response.ImportedRows)
+			assert.Equal(t, 1, response.FailedRows)
+			assert.Equal(t, "failed", response.Rows[0].Status)
+			assert.Equal(t, []string{"Insufficient funds to perform operation"}, response.Rows[0].Errors)
+			assert.Equal(t, "created", response.Rows[1].Status)
+
+			AssertBalanceEquals(t, accountID, 2000)
+		})
+
+		t.Run("OFX file should map the transactions to operation types", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			statement := importOFXStatement(accountID,
+				importOFXTransaction("CREDIT", "150,00", "1"),
+				importOFXTransaction("ATM", "-40.00", "2"),
+				importOFXTransaction("DEBIT", "-10.25", "3"),
+			)
+
+			resp, body := POSTFile(t, "/imports", "statement.ofx", statement, nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, 3, response.ImportedRows)
+
+			AssertTransactionExists(t, accountID, models.CreditVoucher, 15000)
+			AssertTransactionExists(t, accountID, models.Withdrawal, -4000)
+			AssertTransactionExists(t, accountID, models.NormalPurchase, -1025)
+			AssertBalanceEquals(t, accountID, 9975)
+		})
+
+		t.Run("file missing a mapped column should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POSTFile(t, "/imports", "partner.csv", "account,amount\n", nil)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("file of unknown format should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POSTFile(t, "/imports", "partner.txt", "account_id,operation_type,amount\n", nil)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+
+	t.Run("GET /imports/:importId", func(t *testing.T) {
+		t.Run("should return the report of an import", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			csv := fmt.Sprintf("account_id,operation_type,amount\n%s,credit_voucher,1.00\n", accountID)
+
+			resp, body := POSTFile(t, "/imports", "partner.csv", csv, nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var created ImportResponse
+			ParseJSON(t, body, &created)
+
+			resp, body = GET(t, "/imports/"+created.ID)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response ImportResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, created.ID, response.ID)
+			assert.Equal(t, 1, response.ImportedRows)
+		})
+
+		t.Run("with unknown id should return not found", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := GET(t, "/imports/00000000-0000-0000-0000-000000000000")
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
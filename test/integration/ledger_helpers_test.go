@@ -0,0 +1,36 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func GetJournalPostingsForTransaction(t *testing.T, transactionID string) []models.JournalPosting {
+	t.Helper()
+
+	var postings []models.JournalPosting
+	err := DB.NewSelect().
+		Model(&postings).
+		Join("JOIN journal_entry je ON je.id = journal_posting.journal_entry_id").
+		Where("je.transaction_id = ?", transactionID).
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return postings
+}
+
+func GetLedgerAccount(t *testing.T, code models.LedgerAccountCode) models.LedgerAccount {
+	t.Helper()
+
+	var ledgerAccount models.LedgerAccount
+	err := DB.NewSelect().
+		Model(&ledgerAccount).
+		Where("code = ?", code).
+		Scan(context.Background())
+
+	require.NoError(t, err, "ledger account %s should exist", code)
+	return ledgerAccount
+}
@@ -0,0 +1,164 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/uptrace/bun"
+)
+
+func TestJournalEntries(t *testing.T) {
+	t.Run("POST /transactions", func(t *testing.T) {
+		t.Run("credit voucher should post a balanced entry against voucher funding", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			tx := AssertTransactionExists(t, accountID, models.CreditVoucher, 10000)
+			postings := GetJournalPostingsForTransaction(t, tx.ID.String())
+			require.Len(t, postings, 2)
+
+			voucherFunding := GetLedgerAccount(t, models.VoucherFundingLedgerAccount)
+
+			var total int64
+			for _, posting := range postings {
+				total += posting.Amount
+
+				if posting.CustomerAccountID != nil {
+					assert.Equal(t, accountID, posting.CustomerAccountID.String())
+					assert.Equal(t, int64(10000), posting.Amount)
+				} else {
+					assert.Equal(t, voucherFunding.ID.String(), posting.LedgerAccountID.String())
+					assert.Equal(t, int64(-10000), posting.Amount)
+				}
+			}
+			assert.Equal(t, int64(0), total)
+		})
+
+		t.Run("purchase should post a balanced entry against settlement", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			creditResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, creditResp.StatusCode)
+
+			purchaseResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.NormalPurchase,
+				"amount":         30.00,
+			})
+			require.Equal(t, http.StatusOK, purchaseResp.StatusCode)
+
+			tx := AssertTransactionExists(t, accountID, models.NormalPurchase, -3000)
+			postings := GetJournalPostingsForTransaction(t, tx.ID.String())
+			require.Len(t, postings, 2)
+
+			settlement := GetLedgerAccount(t, models.SettlementLedgerAccount)
+
+			for _, posting := range postings {
+				if posting.LedgerAccountID != nil {
+					assert.Equal(t, settlement.ID.String(), posting.LedgerAccountID.String())
+					assert.Equal(t, int64(3000), posting.Amount)
+				}
+			}
+		})
+	})
+
+	t.Run("journal_posting", func(t *testing.T) {
+		t.Run("unbalanced entry should be rejected by the database on commit", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			account := AssertCustomerAccountExistsByID(t, accountID)
+
+			err := DB.RunInTx(context.Background(), nil, func(ctx context.Context, dbTx bun.Tx) error {
+				transaction := models.Transaction{
+					CustomerAccountID: account.ID,
+					OperationType:     models.CreditVoucher,
+					Amount:            500,
+				}
+				if _, err := dbTx.NewInsert().Model(&transaction).Exec(ctx); err != nil {
+					return err
+				}
+
+				entry := models.JournalEntry{TransactionID: transaction.ID}
+				if _, err := dbTx.NewInsert().Model(&entry).Exec(ctx); err != nil {
+					return err
+				}
+
+				_, err := dbTx.NewInsert().Model(&models.JournalPosting{
+					JournalEntryID:    entry.ID,
+					CustomerAccountID: account.ID,
+					Amount:            500,
+				}).Exec(ctx)
+
+				return err
+			})
+
+			assert.Error(t, err)
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
+	})
+}
+
+func TestTrialBalance(t *testing.T) {
+	t.Run("GET /ledger/trial-balance", func(t *testing.T) {
+		t.Run("should report balanced debits and credits across all accounts", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			creditResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, creditResp.StatusCode)
+
+			withdrawalResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.Withdrawal,
+				"amount":         40.00,
+			})
+			require.Equal(t, http.StatusOK, withdrawalResp.StatusCode)
+
+			resp, body := GET(t, "/ledger/trial-balance")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+
+			assert.Equal(t, true, response["balanced"])
+			assert.Equal(t, 140.0, response["total_debits"])
+			assert.Equal(t, 140.0, response["total_credits"])
+
+			balances := map[string]float64{}
+			for _, account := range response["accounts"].([]any) {
+				row := account.(map[string]any)
+				balances[row["account"].(string)] = row["balance"].(float64)
+			}
+
+			assert.Equal(t, 60.0, balances[accountID])
+			assert.Equal(t, -100.0, balances[string(models.VoucherFundingLedgerAccount)])
+			assert.Equal(t, 40.0, balances[string(models.SettlementLedgerAccount)])
+			assert.Equal(t, 0.0, balances[string(models.FeesRevenueLedgerAccount)])
+		})
+	})
+}
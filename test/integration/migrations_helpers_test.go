@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// newEmptyTestDatabase creates a database without any migration applied,
+// dropped at the end of the test, to check the migrations against data
+// written by older versions.
+func newEmptyTestDatabase(t *testing.T) *bun.DB {
+	t.Helper()
+
+	cfg := getTestConfig()
+	dbName := cfg.dbName + "_migrations"
+
+	adminDSN := fmt.Sprintf(
+		"postgres://%s:%s@%s/postgres?sslmode=%s",
+		cfg.user, cfg.password, cfg.host, cfg.sslMode,
+	)
+
+	adminDB := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(adminDSN)))
+	t.Cleanup(func() { adminDB.Close() })
+
+	_, err := adminDB.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbName))
+	require.NoError(t, err)
+
+	_, err = adminDB.Exec(fmt.Sprintf("CREATE DATABASE %s", dbName))
+	require.NoError(t, err)
+
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s/%s?sslmode=%s",
+		cfg.user, cfg.password, cfg.host, dbName, cfg.sslMode,
+	)
+
+	bunDB := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New())
+
+	t.Cleanup(func() {
+		bunDB.Close()
+
+		_, err := adminDB.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", dbName))
+		require.NoError(t, err)
+	})
+
+	return bunDB
+}
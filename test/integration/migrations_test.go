@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	t.Run("ledger backfill should skip transactions stored with a 0 amount", func(t *testing.T) {
+		ctx := context.Background()
+		bunDB := newEmptyTestDatabase(t)
+		migrations := &migrate.FileMigrationSource{Dir: migrationsFolder}
+
+		// Only the initial schema, from before the ledger existed.
+		_, err := migrate.ExecMax(bunDB.DB, "postgres", migrations, migrate.Up, 1)
+		require.NoError(t, err)
+
+		_, err = bunDB.ExecContext(ctx, `
+			INSERT INTO customer (id, document) VALUES ('00000000-0000-0000-0000-000000000001', ?);
+			INSERT INTO customer_account (id, customer_id)
+				VALUES ('00000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000001');
+			INSERT INTO balance (id, customer_account_id, balance)
+				VALUES ('00000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000002', 1000);
+			INSERT INTO transactions (id, customer_account_id, operation_type, amount) VALUES
+				('00000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000002', 'credit_voucher', 0),
+				('00000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000002', 'credit_voucher', 1000);
+		`, TestDocument)
+		require.NoError(t, err)
+
+		_, err = migrate.Exec(bunDB.DB, "postgres", migrations, migrate.Up)
+		require.NoError(t, err)
+
+		var entries []string
+		err = bunDB.NewSelect().
+			Column("transaction_id").
+			Table("journal_entry").
+			Scan(ctx, &entries)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"00000000-0000-0000-0000-000000000005"}, entries)
+
+		var postingsTotal int64
+		err = bunDB.NewSelect().
+			ColumnExpr("COALESCE(SUM(amount), 0)").
+			Table("journal_posting").
+			Scan(ctx, &postingsTotal)
+		require.NoError(t, err)
+		assert.Zero(t, postingsTotal)
+	})
+}
@@ -0,0 +1,252 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+const testOperationType models.OperationType = "account_fee"
+
+func createTestOperationType(t *testing.T) {
+	t.Helper()
+
+	resp, _ := POST(t, "/operation-types", map[string]any{
+		"code":                        testOperationType,
+		"direction":                   models.DebitDirection,
+		"description":                 "Monthly account fee",
+		"counterparty_ledger_account": models.FeesRevenueLedgerAccount,
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestOperationTypes(t *testing.T) {
+	t.Run("GET /operation-types", func(t *testing.T) {
+		t.Run("should list the seeded operation types", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, body := GET(t, "/operation-types")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response []map[string]any
+			ParseJSON(t, body, &response)
+
+			directions := map[string]string{}
+			for _, operationType := range response {
+				directions[operationType["code"].(string)] = operationType["direction"].(string)
+			}
+
+			assert.Equal(t, map[string]string{
+				string(models.NormalPurchase):            string(models.DebitDirection),
+				string(models.PurcharseWithInstallments): string(models.DebitDirection),
+				string(models.Withdrawal):                string(models.DebitDirection),
+				string(models.CreditVoucher):             string(models.CreditDirection),
+			}, directions)
+		})
+	})
+
+	t.Run("POST /operation-types", func(t *testing.T) {
+		t.Run("new type should be usable by transactions without a deploy", func(t *testing.T) {
+			CleanupTables(t)
+
+			createTestOperationType(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			creditResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, creditResp.StatusCode)
+
+			feeResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": testOperationType,
+				"amount":         12.50,
+			})
+			require.Equal(t, http.StatusOK, feeResp.StatusCode)
+
+			AssertBalanceEquals(t, accountID, 8750)
+
+			tx := AssertTransactionExists(t, accountID, testOperationType, -1250)
+			feesRevenue := GetLedgerAccount(t, models.FeesRevenueLedgerAccount)
+
+			for _, posting := range GetJournalPostingsForTransaction(t, tx.ID.String()) {
+				if posting.LedgerAccountID != nil {
+					assert.Equal(t, feesRevenue.ID.String(), posting.LedgerAccountID.String())
+					assert.Equal(t, int64(1250), posting.Amount)
+				}
+			}
+		})
+
+		t.Run("with existing code should return conflict", func(t *testing.T) {
+			CleanupTables(t)
+
+			createTestOperationType(t)
+
+			resp, _ := POST(t, "/operation-types", map[string]any{
+				"code":        testOperationType,
+				"direction":   models.DebitDirection,
+				"description": "Duplicated",
+			})
+
+			assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		})
+
+		t.Run("with invalid direction should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POST(t, "/operation-types", map[string]any{
+				"code":        testOperationType,
+				"direction":   "sideways",
+				"description": "Invalid",
+			})
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("with unknown counterparty should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POST(t, "/operation-types", map[string]any{
+				"code":                        testOperationType,
+				"direction":                   models.DebitDirection,
+				"description":                 "Invalid",
+				"counterparty_ledger_account": "unknown",
+			})
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+
+	t.Run("PATCH /operation-types/:code", func(t *testing.T) {
+		t.Run("disabled type should reject new transactions", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, body := PATCH(t, "/operation-types/"+string(models.CreditVoucher), map[string]any{
+				"enabled": false,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Equal(t, false, response["enabled"])
+
+			creditResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         10.00,
+			})
+			assert.Equal(t, http.StatusBadRequest, creditResp.StatusCode)
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("amount limits should be enforced on transactions", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := PATCH(t, "/operation-types/"+string(models.CreditVoucher), map[string]any{
+				"min_amount": 1.00,
+				"max_amount": 50.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			aboveResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         50.01,
+			})
+			assert.Equal(t, http.StatusBadRequest, aboveResp.StatusCode)
+
+			belowResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         0.50,
+			})
+			assert.Equal(t, http.StatusBadRequest, belowResp.StatusCode)
+
+			withinResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         50.00,
+			})
+			assert.Equal(t, http.StatusOK, withinResp.StatusCode)
+		})
+
+		t.Run("with null limit should remove it and keep the omitted one", func(t *testing.T) {
+			CleanupTables(t)
+
+			path := "/operation-types/" + string(models.CreditVoucher)
+
+			resp, _ := PATCH(t, path, map[string]any{
+				"min_amount": 1.00,
+				"max_amount": 50.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			resp, body := PATCH(t, path, map[string]any{
+				"min_amount": nil,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Nil(t, response["min_amount"])
+			assert.Equal(t, 50.0, response["max_amount"])
+		})
+
+		t.Run("with amount below one cent should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := PATCH(t, "/operation-types/"+string(models.CreditVoucher), map[string]any{
+				"min_amount": 0.001,
+			})
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("with max amount below min amount should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := PATCH(t, "/operation-types/"+string(models.CreditVoucher), map[string]any{
+				"min_amount": 10.00,
+				"max_amount": 5.00,
+			})
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("with non-existent type should return not found", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := PATCH(t, "/operation-types/unknown", map[string]any{
+				"enabled": false,
+			})
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+
+	t.Run("POST /transactions", func(t *testing.T) {
+		t.Run("with unknown operation type should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": "teleport",
+				"amount":         10.00,
+			})
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+// recordingPublisher keeps the events it publishes, failing those for which
+// fail returns an error.
+type recordingPublisher struct {
+	mu        sync.Mutex
+	published []events.Event
+	fail      func(event events.Event) error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	if p.fail != nil {
+		if err := p.fail(event); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = append(p.published, event)
+
+	return nil
+}
+
+func (p *recordingPublisher) Published() []events.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]events.Event(nil), p.published...)
+}
+
+func GetOutboxEvents(t *testing.T, accountID string) []models.OutboxEvent {
+	t.Helper()
+
+	var outboxEvents []models.OutboxEvent
+	err := DB.NewSelect().
+		Model(&outboxEvents).
+		Where("customer_account_id = ?", accountID).
+		Order("id").
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return outboxEvents
+}
+
+func ParseOutboxPayload(t *testing.T, outboxEvent models.OutboxEvent) map[string]any {
+	t.Helper()
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &payload))
+
+	return payload
+}
+
+// MakeOutboxEventsDue lets the events waiting for a retry be dispatched now.
+func MakeOutboxEventsDue(t *testing.T) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Table("outbox_event").
+		Set("next_attempt_at = NOW() - INTERVAL '1 second'").
+		Where("status = ?", models.OutboxEventPending).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
+
+// dispatchOutbox publishes every pending outbox event to the publisher.
+func dispatchOutbox(t *testing.T, publisher events.Publisher) {
+	t.Helper()
+
+	dispatcher := events.NewDispatcher(repository.NewOutboxRepository(DB), publisher, 100, 3)
+
+	_, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+}
+
+func splitLines(content []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
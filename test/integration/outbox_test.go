@@ -0,0 +1,215 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+)
+
+func TestOutbox(t *testing.T) {
+	t.Run("creating an account should record account.created", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		outboxEvents := GetOutboxEvents(t, accountID)
+		require.Len(t, outboxEvents, 1)
+
+		assert.Equal(t, events.AccountCreated, outboxEvents[0].EventType)
+		assert.Equal(t, 1, outboxEvents[0].SchemaVersion)
+		assert.Equal(t, models.OutboxEventPending, outboxEvents[0].Status)
+
+		payload := ParseOutboxPayload(t, outboxEvents[0])
+		assert.Equal(t, accountID, payload["account_id"])
+		assert.Equal(t, TestDocument, payload["document_number"])
+	})
+
+	t.Run("creating a transaction should record transaction.created and balance.changed", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, body := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response map[string]any
+		ParseJSON(t, body, &response)
+
+		outboxEvents := GetOutboxEvents(t, accountID)
+		require.Len(t, outboxEvents, 3)
+
+		assert.Equal(t, events.TransactionCreated, outboxEvents[1].EventType)
+		transactionCreated := ParseOutboxPayload(t, outboxEvents[1])
+		assert.Equal(t, response["id"], transactionCreated["transaction_id"])
+		assert.Equal(t, 100.00, transactionCreated["amount"])
+
+		assert.Equal(t, events.BalanceChanged, outboxEvents[2].EventType)
+		balanceChanged := ParseOutboxPayload(t, outboxEvents[2])
+		assert.Equal(t, 0.0, balanceChanged["previous_balance"])
+		assert.Equal(t, 100.00, balanceChanged["balance"])
+	})
+
+	t.Run("refused transactions should record no event", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.Withdrawal,
+			"amount":         100.00,
+		})
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = POST(t, "/transactions/batch", map[string]any{
+			"mode": "atomic",
+			"items": []map[string]any{
+				{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 10.00},
+				{"account_id": accountID, "operation_type": models.Withdrawal, "amount": 50.00},
+			},
+		})
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		assert.Len(t, GetOutboxEvents(t, accountID), 1)
+	})
+
+	t.Run("dispatcher should publish the events of each account in order", func(t *testing.T) {
+		CleanupTables(t)
+
+		firstAccountID := createTestAccount(t, TestDocument)
+		secondAccountID := createTestAccount(t, TestSecondDocument)
+
+		for _, accountID := range []string{firstAccountID, secondAccountID, firstAccountID} {
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         10.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		publisher := &recordingPublisher{}
+		dispatcher := events.NewDispatcher(repository.NewOutboxRepository(DB), publisher, 100, 3)
+
+		result, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 8, result.Dispatched)
+
+		publishedPerAccount := map[string][]string{}
+		for _, event := range publisher.Published() {
+			accountID := event.AccountID.String()
+			publishedPerAccount[accountID] = append(publishedPerAccount[accountID], event.ID.String())
+		}
+
+		for _, accountID := range []string{firstAccountID, secondAccountID} {
+			var expected []string
+			for _, outboxEvent := range GetOutboxEvents(t, accountID) {
+				assert.Equal(t, models.OutboxEventDispatched, outboxEvent.Status)
+				assert.NotNil(t, outboxEvent.DispatchedAt)
+
+				expected = append(expected, outboxEvent.EventID.String())
+			}
+
+			assert.Equal(t, expected, publishedPerAccount[accountID])
+		}
+
+		result, err = dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, result.Dispatched)
+	})
+
+	t.Run("failed event should hold back the events after it on the same account", func(t *testing.T) {
+		CleanupTables(t)
+
+		failingAccountID := createTestAccount(t, TestDocument)
+		otherAccountID := createTestAccount(t, TestSecondDocument)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     failingAccountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         10.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		publisher := &recordingPublisher{fail: func(event events.Event) error {
+			if event.AccountID.String() == failingAccountID {
+				return errors.New("downstream unavailable")
+			}
+
+			return nil
+		}}
+		dispatcher := events.NewDispatcher(repository.NewOutboxRepository(DB), publisher, 100, 3)
+
+		result, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Dispatched)
+		assert.Equal(t, 1, result.Retried)
+
+		failingEvents := GetOutboxEvents(t, failingAccountID)
+		require.Len(t, failingEvents, 3)
+
+		assert.Equal(t, models.OutboxEventPending, failingEvents[0].Status)
+		assert.Equal(t, 1, failingEvents[0].Attempts)
+		assert.Equal(t, "downstream unavailable", *failingEvents[0].LastError)
+		assert.True(t, failingEvents[0].NextAttemptAt.After(time.Now()))
+
+		for _, outboxEvent := range failingEvents[1:] {
+			assert.Equal(t, models.OutboxEventPending, outboxEvent.Status)
+			assert.Zero(t, outboxEvent.Attempts)
+		}
+
+		assert.Equal(t, models.OutboxEventDispatched, GetOutboxEvents(t, otherAccountID)[0].Status)
+	})
+
+	t.Run("event failing max attempts times should be dead lettered", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         10.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		poisonEventID := GetOutboxEvents(t, accountID)[0].EventID.String()
+
+		publisher := &recordingPublisher{fail: func(event events.Event) error {
+			if event.ID.String() == poisonEventID {
+				return errors.New("payload rejected")
+			}
+
+			return nil
+		}}
+		dispatcher := events.NewDispatcher(repository.NewOutboxRepository(DB), publisher, 100, 2)
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+
+		MakeOutboxEventsDue(t)
+
+		result, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.DeadLettered)
+		assert.Equal(t, 2, result.Dispatched)
+
+		outboxEvents := GetOutboxEvents(t, accountID)
+		assert.Equal(t, models.OutboxEventDeadLetter, outboxEvents[0].Status)
+		assert.Equal(t, 2, outboxEvents[0].Attempts)
+		assert.Equal(t, models.OutboxEventDispatched, outboxEvents[1].Status)
+		assert.Equal(t, models.OutboxEventDispatched, outboxEvents[2].Status)
+	})
+}
@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestReconciliation(t *testing.T) {
+	t.Run("Reconcile", func(t *testing.T) {
+		t.Run("with balances matching the transactions should report no drifts", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			report, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{})
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, report.AccountsChecked)
+			assert.Empty(t, report.Drifts)
+		})
+
+		t.Run("with a drifted balance should record the drift and block the account", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			SetBalance(t, accountID, 15000)
+
+			report, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{
+				BlockAccounts: true,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, report.Drifts, 1)
+			drift := report.Drifts[0]
+			assert.Equal(t, accountID, drift.CustomerAccountID.String())
+			assert.Equal(t, int64(15000), drift.Balance)
+			assert.Equal(t, int64(10000), drift.TransactionsTotal)
+			assert.Equal(t, int64(5000), drift.Drift)
+			assert.True(t, drift.AccountBlocked)
+
+			account := AssertCustomerAccountExistsByID(t, accountID)
+			assert.NotNil(t, account.BlockedAt)
+
+			blockedResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         10.00,
+			})
+			assert.Equal(t, http.StatusLocked, blockedResp.StatusCode)
+		})
+	})
+
+	t.Run("GET /reconciliations/drifts", func(t *testing.T) {
+		t.Run("with repeated runs should keep a single open drift per account", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			SetBalance(t, accountID, 100)
+
+			first, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{})
+			require.NoError(t, err)
+			require.Len(t, first.Drifts, 1)
+
+			SetBalance(t, accountID, 300)
+
+			second, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{
+				BlockAccounts: true,
+			})
+			require.NoError(t, err)
+			require.Len(t, second.Drifts, 1)
+			assert.Equal(t, first.Drifts[0].ID, second.Drifts[0].ID)
+
+			resp, body := GET(t, "/reconciliations/drifts?status=open")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response []map[string]any
+			ParseJSON(t, body, &response)
+			require.Len(t, response, 1)
+			assert.Equal(t, 3.0, response[0]["drift"])
+			assert.Equal(t, true, response[0]["account_blocked"])
+		})
+
+		t.Run("should list drifts filtered by status", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			SetBalance(t, accountID, 100)
+
+			_, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{})
+			require.NoError(t, err)
+
+			resp, body := GET(t, "/reconciliations/drifts?status=open")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response []map[string]any
+			ParseJSON(t, body, &response)
+			require.Len(t, response, 1)
+			assert.Equal(t, accountID, response[0]["account_id"])
+			assert.Equal(t, 1.0, response[0]["drift"])
+			assert.Equal(t, false, response[0]["account_blocked"])
+
+			resolvedResp, resolvedBody := GET(t, "/reconciliations/drifts?status=resolved")
+			require.Equal(t, http.StatusOK, resolvedResp.StatusCode)
+
+			var resolvedResponse []map[string]any
+			ParseJSON(t, resolvedBody, &resolvedResponse)
+			assert.Empty(t, resolvedResponse)
+		})
+
+		t.Run("with invalid status should return bad request", func(t *testing.T) {
+			resp, _ := GET(t, "/reconciliations/drifts?status=unknown")
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+
+	t.Run("POST /reconciliations/drifts/:id/resolve", func(t *testing.T) {
+		t.Run("should resolve the drift and unblock the account", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			SetBalance(t, accountID, 100)
+
+			report, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{
+				BlockAccounts: true,
+			})
+			require.NoError(t, err)
+			require.Len(t, report.Drifts, 1)
+
+			SetBalance(t, accountID, 0)
+
+			resp, body := POST(t, "/reconciliations/drifts/"+report.Drifts[0].ID.String()+"/resolve", nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Equal(t, string(models.ReconciliationDriftResolved), response["status"])
+			assert.NotEmpty(t, response["resolved_at"])
+
+			account := AssertCustomerAccountExistsByID(t, accountID)
+			assert.Nil(t, account.BlockedAt)
+
+			creditResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         10.00,
+			})
+			assert.Equal(t, http.StatusOK, creditResp.StatusCode)
+		})
+
+		t.Run("with the balance still drifted should return conflict and keep the account blocked", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			SetBalance(t, accountID, 100)
+
+			report, err := newReconciliationService().Reconcile(context.Background(), reconciliation.ReconcileRequest{
+				BlockAccounts: true,
+			})
+			require.NoError(t, err)
+			require.Len(t, report.Drifts, 1)
+
+			resp, _ := POST(t, "/reconciliations/drifts/"+report.Drifts[0].ID.String()+"/resolve", nil)
+			assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+			account := AssertCustomerAccountExistsByID(t, accountID)
+			assert.NotNil(t, account.BlockedAt)
+
+			listResp, listBody := GET(t, "/reconciliations/drifts?status=open")
+			require.Equal(t, http.StatusOK, listResp.StatusCode)
+
+			var response []map[string]any
+			ParseJSON(t, listBody, &response)
+			assert.Len(t, response, 1)
+		})
+
+		t.Run("with non-existent drift should return not found", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POST(t, "/reconciliations/drifts/00000000-0000-0000-0000-000000000000/resolve", nil)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/schedules"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+func newSchedulesService(bunDB *bun.DB, options schedules.Options) schedules.Servicer {
+	return schedules.NewService(
+		repository.NewTransactionScheduleRepository(bunDB),
+		repository.NewCustomerAccountRepository(bunDB),
+		newTransactionsService(bunDB, transactions.Options{}),
+		OperationTypes,
+		options,
+	)
+}
+
+func createTestSchedule(t *testing.T, body map[string]any) schedules.ScheduleResponse {
+	t.Helper()
+
+	resp, respBody := POST(t, "/schedules", body)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(respBody))
+
+	var schedule schedules.ScheduleResponse
+	ParseJSON(t, respBody, &schedule)
+
+	return schedule
+}
+
+func getTestSchedule(t *testing.T, scheduleID string) schedules.ScheduleResponse {
+	t.Helper()
+
+	resp, body := GET(t, "/schedules/"+scheduleID)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var schedule schedules.ScheduleResponse
+	ParseJSON(t, body, &schedule)
+
+	return schedule
+}
+
+// MakeScheduleDue lets the next occurrence of the schedule run now.
+func MakeScheduleDue(t *testing.T, scheduleID string) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Table("transaction_schedule").
+		Set("next_run_at = NOW() - INTERVAL '1 second'").
+		Where("id = ?", scheduleID).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
+
+// ShiftScheduleStart moves the start of the schedule, as if it had been
+// created that long before.
+func ShiftScheduleStart(t *testing.T, scheduleID string, shift time.Duration) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Table("transaction_schedule").
+		Set("start_at = start_at + make_interval(secs => ?)", shift.Seconds()).
+		Where("id = ?", scheduleID).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,385 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/schedules"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestSchedules(t *testing.T) {
+	startAt := func() string {
+		return time.Now().Add(time.Hour).Format(time.RFC3339)
+	}
+
+	t.Run("should refuse invalid schedules", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		valid := func(overrides map[string]any) map[string]any {
+			body := map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+				"start_at":       startAt(),
+			}
+			for key, value := range overrides {
+				body[key] = value
+			}
+
+			return body
+		}
+
+		for name, body := range map[string]map[string]any{
+			"missing start":          valid(map[string]any{"start_at": nil}),
+			"start in the past":      valid(map[string]any{"start_at": time.Now().Add(-time.Hour).Format(time.RFC3339)}),
+			"unknown operation type": valid(map[string]any{"operation_type": "UNKNOWN"}),
+			"invalid rule":           valid(map[string]any{"rrule": "FREQ=HOURLY"}),
+			"rule without occurrences": valid(map[string]any{
+				"rrule": "FREQ=DAILY;UNTIL=20000101",
+			}),
+			"unknown policy":        valid(map[string]any{"insufficient_funds_policy": "ignore"}),
+			"amount below one cent": valid(map[string]any{"amount": 0.001}),
+		} {
+			resp, respBody := POST(t, "/schedules", body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%s: %s", name, respBody)
+		}
+
+		unknownAccountID, err := uuid.NewV4()
+		require.NoError(t, err)
+
+		resp, _ := POST(t, "/schedules", valid(map[string]any{"account_id": unknownAccountID}))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should post a one-off schedule once it's due", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+			"start_at":       startAt(),
+		})
+		assert.Equal(t, models.TransactionScheduleActive, schedule.Status)
+		assert.Equal(t, models.InsufficientFundsRetry, schedule.InsufficientFundsPolicy)
+		assert.Nil(t, schedule.RRule)
+
+		result, err := service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{}, result)
+		assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err = service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Created: 1}, result)
+
+		transaction := AssertTransactionExistsWithIdempotencyKey(t, fmt.Sprintf("schedule:%s:0", schedule.ID))
+		AssertBalanceEquals(t, accountID, 10000)
+
+		schedule = getTestSchedule(t, schedule.ID.String())
+		assert.Equal(t, models.TransactionScheduleCompleted, schedule.Status)
+		assert.Nil(t, schedule.NextRunAt)
+		require.Len(t, schedule.Runs, 1)
+		assert.Equal(t, models.TransactionScheduleRunCreated, schedule.Runs[0].Status)
+		assert.Equal(t, transaction.ID, schedule.Runs[0].TransactionID)
+
+		result, err = service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{}, result)
+		assert.Equal(t, 1, CountTransactionsForAccount(t, accountID))
+	})
+
+	t.Run("should post each occurrence of a recurring schedule", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         10.00,
+			"start_at":       startAt(),
+			"rrule":          "FREQ=DAILY;COUNT=3",
+		})
+
+		for occurrence := range 3 {
+			MakeScheduleDue(t, schedule.ID.String())
+
+			result, err := service.RunDue(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, schedules.RunDueResult{Created: 1}, result)
+
+			AssertTransactionExistsWithIdempotencyKey(t, fmt.Sprintf("schedule:%s:%d", schedule.ID, occurrence))
+		}
+
+		AssertBalanceEquals(t, accountID, 3000)
+
+		fetched := getTestSchedule(t, schedule.ID.String())
+		assert.Equal(t, models.TransactionScheduleCompleted, fetched.Status)
+		require.Len(t, fetched.Runs, 3)
+
+		for occurrence, run := range fetched.Runs {
+			assert.Equal(t, occurrence, run.Occurrence)
+			assert.WithinDuration(t, schedule.StartAt.AddDate(0, 0, occurrence), run.ScheduledFor, time.Second)
+		}
+	})
+
+	t.Run("should not post an occurrence twice", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+			"start_at":       startAt(),
+		})
+
+		// A worker that posted the occurrence but died before recording it.
+		idempotencyKey := fmt.Sprintf("schedule:%s:0", schedule.ID)
+		posted, err := newTransactionsService(DB, transactions.Options{}).
+			CreateTransaction(context.Background(), transactions.CreateTransactionRequest{
+				CustomerAccountID: schedule.CustomerAccountID,
+				OperationType:     models.CreditVoucher,
+				Amount:            100.00,
+				IdempotencyKey:    &idempotencyKey,
+			})
+		require.NoError(t, err)
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err := service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Created: 1}, result)
+
+		assert.Equal(t, 1, CountTransactionsForAccount(t, accountID))
+		AssertBalanceEquals(t, accountID, 10000)
+
+		fetched := getTestSchedule(t, schedule.ID.String())
+		require.Len(t, fetched.Runs, 1)
+		assert.Equal(t, posted.ID, fetched.Runs[0].TransactionID)
+	})
+
+	t.Run("should skip an occurrence the account can't afford under the skip policy", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":                accountID,
+			"operation_type":            models.Withdrawal,
+			"amount":                    50.00,
+			"start_at":                  startAt(),
+			"rrule":                     "FREQ=MONTHLY;BYMONTHDAY=5",
+			"insufficient_funds_policy": models.InsufficientFundsSkip,
+		})
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err := service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Skipped: 1}, result)
+		assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+
+		fetched := getTestSchedule(t, schedule.ID.String())
+		assert.Equal(t, models.TransactionScheduleActive, fetched.Status)
+		assert.Equal(t, 1, fetched.NextOccurrence)
+		require.NotNil(t, fetched.NextRunAt)
+		assert.True(t, fetched.NextRunAt.After(time.Now()))
+		require.Len(t, fetched.Runs, 1)
+		assert.Equal(t, models.TransactionScheduleRunSkipped, fetched.Runs[0].Status)
+		require.NotNil(t, fetched.Runs[0].Error)
+		assert.Contains(t, *fetched.Runs[0].Error, "Insufficient funds")
+	})
+
+	t.Run("should retry an occurrence the account can't afford under the retry policy", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{MaxAttempts: 3, RetryInterval: time.Hour})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.Withdrawal,
+			"amount":         50.00,
+			"start_at":       startAt(),
+		})
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err := service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Retried: 1}, result)
+
+		fetched := getTestSchedule(t, schedule.ID.String())
+		assert.Equal(t, models.TransactionScheduleActive, fetched.Status)
+		assert.Equal(t, 1, fetched.Attempts)
+		assert.NotNil(t, fetched.LastError)
+		require.NotNil(t, fetched.NextRunAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *fetched.NextRunAt, time.Minute)
+		assert.Empty(t, fetched.Runs)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         80.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err = service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Created: 1}, result)
+		AssertBalanceEquals(t, accountID, 3000)
+
+		fetched = getTestSchedule(t, schedule.ID.String())
+		assert.Equal(t, models.TransactionScheduleCompleted, fetched.Status)
+		require.Len(t, fetched.Runs, 1)
+		assert.Equal(t, models.TransactionScheduleRunCreated, fetched.Runs[0].Status)
+		assert.Equal(t, 2, fetched.Runs[0].Attempts)
+	})
+
+	t.Run("should fail an occurrence that runs out of attempts", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{MaxAttempts: 2})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.Withdrawal,
+			"amount":         50.00,
+			"start_at":       startAt(),
+		})
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err := service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Retried: 1}, result)
+
+		MakeScheduleDue(t, schedule.ID.String())
+
+		result, err = service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{Failed: 1}, result)
+
+		fetched := getTestSchedule(t, schedule.ID.String())
+		assert.Equal(t, models.TransactionScheduleCompleted, fetched.Status)
+		require.Len(t, fetched.Runs, 1)
+		assert.Equal(t, models.TransactionScheduleRunFailed, fetched.Runs[0].Status)
+		assert.Equal(t, 2, fetched.Runs[0].Attempts)
+		assert.Nil(t, fetched.Runs[0].TransactionID)
+	})
+
+	t.Run("should pause, resume and cancel schedules", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newSchedulesService(DB, schedules.Options{})
+		accountID := createTestAccount(t, TestDocument)
+
+		schedule := createTestSchedule(t, map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         10.00,
+			"start_at":       startAt(),
+			"rrule":          "FREQ=DAILY",
+		})
+		schedulePath := "/schedules/" + schedule.ID.String()
+
+		resp, body := POST(t, schedulePath+"/pause", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var paused schedules.ScheduleResponse
+		ParseJSON(t, body, &paused)
+		assert.Equal(t, models.TransactionSchedulePaused, paused.Status)
+		assert.Nil(t, paused.NextRunAt)
+
+		resp, _ = POST(t, schedulePath+"/pause", nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// Three days go by while the schedule is paused.
+		ShiftScheduleStart(t, schedule.ID.String(), -72*time.Hour)
+
+		resp, body = POST(t, schedulePath+"/resume", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var resumed schedules.ScheduleResponse
+		ParseJSON(t, body, &resumed)
+		assert.Equal(t, models.TransactionScheduleActive, resumed.Status)
+		assert.Equal(t, 3, resumed.NextOccurrence)
+		require.NotNil(t, resumed.NextRunAt)
+		assert.WithinDuration(t, schedule.StartAt, *resumed.NextRunAt, time.Second)
+
+		result, err := service.RunDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, schedules.RunDueResult{}, result)
+
+		resp, body = GET(t, fmt.Sprintf("/schedules?account_id=%s&status=active", accountID))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var list schedules.SchedulesResponse
+		ParseJSON(t, body, &list)
+		require.Len(t, list.Schedules, 1)
+		assert.Equal(t, schedule.ID, list.Schedules[0].ID)
+
+		resp, body = POST(t, schedulePath+"/cancel", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var cancelled schedules.ScheduleResponse
+		ParseJSON(t, body, &cancelled)
+		assert.Equal(t, models.TransactionScheduleCancelled, cancelled.Status)
+		assert.Nil(t, cancelled.NextRunAt)
+
+		resp, _ = POST(t, schedulePath+"/resume", nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp, _ = POST(t, schedulePath+"/cancel", nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp, body = GET(t, "/schedules?status=active")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		ParseJSON(t, body, &list)
+		assert.Empty(t, list.Schedules)
+
+		resp, _ = GET(t, "/schedules?status=unknown")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should return not found for unknown schedules", func(t *testing.T) {
+		CleanupTables(t)
+
+		scheduleID, err := uuid.NewV4()
+		require.NoError(t, err)
+
+		resp, _ := GET(t, "/schedules/"+scheduleID.String())
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = POST(t, fmt.Sprintf("/schedules/%s/pause", scheduleID), nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = GET(t, "/schedules/invalid")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/statements"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+// StatementsLocation is where statement periods start and end.
+var StatementsLocation, _ = time.LoadLocation("America/Sao_Paulo")
+
+// TestCorporateDocument is a valid CNPJ, for accounts of companies.
+const TestCorporateDocument = "11222333000181"
+
+// camt053Schema is the ISO 20022 schema of camt.053.001.08 statements.
+const camt053Schema = "testdata/camt.053.001.08.xsd"
+
+func newStatementsService(bunDB *bun.DB) statements.Servicer {
+	return statements.NewService(
+		repository.NewTransactionRepository(bunDB),
+		repository.NewBalanceSnapshotRepository(bunDB),
+		repository.NewCustomerAccountRepository(bunDB),
+		OperationTypes,
+		statements.Options{Location: StatementsLocation},
+	)
+}
+
+// SetTransactionCreatedAt moves the transaction to createdAt.
+func SetTransactionCreatedAt(t *testing.T, transactionID string, createdAt time.Time) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Table("transactions").
+		Set("created_at = ?", createdAt).
+		Where("id = ?", transactionID).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
+
+// AssertValidCAMT053 validates the document against the camt.053 schema, with
+// xmllint since Go has no XML Schema validator.
+func AssertValidCAMT053(t *testing.T, document []byte) {
+	t.Helper()
+
+	xmllint, err := exec.LookPath("xmllint")
+	require.NoError(t, err, "xmllint (libxml2) is needed to validate camt.053 statements")
+
+	cmd := exec.Command(xmllint, "--noout", "--schema", camt053Schema, "-")
+	cmd.Stdin = bytes.NewReader(document)
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "camt.053 statement doesn't match the schema: %s", output)
+}
@@ -0,0 +1,366 @@
+package integration
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/statements"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/ofx"
+)
+
+func TestStatements(t *testing.T) {
+	now := time.Now().In(StatementsLocation)
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, StatementsLocation).AddDate(0, -1, 0)
+	period := periodStart.Format("2006-01")
+
+	postTransaction := func(t *testing.T, accountID string, operationType models.OperationType, amount float64, createdAt time.Time) string {
+		t.Helper()
+
+		resp, body := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": operationType,
+			"amount":         amount,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var transaction map[string]any
+		ParseJSON(t, body, &transaction)
+
+		transactionID := transaction["id"].(string)
+		SetTransactionCreatedAt(t, transactionID, createdAt)
+
+		return transactionID
+	}
+
+	// setupAccount creates an account with a credit before the period, a credit
+	// and a withdrawal within it and a credit after it.
+	setupAccount := func(t *testing.T, document string) (string, []string) {
+		t.Helper()
+
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, document)
+
+		postTransaction(t, accountID, models.CreditVoucher, 100.00, periodStart.Add(-time.Hour))
+		transactionIDs := []string{
+			postTransaction(t, accountID, models.CreditVoucher, 50.00, periodStart.AddDate(0, 0, 1)),
+			postTransaction(t, accountID, models.Withdrawal, 30.00, periodStart.AddDate(0, 0, 2)),
+		}
+		postTransaction(t, accountID, models.CreditVoucher, 5.00, periodStart.AddDate(0, 1, 0))
+
+		return accountID, transactionIDs
+	}
+
+	t.Run("should return the statement of a month", func(t *testing.T) {
+		accountID, transactionIDs := setupAccount(t, TestDocument)
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements/%s", accountID, period))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var statement statements.StatementResponse
+		ParseJSON(t, body, &statement)
+
+		assert.Equal(t, accountID, statement.AccountID.String())
+		assert.Equal(t, TestDocument, statement.Document)
+		assert.Equal(t, period, statement.Period)
+		assert.True(t, periodStart.Equal(statement.From))
+		assert.True(t, periodStart.AddDate(0, 1, 0).Equal(statement.To))
+		assert.Equal(t, 100.00, statement.OpeningBalance)
+		assert.Equal(t, 120.00, statement.ClosingBalance)
+		assert.Equal(t, 50.00, statement.TotalCredits)
+		assert.Equal(t, -30.00, statement.TotalDebits)
+
+		require.Len(t, statement.Transactions, 2)
+		assert.Equal(t, transactionIDs[0], statement.Transactions[0].TransactionID.String())
+		assert.Equal(t, models.CreditVoucher, statement.Transactions[0].OperationType)
+		assert.Equal(t, 50.00, statement.Transactions[0].Amount)
+		assert.Equal(t, 150.00, statement.Transactions[0].Balance)
+		assert.Equal(t, transactionIDs[1], statement.Transactions[1].TransactionID.String())
+		assert.Equal(t, models.Withdrawal, statement.Transactions[1].OperationType)
+		assert.Equal(t, -30.00, statement.Transactions[1].Amount)
+		assert.Equal(t, 120.00, statement.Transactions[1].Balance)
+
+		require.Len(t, statement.Totals, 2)
+		assert.Equal(t, models.CreditVoucher, statement.Totals[0].OperationType)
+		assert.Equal(t, 1, statement.Totals[0].Count)
+		assert.Equal(t, 50.00, statement.Totals[0].Amount)
+		assert.Equal(t, models.Withdrawal, statement.Totals[1].OperationType)
+		assert.Equal(t, 1, statement.Totals[1].Count)
+		assert.Equal(t, -30.00, statement.Totals[1].Amount)
+	})
+
+	t.Run("should return an empty statement for a month without transactions", func(t *testing.T) {
+		accountID, _ := setupAccount(t, TestDocument)
+
+		before := periodStart.AddDate(0, -2, 0).Format("2006-01")
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements/%s", accountID, before))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var statement statements.StatementResponse
+		ParseJSON(t, body, &statement)
+
+		assert.Equal(t, 0.00, statement.OpeningBalance)
+		assert.Equal(t, 0.00, statement.ClosingBalance)
+		assert.Empty(t, statement.Transactions)
+		assert.Empty(t, statement.Totals)
+	})
+
+	t.Run("should return the statement as CSV", func(t *testing.T) {
+		accountID, transactionIDs := setupAccount(t, TestDocument)
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements/%s?format=csv", accountID, period))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Equal(t,
+			fmt.Sprintf(`attachment; filename="statement-%s-%s.csv"`, accountID, period),
+			resp.Header.Get("Content-Disposition"),
+		)
+
+		rows, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 7)
+
+		assert.Equal(t, "record_type", rows[0][0])
+		assert.Equal(t, []string{"opening_balance", periodStart.Format(time.RFC3339), "", "", "Opening balance", "", "100.00", ""}, rows[1])
+		assert.Equal(t, "transaction", rows[2][0])
+		assert.Equal(t, transactionIDs[0], rows[2][2])
+		assert.Equal(t, []string{"50.00", "150.00"}, rows[2][5:7])
+		assert.Equal(t, "transaction", rows[3][0])
+		assert.Equal(t, transactionIDs[1], rows[3][2])
+		assert.Equal(t, []string{"-30.00", "120.00"}, rows[3][5:7])
+		assert.Equal(t, "closing_balance", rows[4][0])
+		assert.Equal(t, "120.00", rows[4][6])
+		assert.Equal(t, []string{"total", "", "", string(models.CreditVoucher)}, rows[5][:4])
+		assert.Equal(t, []string{"50.00", "", "1"}, rows[5][5:])
+		assert.Equal(t, []string{"total", "", "", string(models.Withdrawal)}, rows[6][:4])
+		assert.Equal(t, []string{"-30.00", "", "1"}, rows[6][5:])
+	})
+
+	t.Run("should return the statement as PDF", func(t *testing.T) {
+		accountID, _ := setupAccount(t, TestDocument)
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements/%s?format=pdf", accountID, period))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+		assert.Equal(t,
+			fmt.Sprintf(`attachment; filename="statement-%s-%s.pdf"`, accountID, period),
+			resp.Header.Get("Content-Disposition"),
+		)
+		assert.True(t, strings.HasPrefix(string(body), "%PDF-"))
+		assert.True(t, strings.HasSuffix(string(body), "%%EOF\n"))
+		assert.Contains(t, string(body), "(120.00)")
+	})
+
+	t.Run("should return the statement of a range of days", func(t *testing.T) {
+		accountID, transactionIDs := setupAccount(t, TestDocument)
+
+		from := periodStart.AddDate(0, 0, 2).Format("2006-01-02")
+		to := periodStart.AddDate(0, 1, 0).Format("2006-01-02")
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements?from=%s&to=%s", accountID, from, to))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var statement statements.StatementResponse
+		ParseJSON(t, body, &statement)
+
+		assert.Empty(t, statement.Period)
+		assert.Equal(t, "BRL", statement.Currency)
+		assert.True(t, periodStart.AddDate(0, 0, 2).Equal(statement.From))
+		assert.True(t, periodStart.AddDate(0, 1, 1).Equal(statement.To))
+		assert.Equal(t, 150.00, statement.OpeningBalance)
+		assert.Equal(t, 125.00, statement.ClosingBalance)
+
+		require.Len(t, statement.Transactions, 2)
+		assert.Equal(t, transactionIDs[1], statement.Transactions[0].TransactionID.String())
+		assert.Equal(t, 120.00, statement.Transactions[0].Balance)
+		assert.Equal(t, 125.00, statement.Transactions[1].Balance)
+	})
+
+	t.Run("should export the statement as OFX", func(t *testing.T) {
+		accountID, transactionIDs := setupAccount(t, TestDocument)
+
+		from := periodStart.Format("2006-01-02")
+		to := periodStart.AddDate(0, 1, -1).Format("2006-01-02")
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements?from=%s&to=%s&format=ofx", accountID, from, to))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "application/x-ofx", resp.Header.Get("Content-Type"))
+		assert.Equal(t,
+			fmt.Sprintf(`attachment; filename="statement-%s-%s_%s.ofx"`, accountID, from, to),
+			resp.Header.Get("Content-Disposition"),
+		)
+		assert.True(t, strings.HasPrefix(string(body), `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`))
+		assert.Contains(t, string(body), `<?OFX OFXHEADER="200" VERSION="220"`)
+
+		parsed, err := ofx.Parse(strings.NewReader(string(body)))
+		require.NoError(t, err)
+		require.Len(t, parsed, 1)
+
+		statement := parsed[0]
+		assert.LessOrEqual(t, len(statement.AccountID), ofx.MaxAccountIDLength)
+		decodedAccountID, ok := ofx.DecodeAccountID(statement.AccountID)
+		require.True(t, ok)
+		assert.Equal(t, accountID, decodedAccountID.String())
+		assert.Equal(t, "BRL", statement.Currency)
+		require.NotNil(t, statement.StartAt)
+		assert.True(t, periodStart.Equal(*statement.StartAt))
+		require.NotNil(t, statement.LedgerBalance)
+		assert.Equal(t, "120.00", statement.LedgerBalance.Amount)
+
+		require.Len(t, statement.Transactions, 2)
+		assert.Equal(t, "CREDIT", statement.Transactions[0].Type)
+		assert.Equal(t, transactionIDs[0], statement.Transactions[0].FITID)
+		assert.Equal(t, "50.00", statement.Transactions[0].Amount)
+		require.NotNil(t, statement.Transactions[0].PostedAt)
+		assert.True(t, periodStart.AddDate(0, 0, 1).Equal(*statement.Transactions[0].PostedAt))
+		assert.Equal(t, "ATM", statement.Transactions[1].Type)
+		assert.Equal(t, transactionIDs[1], statement.Transactions[1].FITID)
+		assert.Equal(t, "-30.00", statement.Transactions[1].Amount)
+	})
+
+	t.Run("OFX statement should be importable back", func(t *testing.T) {
+		accountID, _ := setupAccount(t, TestDocument)
+
+		from := periodStart.Format("2006-01-02")
+		to := periodStart.AddDate(0, 1, -1).Format("2006-01-02")
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements?from=%s&to=%s&format=ofx", accountID, from, to))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		importResp, importBody := POSTFile(t, "/imports", "statement.ofx", string(body), map[string]string{"dry_run": "true"})
+		require.Equal(t, http.StatusOK, importResp.StatusCode, string(importBody))
+
+		var report ImportResponse
+		ParseJSON(t, importBody, &report)
+
+		require.Len(t, report.Rows, 2)
+		for _, row := range report.Rows {
+			assert.Equal(t, accountID, row.AccountID)
+			assert.Empty(t, row.Errors)
+		}
+
+		assert.Equal(t, string(models.CreditVoucher), report.Rows[0].OperationType)
+		assert.Equal(t, string(models.Withdrawal), report.Rows[1].OperationType)
+	})
+
+	t.Run("should export the statement as camt.053", func(t *testing.T) {
+		accountID, transactionIDs := setupAccount(t, TestCorporateDocument)
+
+		from := periodStart.Format("2006-01-02")
+		to := periodStart.AddDate(0, 1, -1).Format("2006-01-02")
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements?from=%s&to=%s&format=camt053", accountID, from, to))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "application/xml", resp.Header.Get("Content-Type"))
+		assert.Equal(t,
+			fmt.Sprintf(`attachment; filename="statement-%s-%s_%s.xml"`, accountID, from, to),
+			resp.Header.Get("Content-Disposition"),
+		)
+
+		AssertValidCAMT053(t, body)
+
+		var document struct {
+			Statement struct {
+				AccountID string `xml:"Acct>Id>Othr>Id"`
+				Currency  string `xml:"Acct>Ccy"`
+				Owner     string `xml:"Acct>Ownr>Id>OrgId>Othr>Id"`
+				Balances  []struct {
+					Type      string `xml:"Tp>CdOrPrtry>Cd"`
+					Amount    string `xml:"Amt"`
+					Indicator string `xml:"CdtDbtInd"`
+					Date      string `xml:"Dt>Dt"`
+				} `xml:"Bal"`
+				Entries []struct {
+					Reference string `xml:"NtryRef"`
+					Amount    string `xml:"Amt"`
+					Indicator string `xml:"CdtDbtInd"`
+					Status    string `xml:"Sts>Cd"`
+					BookedAt  string `xml:"BookgDt>DtTm"`
+					ValueDate string `xml:"ValDt>Dt"`
+					Code      string `xml:"BkTxCd>Prtry>Cd"`
+				} `xml:"Ntry"`
+			} `xml:"BkToCstmrStmt>Stmt"`
+		}
+		require.NoError(t, xml.Unmarshal(body, &document))
+
+		statement := document.Statement
+		assert.Equal(t, strings.ReplaceAll(accountID, "-", ""), statement.AccountID)
+		assert.Equal(t, "BRL", statement.Currency)
+		assert.Equal(t, TestCorporateDocument, statement.Owner)
+
+		require.Len(t, statement.Balances, 2)
+		assert.Equal(t, "OPBD", statement.Balances[0].Type)
+		assert.Equal(t, "100.00", statement.Balances[0].Amount)
+		assert.Equal(t, "CRDT", statement.Balances[0].Indicator)
+		assert.Equal(t, from, statement.Balances[0].Date)
+		assert.Equal(t, "CLBD", statement.Balances[1].Type)
+		assert.Equal(t, "120.00", statement.Balances[1].Amount)
+		assert.Equal(t, "CRDT", statement.Balances[1].Indicator)
+		assert.Equal(t, to, statement.Balances[1].Date)
+
+		require.Len(t, statement.Entries, 2)
+		assert.Equal(t, strings.ReplaceAll(transactionIDs[0], "-", ""), statement.Entries[0].Reference)
+		assert.Equal(t, "50.00", statement.Entries[0].Amount)
+		assert.Equal(t, "CRDT", statement.Entries[0].Indicator)
+		assert.Equal(t, "BOOK", statement.Entries[0].Status)
+		assert.Equal(t, periodStart.AddDate(0, 0, 1).Format(time.RFC3339), statement.Entries[0].BookedAt)
+		assert.Equal(t, periodStart.AddDate(0, 0, 1).Format("2006-01-02"), statement.Entries[0].ValueDate)
+		assert.Equal(t, string(models.CreditVoucher), statement.Entries[0].Code)
+		assert.Equal(t, strings.ReplaceAll(transactionIDs[1], "-", ""), statement.Entries[1].Reference)
+		assert.Equal(t, "30.00", statement.Entries[1].Amount)
+		assert.Equal(t, "DBIT", statement.Entries[1].Indicator)
+		assert.Equal(t, periodStart.AddDate(0, 0, 2).Format("2006-01-02"), statement.Entries[1].ValueDate)
+		assert.Equal(t, string(models.Withdrawal), statement.Entries[1].Code)
+	})
+
+	t.Run("should export a camt.053 statement of a personal account", func(t *testing.T) {
+		accountID, _ := setupAccount(t, TestDocument)
+
+		resp, body := GET(t, fmt.Sprintf("/accounts/%s/statements/%s?format=camt053", accountID, period))
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		AssertValidCAMT053(t, body)
+		assert.Contains(t, string(body), "<PrvtId>")
+		assert.NotContains(t, string(body), "<OrgId>")
+	})
+
+	t.Run("should refuse invalid statement requests", func(t *testing.T) {
+		CleanupTables(t)
+
+		accountID := createTestAccount(t, TestDocument)
+		nextMonth := periodStart.AddDate(0, 2, 0).Format("2006-01")
+
+		for name, path := range map[string]string{
+			"invalid account id": fmt.Sprintf("/accounts/invalid/statements/%s", period),
+			"invalid period":     fmt.Sprintf("/accounts/%s/statements/2026-13", accountID),
+			"unknown format":     fmt.Sprintf("/accounts/%s/statements/%s?format=xlsx", accountID, period),
+			"future period":      fmt.Sprintf("/accounts/%s/statements/%s", accountID, nextMonth),
+			"missing range":      fmt.Sprintf("/accounts/%s/statements", accountID),
+			"invalid range date": fmt.Sprintf("/accounts/%s/statements?from=2026-02-30&to=2026-03-01", accountID),
+			"reversed range":     fmt.Sprintf("/accounts/%s/statements?from=2026-03-02&to=2026-03-01", accountID),
+			"range over a year":  fmt.Sprintf("/accounts/%s/statements?from=2025-01-01&to=2026-01-02", accountID),
+			"future range": fmt.Sprintf(
+				"/accounts/%s/statements?from=%s&to=%s", accountID, nextMonth+"-01", nextMonth+"-02",
+			),
+		} {
+			resp, body := GET(t, path)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%s: %s", name, body)
+		}
+
+		unknownAccountID, err := uuid.NewV4()
+		require.NoError(t, err)
+
+		resp, _ := GET(t, fmt.Sprintf("/accounts/%s/statements/%s", unknownAccountID, period))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -9,8 +10,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tiagovaldrich/accounts-api/db"
 	"github.com/tiagovaldrich/accounts-api/internal/api/accounts"
+	"github.com/tiagovaldrich/accounts-api/internal/api/activity"
+	"github.com/tiagovaldrich/accounts-api/internal/api/auditlogs"
+	"github.com/tiagovaldrich/accounts-api/internal/api/imports"
+	"github.com/tiagovaldrich/accounts-api/internal/api/ledger"
+	"github.com/tiagovaldrich/accounts-api/internal/api/operationtypes"
+	"github.com/tiagovaldrich/accounts-api/internal/api/reconciliation"
+	"github.com/tiagovaldrich/accounts-api/internal/api/schedules"
+	"github.com/tiagovaldrich/accounts-api/internal/api/statements"
 	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/api/webhooks"
 	"github.com/tiagovaldrich/accounts-api/internal/config"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
 	"github.com/tiagovaldrich/accounts-api/internal/repository"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -30,6 +41,7 @@ type testDBConfig struct {
 var (
 	App              *fiber.App
 	DB               *bun.DB
+	OperationTypes   operationtypes.Servicer
 	migrationsFolder string = "../../db/migrations"
 )
 
@@ -44,6 +56,7 @@ func setup() {
 	createTestDatabaseIfNotExists()
 	DB = connectToTestDatabase()
 	db.RunMigrations(DB, &migrationsFolder)
+	OperationTypes = loadOperationTypes(DB)
 	App = setupApp(DB)
 }
 
@@ -132,34 +145,137 @@ func getEnvOrDefaultBool(key string, defaultValue bool) bool {
 }
 
 func setupApp(bunDB *bun.DB) *fiber.App {
-	router := config.NewRouter()
+	router := config.NewRouter(repository.NewIdempotentRequestRepository(bunDB), repository.NewAuditLogRepository(bunDB), config.HTTPConfig{})
 
 	customerRepository := repository.NewCustomerRepository(bunDB)
 	customerAccountRepository := repository.NewCustomerAccountRepository(bunDB)
 	balanceRepository := repository.NewBalanceRepository(bunDB)
-	transactionRepository := repository.NewTransactionRepository(bunDB)
+	balanceSnapshotRepository := repository.NewBalanceSnapshotRepository(bunDB)
+	ledgerRepository := repository.NewLedgerRepository(bunDB)
 
-	accountsService := accounts.NewService(customerRepository, customerAccountRepository, balanceRepository)
-	accounts.NewHTTPHandler(router.GetApp(), accountsService)
-
-	transactionsService := transactions.NewService(
-		transactionRepository,
+	accountsService := accounts.NewService(
+		customerRepository,
 		customerAccountRepository,
 		balanceRepository,
+		balanceSnapshotRepository,
+		repository.NewOutboxRepository(bunDB),
+		repository.NewAuditLogRepository(bunDB),
 	)
+	accounts.NewHTTPHandler(router.GetApp(), accountsService)
+
+	transactionsService := newTransactionsService(bunDB, transactions.Options{})
 	transactions.NewHTTPHandler(router.GetApp(), transactionsService)
 
+	ledgerService := ledger.NewService(ledgerRepository)
+	ledger.NewHTTPHandler(router.GetApp(), ledgerService)
+
+	reconciliation.NewHTTPHandler(router.GetApp(), newReconciliationService())
+
+	operationtypes.NewHTTPHandler(router.GetApp(), OperationTypes)
+
+	importsService := imports.NewService(
+		repository.NewImportFileRepository(bunDB),
+		customerAccountRepository,
+		transactionsService,
+		OperationTypes,
+	)
+	imports.NewHTTPHandler(router.GetApp(), importsService)
+
+	webhooks.NewHTTPHandler(router.GetApp(), newWebhooksService(bunDB, webhooks.Options{}))
+
+	activity.NewHTTPHandler(router.GetApp(), newActivityService(bunDB))
+
+	auditlogs.NewHTTPHandler(router.GetApp(), auditlogs.NewService(repository.NewAuditLogRepository(bunDB)))
+
+	schedules.NewHTTPHandler(router.GetApp(), newSchedulesService(bunDB, schedules.Options{}))
+
+	statements.NewHTTPHandler(router.GetApp(), newStatementsService(bunDB))
+
 	return router.GetApp()
 }
 
-func CleanupTables(t *testing.T) {
+func loadOperationTypes(bunDB *bun.DB) operationtypes.Servicer {
+	operationTypesService := operationtypes.NewService(
+		repository.NewOperationTypeRepository(bunDB),
+		repository.NewLedgerRepository(bunDB),
+	)
+
+	if err := operationTypesService.Load(context.Background()); err != nil {
+		panic(fmt.Sprintf("failed to load operation types: %v", err))
+	}
+
+	return operationTypesService
+}
+
+func newTransactionsService(bunDB *bun.DB, options transactions.Options) transactions.Servicer {
+	return transactions.NewService(
+		repository.NewTransactionRepository(bunDB),
+		repository.NewIdempotencyRecordRepository(bunDB),
+		repository.NewCustomerAccountRepository(bunDB),
+		repository.NewBalanceRepository(bunDB),
+		repository.NewLedgerRepository(bunDB),
+		repository.NewOutboxRepository(bunDB),
+		repository.NewAuditLogRepository(bunDB),
+		OperationTypes,
+		options,
+	)
+}
+
+func newReconciliationService() reconciliation.Servicer {
+	return reconciliation.NewService(
+		repository.NewReconciliationRepository(DB),
+		repository.NewCustomerAccountRepository(DB),
+		repository.NewBalanceRepository(DB),
+		repository.NewOutboxRepository(DB),
+	)
+}
+
+func CleanupTables(t testing.TB) {
 	t.Helper()
 
-	tables := []string{"transactions", "balance", "customer_account", "customer"}
+	tables := []string{"audit_log", "transaction_schedule_run", "transaction_schedule", "balance_adjustment", "balance_rebuild", "reconciliation_drift", "reconciliation_run", "journal_posting", "journal_entry", "idempotency_record", "idempotent_request", "import_file", "outbox_event", "webhook_delivery_attempt", "webhook_delivery", "webhook_subscription", "transactions", "balance_snapshot", "balance", "customer_account", "customer"}
 	for _, table := range tables {
 		_, err := DB.Exec(fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table))
 		if err != nil {
 			t.Fatalf("failed to truncate table %s: %v", table, err)
 		}
 	}
+
+	resetOperationTypes(t)
+}
+
+// resetOperationTypes drops the operation types created by tests and restores
+// the seeded ones, reloading the cache used by the app.
+func resetOperationTypes(t testing.TB) {
+	t.Helper()
+
+	seededOperationTypes := []models.OperationType{
+		models.NormalPurchase,
+		models.PurcharseWithInstallments,
+		models.Withdrawal,
+		models.CreditVoucher,
+	}
+
+	_, err := DB.NewDelete().
+		Model((*models.OperationTypeDefinition)(nil)).
+		Where("code NOT IN (?)", bun.In(seededOperationTypes)).
+		Exec(context.Background())
+	if err != nil {
+		t.Fatalf("failed to delete operation types: %v", err)
+	}
+
+	_, err = DB.NewUpdate().
+		Model((*models.OperationTypeDefinition)(nil)).
+		Set("enabled = TRUE").
+		Set("min_amount = NULL").
+		Set("max_amount = NULL").
+		Where("TRUE").
+		Exec(context.Background())
+	if err != nil {
+		t.Fatalf("failed to reset operation types: %v", err)
+	}
+
+	if err := OperationTypes.Load(context.Background()); err != nil {
+		t.Fatalf("failed to reload operation types: %v", err)
+	}
 }
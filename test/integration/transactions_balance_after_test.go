@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"context"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestTransactionBalanceAfter(t *testing.T) {
+	t.Run("POST /transactions", func(t *testing.T) {
+		t.Run("should store and return the balance after each transaction", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			creditResp, creditBody := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         100.00,
+			})
+			require.Equal(t, http.StatusOK, creditResp.StatusCode)
+
+			var creditResponse map[string]any
+			ParseJSON(t, creditBody, &creditResponse)
+			assert.Equal(t, 100.0, creditResponse["balance_after"])
+
+			purchaseResp, purchaseBody := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.NormalPurchase,
+				"amount":         25.50,
+			})
+			require.Equal(t, http.StatusOK, purchaseResp.StatusCode)
+
+			var purchaseResponse map[string]any
+			ParseJSON(t, purchaseBody, &purchaseResponse)
+			assert.Equal(t, 74.5, purchaseResponse["balance_after"])
+
+			tx := AssertTransactionExists(t, accountID, models.NormalPurchase, -2550)
+			require.NotNil(t, tx.BalanceAfter)
+			assert.Equal(t, int64(7450), *tx.BalanceAfter)
+		})
+	})
+
+	t.Run("GET /transactions/:id", func(t *testing.T) {
+		t.Run("with existing transaction should return it with the balance after", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			createResp, createBody := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         42.00,
+			})
+			require.Equal(t, http.StatusOK, createResp.StatusCode)
+
+			var createResponse map[string]any
+			ParseJSON(t, createBody, &createResponse)
+			transactionID := createResponse["id"].(string)
+
+			resp, body := GET(t, "/transactions/"+transactionID)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Equal(t, transactionID, response["id"])
+			assert.Equal(t, accountID, response["customer_account_id"])
+			assert.Equal(t, string(models.CreditVoucher), response["operation_type"])
+			assert.Equal(t, 42.0, response["amount"])
+			assert.Equal(t, 42.0, response["balance_after"])
+			assert.NotEmpty(t, response["created_at"])
+		})
+
+		t.Run("with non-existent transaction should return not found", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := GET(t, "/transactions/00000000-0000-0000-0000-000000000000")
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+
+		t.Run("with invalid UUID should return bad request", func(t *testing.T) {
+			resp, _ := GET(t, "/transactions/invalid-uuid")
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+
+	t.Run("BackfillBalanceAfter", func(t *testing.T) {
+		t.Run("should compute the running balance of existing history in order", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			for _, payload := range []map[string]any{
+				{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00},
+				{"account_id": accountID, "operation_type": models.Withdrawal, "amount": 30.00},
+				{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 5.00},
+			} {
+				resp, _ := POST(t, "/transactions", payload)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+
+			ClearBalanceAfter(t, accountID)
+
+			result, err := newTransactionsService(DB, transactions.Options{}).BackfillBalanceAfter(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.AccountsProcessed)
+			assert.Equal(t, int64(3), result.TransactionsUpdated)
+
+			var balances []int64
+			for _, transaction := range GetTransactionsForAccount(t, accountID) {
+				require.NotNil(t, transaction.BalanceAfter)
+				balances = append(balances, *transaction.BalanceAfter)
+			}
+			assert.Equal(t, []int64{10000, 7000, 7500}, balances)
+
+			result, err = newTransactionsService(DB, transactions.Options{}).BackfillBalanceAfter(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 0, result.AccountsProcessed)
+		})
+	})
+}
@@ -0,0 +1,228 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestCreateTransactionsBatch(t *testing.T) {
+	t.Run("POST /transactions/batch", func(t *testing.T) {
+		t.Run("atomic batch should create every item", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, body := POST(t, "/transactions/batch", map[string]any{
+				"mode": "atomic",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00},
+					{"account_id": accountID, "operation_type": models.NormalPurchase, "amount": 30.00},
+				},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response BatchResponse
+			ParseJSON(t, body, &response)
+			assert.Equal(t, "atomic", response.Mode)
+			require.Len(t, response.Items, 2)
+
+			for index, item := range response.Items {
+				assert.Equal(t, index, item.Index)
+				assert.Equal(t, http.StatusOK, item.Status)
+				assert.NotEmpty(t, item.Transaction["id"])
+			}
+
+			assert.Equal(t, 70.00, response.Items[1].Transaction["balance_after"])
+			assert.Equal(t, 2, CountTransactionsForAccount(t, accountID))
+			AssertBalanceEquals(t, accountID, 7000)
+		})
+
+		t.Run("atomic batch should roll back every item when one fails", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, body := POST(t, "/transactions/batch", map[string]any{
+				"mode": "atomic",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00},
+					{"account_id": accountID, "operation_type": models.Withdrawal, "amount": 500.00},
+				},
+			})
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var response map[string]any
+			ParseJSON(t, body, &response)
+
+			fieldErrors := response["field_errors"].([]any)
+			require.Len(t, fieldErrors, 1)
+			assert.Equal(t, "items[1]", fieldErrors[0].(map[string]any)["field"])
+
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+			AssertBalanceEquals(t, accountID, 0)
+		})
+
+		t.Run("atomic batch with an invalid item should create nothing", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions/batch", map[string]any{
+				"mode": "atomic",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00},
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": -1},
+				},
+			})
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("independent batch should report a status per item", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, body := POST(t, "/transactions/batch", map[string]any{
+				"mode": "independent",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00},
+					{"account_id": accountID, "operation_type": models.Withdrawal, "amount": 500.00},
+					{"account_id": "00000000-0000-0000-0000-000000000000", "operation_type": models.CreditVoucher, "amount": 10.00},
+					{"account_id": accountID, "operation_type": models.CreditVoucher},
+					{"account_id": accountID, "operation_type": models.NormalPurchase, "amount": 40.00},
+				},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response BatchResponse
+			ParseJSON(t, body, &response)
+			require.Len(t, response.Items, 5)
+
+			statuses := make([]int, 0, len(response.Items))
+			for _, item := range response.Items {
+				statuses = append(statuses, item.Status)
+			}
+
+			assert.Equal(t, []int{
+				http.StatusOK,
+				http.StatusBadRequest,
+				http.StatusNotFound,
+				http.StatusBadRequest,
+				http.StatusOK,
+			}, statuses)
+
+			assert.Nil(t, response.Items[1].Transaction)
+			assert.Equal(t, "Insufficient funds to perform operation", response.Items[1].Error["message"])
+			assert.NotEmpty(t, response.Items[3].Error["field_errors"])
+
+			assert.Equal(t, 2, CountTransactionsForAccount(t, accountID))
+			AssertBalanceEquals(t, accountID, 6000)
+		})
+
+		t.Run("items with an idempotency key should be replayed on retry", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			batch := map[string]any{
+				"mode": "independent",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 100.00, "idempotency_key": "batch-item-1"},
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 50.00},
+				},
+			}
+
+			firstResp, firstBody := POST(t, "/transactions/batch", batch)
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, secondBody := POST(t, "/transactions/batch", batch)
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+
+			var first, second BatchResponse
+			ParseJSON(t, firstBody, &first)
+			ParseJSON(t, secondBody, &second)
+
+			assert.False(t, first.Items[0].Replayed)
+			assert.True(t, second.Items[0].Replayed)
+			assert.Equal(t, first.Items[0].Transaction["id"], second.Items[0].Transaction["id"])
+			assert.False(t, second.Items[1].Replayed)
+
+			assert.Equal(t, 1, CountTransactionsWithIdempotencyKey(t, "batch-item-1"))
+			assert.Equal(t, 3, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("item reusing an idempotency key with a different payload should fail", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":      accountID,
+				"operation_type":  models.CreditVoucher,
+				"amount":          100.00,
+				"idempotency_key": "batch-reused-key",
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			resp, _ = POST(t, "/transactions/batch", map[string]any{
+				"mode": "atomic",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 10.00},
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 20.00, "idempotency_key": "batch-reused-key"},
+				},
+			})
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+			assert.Equal(t, 1, CountTransactionsForAccount(t, accountID))
+		})
+
+		t.Run("with an invalid mode should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, _ := POST(t, "/transactions/batch", map[string]any{
+				"mode": "eventual",
+				"items": []map[string]any{
+					{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 10.00},
+				},
+			})
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("with no items should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			resp, _ := POST(t, "/transactions/batch", map[string]any{
+				"mode":  "atomic",
+				"items": []map[string]any{},
+			})
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("with too many items should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			items := make([]map[string]any, 501)
+			for index := range items {
+				items[index] = map[string]any{"account_id": accountID, "operation_type": models.CreditVoucher, "amount": 1.00}
+			}
+
+			resp, _ := POST(t, "/transactions/batch", map[string]any{
+				"mode":  "independent",
+				"items": items,
+			})
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
+	})
+}
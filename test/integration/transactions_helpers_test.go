@@ -4,13 +4,14 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
 )
 
-func createTestAccount(t *testing.T, document string) string {
+func createTestAccount(t testing.TB, document string) string {
 	t.Helper()
 
 	resp, body := POST(t, "/accounts", map[string]any{"document_number": document})
@@ -77,3 +78,52 @@ func CountTransactionsForAccount(t *testing.T, accountID string) int {
 	require.NoError(t, err)
 	return count
 }
+
+func ClearBalanceAfter(t *testing.T, accountID string) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Model((*models.Transaction)(nil)).
+		Set("balance_after = NULL").
+		Where("customer_account_id = ?", accountID).
+		Exec(context.Background())
+
+	require.NoError(t, err)
+}
+
+func GetTransactionsForAccount(t *testing.T, accountID string) []models.Transaction {
+	t.Helper()
+
+	var transactions []models.Transaction
+	err := DB.NewSelect().
+		Model(&transactions).
+		Where("customer_account_id = ?", accountID).
+		Order("created_at", "id").
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return transactions
+}
+
+func SetIdempotencyRecordsCreatedAt(t *testing.T, accountID string, createdAt time.Time) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Model((*models.IdempotencyRecord)(nil)).
+		Set("created_at = ?", createdAt).
+		Where("customer_account_id = ?", accountID).
+		Exec(context.Background())
+
+	require.NoError(t, err)
+}
+
+type BatchResponse struct {
+	Mode  string `json:"mode"`
+	Items []struct {
+		Index       int            `json:"index"`
+		Status      int            `json:"status"`
+		Replayed    bool           `json:"replayed"`
+		Transaction map[string]any `json:"transaction"`
+		Error       map[string]any `json:"error"`
+	} `json:"items"`
+}
@@ -1,12 +1,20 @@
 package integration
 
 import (
+	"context"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gofrs/uuid/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/transactions"
+	"github.com/tiagovaldrich/accounts-api/internal/jobs"
 	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/pkg/middleware"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
 )
 
 func TestCreateTransaction(t *testing.T) {
@@ -76,7 +84,7 @@ func TestCreateTransaction(t *testing.T) {
 
 			AssertBalanceEquals(t, accountID, 0)
 
-			resp, _ := POST(t, "/transactions", map[string]any{
+			resp, body := POST(t, "/transactions", map[string]any{
 				"account_id":     accountID,
 				"operation_type": models.Withdrawal,
 				"amount":         100.00,
@@ -84,6 +92,10 @@ func TestCreateTransaction(t *testing.T) {
 
 			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
+			var response map[string]any
+			ParseJSON(t, body, &response)
+			assert.Equal(t, transactions.ErrCodeInsufficientFunds, response["code"])
+
 			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
 
 			AssertBalanceEquals(t, accountID, 0)
@@ -97,6 +109,21 @@ func TestCreateTransaction(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 		})
 
+		t.Run("with an amount below one cent should return bad request", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			resp, body := POST(t, "/transactions", map[string]any{
+				"account_id":     accountID,
+				"operation_type": models.CreditVoucher,
+				"amount":         0.001,
+			})
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
+
 		t.Run("with non-existent account should return error", func(t *testing.T) {
 			CleanupTables(t)
 
@@ -113,7 +140,7 @@ func TestCreateTransaction(t *testing.T) {
 
 func TestTransactionIdempotency(t *testing.T) {
 	t.Run("POST /transactions", func(t *testing.T) {
-		t.Run("duplicate request with same idempotency key should be rejected and only one transaction created", func(t *testing.T) {
+		t.Run("duplicate request with same idempotency key should replay the original transaction", func(t *testing.T) {
 			CleanupTables(t)
 
 			accountID := createTestAccount(t, TestDocument)
@@ -126,8 +153,9 @@ func TestTransactionIdempotency(t *testing.T) {
 				"idempotency_key": idempotencyKey,
 			}
 
-			firstResp, _ := POST(t, "/transactions", payload)
+			firstResp, firstBody := POST(t, "/transactions", payload)
 			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+			assert.Empty(t, firstResp.Header.Get(middleware.IdempotentReplayedHeader))
 
 			tx := AssertTransactionExistsWithIdempotencyKey(t, idempotencyKey)
 			assert.Equal(t, accountID, tx.CustomerAccountID.String())
@@ -136,14 +164,42 @@ func TestTransactionIdempotency(t *testing.T) {
 
 			balanceAfterFirst := GetBalance(t, accountID)
 
-			secondResp, _ := POST(t, "/transactions", payload)
-			assert.Equal(t, http.StatusConflict, secondResp.StatusCode)
+			secondResp, secondBody := POST(t, "/transactions", payload)
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+			assert.Equal(t, "true", secondResp.Header.Get(middleware.IdempotentReplayedHeader))
+			assert.JSONEq(t, string(firstBody), string(secondBody))
 
 			assert.Equal(t, 1, CountTransactionsWithIdempotencyKey(t, idempotencyKey))
 
 			AssertBalanceEquals(t, accountID, balanceAfterFirst)
 		})
 
+		t.Run("same idempotency key with a different payload should return unprocessable entity", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			idempotencyKey := "unique-key-123"
+
+			firstResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":      accountID,
+				"operation_type":  models.CreditVoucher,
+				"amount":          200.00,
+				"idempotency_key": idempotencyKey,
+			})
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			secondResp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":      accountID,
+				"operation_type":  models.CreditVoucher,
+				"amount":          300.00,
+				"idempotency_key": idempotencyKey,
+			})
+			assert.Equal(t, http.StatusUnprocessableEntity, secondResp.StatusCode)
+
+			assert.Equal(t, 1, CountTransactionsWithIdempotencyKey(t, idempotencyKey))
+			AssertBalanceEquals(t, accountID, 20000)
+		})
+
 		t.Run("different idempotency keys should create separate transactions", func(t *testing.T) {
 			CleanupTables(t)
 
@@ -171,5 +227,141 @@ func TestTransactionIdempotency(t *testing.T) {
 
 			AssertBalanceEquals(t, accountID, 20000)
 		})
+
+		t.Run("same idempotency key on different accounts should create separate transactions", func(t *testing.T) {
+			CleanupTables(t)
+
+			firstAccountID := createTestAccount(t, TestDocument)
+			secondAccountID := createTestAccount(t, TestSecondDocument)
+
+			for _, accountID := range []string{firstAccountID, secondAccountID} {
+				resp, _ := POST(t, "/transactions", map[string]any{
+					"account_id":      accountID,
+					"operation_type":  models.CreditVoucher,
+					"amount":          100.00,
+					"idempotency_key": "shared-key",
+				})
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Empty(t, resp.Header.Get(middleware.IdempotentReplayedHeader))
+			}
+
+			assert.Equal(t, 2, CountTransactionsWithIdempotencyKey(t, "shared-key"))
+			AssertBalanceEquals(t, firstAccountID, 10000)
+			AssertBalanceEquals(t, secondAccountID, 10000)
+		})
+
+		t.Run("idempotency key older than the retention window should be purged and reusable", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			payload := map[string]any{
+				"account_id":      accountID,
+				"operation_type":  models.CreditVoucher,
+				"amount":          100.00,
+				"idempotency_key": "expiring-key",
+			}
+
+			firstResp, _ := POST(t, "/transactions", payload)
+			require.Equal(t, http.StatusOK, firstResp.StatusCode)
+
+			cleanupJob := jobs.NewIdempotencyKeysCleanupJob(
+				repository.NewIdempotencyRecordRepository(DB),
+				repository.NewIdempotentRequestRepository(DB),
+				time.Hour,
+				30*24*time.Hour,
+			)
+
+			require.NoError(t, cleanupJob.Run(context.Background()))
+			assert.Equal(t, 1, CountTransactionsWithIdempotencyKey(t, "expiring-key"))
+
+			SetIdempotencyRecordsCreatedAt(t, accountID, time.Now().Add(-31*24*time.Hour))
+
+			require.NoError(t, cleanupJob.Run(context.Background()))
+			assert.Equal(t, 0, CountTransactionsWithIdempotencyKey(t, "expiring-key"))
+
+			secondResp, _ := POST(t, "/transactions", payload)
+			require.Equal(t, http.StatusOK, secondResp.StatusCode)
+			assert.Empty(t, secondResp.Header.Get(middleware.IdempotentReplayedHeader))
+
+			assert.Equal(t, 2, CountTransactionsForAccount(t, accountID))
+			AssertBalanceEquals(t, accountID, 20000)
+		})
+
+		t.Run("concurrent requests with same idempotency key should create one transaction and replay it", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+
+			payload := map[string]any{
+				"account_id":      accountID,
+				"operation_type":  models.CreditVoucher,
+				"amount":          100.00,
+				"idempotency_key": "concurrent-key",
+			}
+
+			const requests = 10
+
+			var (
+				wg       sync.WaitGroup
+				statuses = make([]int, requests)
+				replays  = make([]bool, requests)
+			)
+
+			for i := range requests {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					resp, _ := POST(t, "/transactions", payload)
+					statuses[i] = resp.StatusCode
+					replays[i] = resp.Header.Get(middleware.IdempotentReplayedHeader) == "true"
+				}()
+			}
+
+			wg.Wait()
+
+			replayed := 0
+			for i := range requests {
+				assert.Equal(t, http.StatusOK, statuses[i])
+
+				if replays[i] {
+					replayed++
+				}
+			}
+
+			assert.Equal(t, requests-1, replayed)
+			assert.Equal(t, 1, CountTransactionsWithIdempotencyKey(t, "concurrent-key"))
+			AssertBalanceEquals(t, accountID, 10000)
+		})
+
+		t.Run("request waiting too long for a concurrent one with same key should return conflict", func(t *testing.T) {
+			CleanupTables(t)
+
+			accountID := createTestAccount(t, TestDocument)
+			customerAccountID := uuid.Must(uuid.FromString(accountID))
+
+			tx, err := DB.BeginTx(context.Background(), nil)
+			require.NoError(t, err)
+			defer tx.Rollback() //nolint: errcheck
+
+			_, err = tx.NewInsert().Model(&models.IdempotencyRecord{
+				CustomerAccountID:  &customerAccountID,
+				IdempotencyKey:     "in-progress-key",
+				RequestFingerprint: "in-progress",
+			}).Exec(context.Background())
+			require.NoError(t, err)
+
+			resp, _ := POST(t, "/transactions", map[string]any{
+				"account_id":      accountID,
+				"operation_type":  models.CreditVoucher,
+				"amount":          100.00,
+				"idempotency_key": "in-progress-key",
+			})
+
+			assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			assert.Equal(t, 0, CountTransactionsForAccount(t, accountID))
+		})
 	})
 }
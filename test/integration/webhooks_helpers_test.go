@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/webhooks"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+	"github.com/tiagovaldrich/accounts-api/internal/repository"
+	"github.com/uptrace/bun"
+)
+
+const TestWebhookSecret = "test-webhook-secret-0123456789"
+
+func newWebhooksService(bunDB *bun.DB, options webhooks.Options) webhooks.Servicer {
+	return webhooks.NewService(repository.NewWebhookRepository(bunDB), options)
+}
+
+type receivedWebhook struct {
+	Header         http.Header
+	Body           []byte
+	SignatureValid bool
+}
+
+// webhookReceiver is a local endpoint webhooks are delivered to. It checks the
+// signature of each request with its secret and answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+	secret   string
+	status   atomic.Int32
+	mu       sync.Mutex
+	received []receivedWebhook
+}
+
+func newWebhookReceiver(t *testing.T, secret string) *webhookReceiver {
+	t.Helper()
+
+	receiver := &webhookReceiver{secret: secret}
+	receiver.status.Store(http.StatusOK)
+
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte(receiver.secret))
+		mac.Write([]byte(r.Header.Get(webhooks.TimestampHeader) + "."))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+		receiver.mu.Lock()
+		receiver.received = append(receiver.received, receivedWebhook{
+			Header:         r.Header.Clone(),
+			Body:           body,
+			SignatureValid: hmac.Equal([]byte(expected), []byte(r.Header.Get(webhooks.SignatureHeader))),
+		})
+		receiver.mu.Unlock()
+
+		w.WriteHeader(int(receiver.status.Load()))
+		_, _ = w.Write([]byte(http.StatusText(int(receiver.status.Load()))))
+	}))
+	t.Cleanup(receiver.Close)
+
+	return receiver
+}
+
+func (r *webhookReceiver) Received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]receivedWebhook(nil), r.received...)
+}
+
+func (r *webhookReceiver) RespondWith(status int) {
+	r.status.Store(int32(status))
+}
+
+func createTestWebhook(t *testing.T, url string, eventTypes []string, enabled bool) string {
+	t.Helper()
+
+	resp, body := POST(t, "/webhooks", map[string]any{
+		"url":         url,
+		"event_types": eventTypes,
+		"secret":      TestWebhookSecret,
+		"enabled":     enabled,
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var webhook map[string]any
+	ParseJSON(t, body, &webhook)
+
+	return webhook["id"].(string)
+}
+
+func GetWebhookDeliveries(t *testing.T, webhookID string) []models.WebhookDelivery {
+	t.Helper()
+
+	var deliveries []models.WebhookDelivery
+	err := DB.NewSelect().
+		Model(&deliveries).
+		Where("webhook_subscription_id = ?", webhookID).
+		Order("created_at").
+		Scan(context.Background())
+
+	require.NoError(t, err)
+	return deliveries
+}
+
+// MakeWebhookDeliveriesDue lets the deliveries waiting for a retry be sent now.
+func MakeWebhookDeliveriesDue(t *testing.T) {
+	t.Helper()
+
+	_, err := DB.NewUpdate().
+		Table("webhook_delivery").
+		Set("next_attempt_at = NOW() - INTERVAL '1 second'").
+		Where("status = ?", models.WebhookDeliveryPending).
+		Exec(context.Background())
+	require.NoError(t, err)
+}
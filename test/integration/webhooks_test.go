@@ -0,0 +1,287 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tiagovaldrich/accounts-api/internal/api/webhooks"
+	"github.com/tiagovaldrich/accounts-api/internal/events"
+	"github.com/tiagovaldrich/accounts-api/internal/models"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Run("should refuse invalid webhooks", func(t *testing.T) {
+		CleanupTables(t)
+
+		for name, body := range map[string]map[string]any{
+			"missing url":        {"event_types": []string{events.TransactionCreated}},
+			"invalid url":        {"url": "ftp://example.com", "event_types": []string{events.TransactionCreated}},
+			"no event types":     {"url": "https://example.com", "event_types": []string{}},
+			"unknown event type": {"url": "https://example.com", "event_types": []string{"account.deleted"}},
+			"short secret":       {"url": "https://example.com", "event_types": []string{events.TransactionCreated}, "secret": "short"},
+		} {
+			resp, respBody := POST(t, "/webhooks", body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "%s: %s", name, respBody)
+		}
+	})
+
+	t.Run("should generate a secret shown only on creation", func(t *testing.T) {
+		CleanupTables(t)
+
+		resp, body := POST(t, "/webhooks", map[string]any{
+			"url":         "https://example.com/hooks",
+			"event_types": []string{events.TransactionCreated, events.BalanceChanged},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var created map[string]any
+		ParseJSON(t, body, &created)
+		assert.True(t, strings.HasPrefix(created["secret"].(string), "whsec_"))
+		assert.Equal(t, true, created["enabled"])
+
+		resp, body = GET(t, fmt.Sprintf("/webhooks/%s", created["id"]))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var fetched map[string]any
+		ParseJSON(t, body, &fetched)
+		assert.NotContains(t, fetched, "secret")
+		assert.Equal(t, []any{events.TransactionCreated, events.BalanceChanged}, fetched["event_types"])
+	})
+
+	t.Run("should update, list and delete webhooks", func(t *testing.T) {
+		CleanupTables(t)
+
+		webhookID := createTestWebhook(t, "https://example.com/hooks", []string{events.TransactionCreated}, true)
+
+		resp, body := PATCH(t, fmt.Sprintf("/webhooks/%s", webhookID), map[string]any{
+			"url":         "https://example.com/other",
+			"event_types": []string{events.AccountCreated},
+			"enabled":     false,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var updated map[string]any
+		ParseJSON(t, body, &updated)
+		assert.Equal(t, "https://example.com/other", updated["url"])
+		assert.Equal(t, []any{events.AccountCreated}, updated["event_types"])
+		assert.Equal(t, false, updated["enabled"])
+
+		resp, body = GET(t, "/webhooks")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var list webhooks.WebhooksResponse
+		ParseJSON(t, body, &list)
+		require.Len(t, list.Webhooks, 1)
+		assert.Equal(t, webhookID, list.Webhooks[0].ID.String())
+
+		resp, _ = DELETE(t, fmt.Sprintf("/webhooks/%s", webhookID))
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, _ = GET(t, fmt.Sprintf("/webhooks/%s", webhookID))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = DELETE(t, fmt.Sprintf("/webhooks/%s", webhookID))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should enqueue events only for enabled webhooks subscribed to them", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newWebhooksService(DB, webhooks.Options{})
+
+		transactionsWebhookID := createTestWebhook(t, "https://example.com/a", []string{events.TransactionCreated}, true)
+		accountsWebhookID := createTestWebhook(t, "https://example.com/b", []string{events.AccountCreated}, true)
+		disabledWebhookID := createTestWebhook(t, "https://example.com/c", []string{events.TransactionCreated}, false)
+
+		accountID := createTestAccount(t, TestDocument)
+
+		resp, _ := POST(t, "/transactions", map[string]any{
+			"account_id":     accountID,
+			"operation_type": models.CreditVoucher,
+			"amount":         100.00,
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		dispatchOutbox(t, service)
+
+		transactionDeliveries := GetWebhookDeliveries(t, transactionsWebhookID)
+		require.Len(t, transactionDeliveries, 1)
+		assert.Equal(t, events.TransactionCreated, transactionDeliveries[0].EventType)
+		assert.Equal(t, models.WebhookDeliveryPending, transactionDeliveries[0].Status)
+
+		accountDeliveries := GetWebhookDeliveries(t, accountsWebhookID)
+		require.Len(t, accountDeliveries, 1)
+		assert.Equal(t, events.AccountCreated, accountDeliveries[0].EventType)
+
+		assert.Empty(t, GetWebhookDeliveries(t, disabledWebhookID))
+
+		var event events.Event
+		require.NoError(t, json.Unmarshal(transactionDeliveries[0].Payload, &event))
+		assert.Equal(t, transactionDeliveries[0].EventID, event.ID)
+
+		require.NoError(t, service.Publish(context.Background(), event))
+		assert.Len(t, GetWebhookDeliveries(t, transactionsWebhookID), 1)
+	})
+
+	t.Run("should deliver signed events and log the attempt", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newWebhooksService(DB, webhooks.Options{})
+		receiver := newWebhookReceiver(t, TestWebhookSecret)
+
+		webhookID := createTestWebhook(t, receiver.URL, []string{events.AccountCreated}, true)
+		accountID := createTestAccount(t, TestDocument)
+
+		dispatchOutbox(t, service)
+
+		result, err := service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Succeeded)
+
+		received := receiver.Received()
+		require.Len(t, received, 1)
+		assert.True(t, received[0].SignatureValid)
+		assert.Equal(t, events.AccountCreated, received[0].Header.Get(webhooks.EventHeader))
+
+		timestamp := received[0].Header.Get(webhooks.TimestampHeader)
+		assert.NotEmpty(t, timestamp)
+
+		var event events.Event
+		require.NoError(t, json.Unmarshal(received[0].Body, &event))
+		assert.Equal(t, event.ID.String(), received[0].Header.Get(webhooks.IDHeader))
+		assert.Equal(t, accountID, event.AccountID.String())
+
+		deliveries := GetWebhookDeliveries(t, webhookID)
+		require.Len(t, deliveries, 1)
+
+		resp, body := GET(t, fmt.Sprintf("/webhooks/%s/deliveries/%s", webhookID, deliveries[0].ID))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var delivery webhooks.DeliveryResponse
+		ParseJSON(t, body, &delivery)
+		assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		require.NotNil(t, delivery.ResponseStatus)
+		assert.Equal(t, http.StatusOK, *delivery.ResponseStatus)
+		require.Len(t, delivery.AttemptLog, 1)
+		assert.Equal(t, http.StatusOK, *delivery.AttemptLog[0].ResponseStatus)
+		assert.Equal(t, "OK", *delivery.AttemptLog[0].ResponseBody)
+
+		result, err = service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, webhooks.DeliverResult{}, result)
+		assert.Len(t, receiver.Received(), 1)
+	})
+
+	t.Run("should retry failed deliveries with backoff until they run out of attempts", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newWebhooksService(DB, webhooks.Options{MaxAttempts: 2})
+		receiver := newWebhookReceiver(t, TestWebhookSecret)
+		receiver.RespondWith(http.StatusInternalServerError)
+
+		webhookID := createTestWebhook(t, receiver.URL, []string{events.AccountCreated}, true)
+		createTestAccount(t, TestDocument)
+
+		dispatchOutbox(t, service)
+
+		result, err := service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Retried)
+
+		deliveries := GetWebhookDeliveries(t, webhookID)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.True(t, deliveries[0].NextAttemptAt.After(time.Now()))
+		require.NotNil(t, deliveries[0].LastError)
+		assert.Contains(t, *deliveries[0].LastError, "500")
+
+		result, err = service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, webhooks.DeliverResult{}, result, "a delivery waiting for its retry shouldn't be sent")
+
+		MakeWebhookDeliveriesDue(t)
+
+		result, err = service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+
+		resp, body := GET(t, fmt.Sprintf("/webhooks/%s/deliveries?status=failed", webhookID))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var list webhooks.DeliveriesResponse
+		ParseJSON(t, body, &list)
+		require.Len(t, list.Deliveries, 1)
+		assert.Equal(t, 2, list.Deliveries[0].Attempts)
+		assert.Nil(t, list.Deliveries[0].NextAttemptAt)
+		assert.Len(t, receiver.Received(), 2)
+	})
+
+	t.Run("should redeliver a failed delivery", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newWebhooksService(DB, webhooks.Options{MaxAttempts: 1})
+		receiver := newWebhookReceiver(t, TestWebhookSecret)
+		receiver.RespondWith(http.StatusServiceUnavailable)
+
+		webhookID := createTestWebhook(t, receiver.URL, []string{events.AccountCreated}, true)
+		createTestAccount(t, TestDocument)
+
+		dispatchOutbox(t, service)
+
+		result, err := service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+
+		deliveries := GetWebhookDeliveries(t, webhookID)
+		require.Len(t, deliveries, 1)
+
+		receiver.RespondWith(http.StatusNoContent)
+
+		resp, body := POST(t, fmt.Sprintf("/webhooks/%s/deliveries/%s/redeliver", webhookID, deliveries[0].ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var delivery webhooks.DeliveryResponse
+		ParseJSON(t, body, &delivery)
+		assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Nil(t, delivery.LastError)
+		require.Len(t, delivery.AttemptLog, 2)
+		assert.Equal(t, http.StatusServiceUnavailable, *delivery.AttemptLog[0].ResponseStatus)
+		assert.Equal(t, http.StatusNoContent, *delivery.AttemptLog[1].ResponseStatus)
+
+		received := receiver.Received()
+		require.Len(t, received, 2)
+		assert.Equal(t, received[0].Header.Get(webhooks.IDHeader), received[1].Header.Get(webhooks.IDHeader))
+		assert.True(t, received[1].SignatureValid)
+	})
+
+	t.Run("should return 404 for deliveries of other webhooks", func(t *testing.T) {
+		CleanupTables(t)
+
+		service := newWebhooksService(DB, webhooks.Options{})
+
+		webhookID := createTestWebhook(t, "https://example.com/a", []string{events.AccountCreated}, true)
+		otherWebhookID := createTestWebhook(t, "https://example.com/b", []string{events.AccountCreated}, true)
+		createTestAccount(t, TestDocument)
+
+		dispatchOutbox(t, service)
+
+		deliveries := GetWebhookDeliveries(t, webhookID)
+		require.Len(t, deliveries, 1)
+
+		resp, _ := GET(t, fmt.Sprintf("/webhooks/%s/deliveries/%s", otherWebhookID, deliveries[0].ID))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = POST(t, fmt.Sprintf("/webhooks/%s/deliveries/%s/redeliver", otherWebhookID, deliveries[0].ID), nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}